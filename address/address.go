@@ -0,0 +1,269 @@
+// Package address converts between the bech32 (ethm1, ethmvaloper1,
+// ethmvalcons1) and hex (0x, EIP-55 checksummed) forms of mrmint addresses.
+//
+// Account and validator operator addresses share the same 20 bytes, so they
+// convert freely into each other and into the 0x form. Consensus addresses are
+// derived from the node's ed25519 key and only convert to and from hex.
+package address
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/btcsuite/btcutil/bech32"
+	"golang.org/x/crypto/sha3"
+)
+
+// Bech32 prefixes used by the chain.
+const (
+	AccountHRP   = "ethm"
+	ValoperHRP   = "ethmvaloper"
+	ValconsHRP   = "ethmvalcons"
+	addressBytes = 20
+)
+
+// Kind is the form an address was given in.
+type Kind string
+
+const (
+	KindAccount Kind = "account"
+	KindValoper Kind = "valoper"
+	KindValcons Kind = "valcons"
+	KindHex     Kind = "hex"
+)
+
+// Address is a decoded address: its raw bytes and the form it was given in.
+type Address struct {
+	Bytes []byte
+	Kind  Kind
+}
+
+// Parse decodes a bech32 address with one of the chain prefixes or a 0x hex
+// address. Mixed case hex addresses must carry a valid EIP-55 checksum.
+func Parse(input string) (Address, error) {
+	input = strings.TrimSpace(input)
+	if strings.HasPrefix(input, "0x") || strings.HasPrefix(input, "0X") {
+		b, err := parseHex(input)
+		if err != nil {
+			return Address{}, err
+		}
+		return Address{Bytes: b, Kind: KindHex}, nil
+	}
+
+	hrp, b, err := decodeBech32(input)
+	if err != nil {
+		return Address{}, err
+	}
+	switch hrp {
+	case AccountHRP:
+		return Address{Bytes: b, Kind: KindAccount}, nil
+	case ValoperHRP:
+		return Address{Bytes: b, Kind: KindValoper}, nil
+	case ValconsHRP:
+		return Address{Bytes: b, Kind: KindValcons}, nil
+	default:
+		return Address{}, fmt.Errorf("unknown address prefix %q: must be one of %s, %s, %s or 0x", hrp, AccountHRP, ValoperHRP, ValconsHRP)
+	}
+}
+
+// Account returns the ethm1 form.
+func (a Address) Account() string { return mustBech32(AccountHRP, a.Bytes) }
+
+// Valoper returns the ethmvaloper1 form.
+func (a Address) Valoper() string { return mustBech32(ValoperHRP, a.Bytes) }
+
+// Valcons returns the ethmvalcons1 form.
+func (a Address) Valcons() string { return mustBech32(ValconsHRP, a.Bytes) }
+
+// Hex returns the EIP-55 checksummed 0x form.
+func (a Address) Hex() string { return ChecksumHex(a.Bytes) }
+
+// BechToHex converts any chain bech32 address to its checksummed 0x form.
+func BechToHex(bech32Addr string) (string, error) {
+	_, b, err := decodeBech32(bech32Addr)
+	if err != nil {
+		return "", err
+	}
+	return ChecksumHex(b), nil
+}
+
+// HexToBech converts a 0x address to bech32 with the given prefix.
+func HexToBech(hexAddr, hrp string) (string, error) {
+	b, err := parseHex(hexAddr)
+	if err != nil {
+		return "", err
+	}
+	return toBech32(hrp, b)
+}
+
+// ChecksumHex encodes 20 address bytes as an EIP-55 checksummed 0x string.
+func ChecksumHex(b []byte) string {
+	lower := hex.EncodeToString(b)
+	hash := keccak256([]byte(lower))
+
+	out := make([]byte, len(lower))
+	for i, c := range []byte(lower) {
+		// Each hex character is upper cased when the matching nibble of the
+		// hash is 8 or higher.
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if c >= 'a' && nibble&0x0f >= 8 {
+			c -= 'a' - 'A'
+		}
+		out[i] = c
+	}
+	return "0x" + string(out)
+}
+
+// FromConsensusPubKey derives the consensus address from the ed25519 key
+// printed by "ethermintd tendermint show-validator".
+func FromConsensusPubKey(pubkeyJSON string) (Address, error) {
+	key, err := pubKeyBytes(pubkeyJSON, "ed25519")
+	if err != nil {
+		return Address{}, err
+	}
+	if len(key) != 32 {
+		return Address{}, fmt.Errorf("expected a 32 byte ed25519 key, got %d bytes", len(key))
+	}
+	sum := sha256.Sum256(key)
+	return Address{Bytes: sum[:addressBytes], Kind: KindValcons}, nil
+}
+
+// FromAccountPubKey derives the account address from the eth_secp256k1 key
+// printed by "ethermintd keys show --output json".
+func FromAccountPubKey(pubkeyJSON string) (Address, error) {
+	key, err := pubKeyBytes(pubkeyJSON, "ethsecp256k1")
+	if err != nil {
+		return Address{}, err
+	}
+	uncompressed, err := decompressSecp256k1(key)
+	if err != nil {
+		return Address{}, err
+	}
+	hash := keccak256(uncompressed[1:])
+	return Address{Bytes: hash[12:], Kind: KindAccount}, nil
+}
+
+func decodeBech32(input string) (string, []byte, error) {
+	hrp, data, err := bech32.Decode(input)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to decode bech32 address: %w", err)
+	}
+	b, err := bech32.ConvertBits(data, 5, 8, false)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to convert bits: %w", err)
+	}
+	if len(b) != addressBytes {
+		return "", nil, fmt.Errorf("expected %d bytes, got %d", addressBytes, len(b))
+	}
+	return hrp, b, nil
+}
+
+func toBech32(hrp string, b []byte) (string, error) {
+	data, err := bech32.ConvertBits(b, 8, 5, true)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert bits: %w", err)
+	}
+	return bech32.Encode(hrp, data)
+}
+
+func mustBech32(hrp string, b []byte) string {
+	s, err := toBech32(hrp, b)
+	if err != nil {
+		// Only reachable with a malformed Address built outside Parse.
+		panic(err)
+	}
+	return s
+}
+
+func parseHex(input string) ([]byte, error) {
+	digits := input[2:]
+	if len(digits) != addressBytes*2 {
+		return nil, fmt.Errorf("invalid hex address %q: expected %d hex digits", input, addressBytes*2)
+	}
+	b, err := hex.DecodeString(digits)
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex address %q: %w", input, err)
+	}
+	// All lower or all upper case addresses carry no checksum.
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) {
+		if ChecksumHex(b) != "0x"+digits {
+			return nil, fmt.Errorf("invalid EIP-55 checksum for %q (expected %s)", input, ChecksumHex(b))
+		}
+	}
+	return b, nil
+}
+
+// pubKeyBytes accepts either the JSON form {"@type": ..., "key": base64} or a
+// bare base64 key.
+func pubKeyBytes(pubkey, wantType string) ([]byte, error) {
+	pubkey = strings.TrimSpace(pubkey)
+	encoded := pubkey
+	if strings.HasPrefix(pubkey, "{") {
+		var pk struct {
+			Type string `json:"@type"`
+			Key  string `json:"key"`
+		}
+		if err := json.Unmarshal([]byte(pubkey), &pk); err != nil {
+			return nil, fmt.Errorf("invalid pubkey JSON: %w", err)
+		}
+		if pk.Type != "" && !strings.Contains(strings.ToLower(pk.Type), wantType) {
+			return nil, fmt.Errorf("expected a %s pubkey, got %s", wantType, pk.Type)
+		}
+		encoded = pk.Key
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base64 pubkey: %w", err)
+	}
+	return key, nil
+}
+
+var (
+	secp256k1P, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f", 16)
+	secp256k1B    = big.NewInt(7)
+)
+
+// decompressSecp256k1 returns the 65 byte uncompressed form of a 33 byte
+// compressed secp256k1 public key.
+func decompressSecp256k1(key []byte) ([]byte, error) {
+	if len(key) == 65 && key[0] == 4 {
+		return key, nil
+	}
+	if len(key) != 33 || (key[0] != 2 && key[0] != 3) {
+		return nil, fmt.Errorf("invalid compressed secp256k1 pubkey")
+	}
+
+	x := new(big.Int).SetBytes(key[1:])
+	// y² = x³ + 7 (mod p); p ≡ 3 (mod 4) so y = (y²)^((p+1)/4).
+	y2 := new(big.Int).Exp(x, big.NewInt(3), secp256k1P)
+	y2.Add(y2, secp256k1B).Mod(y2, secp256k1P)
+	exp := new(big.Int).Add(secp256k1P, big.NewInt(1))
+	exp.Rsh(exp, 2)
+	y := new(big.Int).Exp(y2, exp, secp256k1P)
+	if new(big.Int).Exp(y, big.NewInt(2), secp256k1P).Cmp(y2) != 0 {
+		return nil, fmt.Errorf("invalid secp256k1 pubkey: point is not on the curve")
+	}
+	if y.Bit(0) != uint(key[0]&1) {
+		y.Sub(secp256k1P, y)
+	}
+
+	out := make([]byte, 65)
+	out[0] = 4
+	x.FillBytes(out[1:33])
+	y.FillBytes(out[33:])
+	return out, nil
+}
+
+func keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
@@ -0,0 +1,183 @@
+package address
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// The address of the secp256k1 private key 1, in every form.
+const (
+	keyOneHex     = "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf"
+	keyOneAccount = "ethm10e0525sfrf53yh2aljmm3sn9jq5njk7llwzyrn"
+	keyOneValoper = "ethmvaloper10e0525sfrf53yh2aljmm3sn9jq5njk7ls7ggmw"
+	keyOneValcons = "ethmvalcons10e0525sfrf53yh2aljmm3sn9jq5njk7lydm5h0"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		kind    Kind
+		wantErr string
+	}{
+		{name: "account", input: keyOneAccount, kind: KindAccount},
+		{name: "valoper", input: keyOneValoper, kind: KindValoper},
+		{name: "valcons", input: keyOneValcons, kind: KindValcons},
+		{name: "checksummed hex", input: keyOneHex, kind: KindHex},
+		{name: "lower case hex", input: strings.ToLower(keyOneHex), kind: KindHex},
+		{name: "upper case hex", input: "0x" + strings.ToUpper(keyOneHex[2:]), kind: KindHex},
+		{name: "surrounding spaces", input: "  " + keyOneAccount + "\n", kind: KindAccount},
+		{name: "bad checksum", input: "0x7e5F4552091A69125d5DfCb7b8C2659029395Bdf", wantErr: "invalid EIP-55 checksum"},
+		{name: "short hex", input: "0x7e5f4552", wantErr: "expected 40 hex digits"},
+		{name: "non hex digits", input: "0x7e5f4552091a69125d5dfcb7b8c2659029395bzz", wantErr: "invalid hex address"},
+		{name: "unknown prefix", input: "cosmos10e0525sfrf53yh2aljmm3sn9jq5njk7lyqvxq3", wantErr: "unknown address prefix"},
+		{name: "bad bech32 checksum", input: "ethm10e0525sfrf53yh2aljmm3sn9jq5njk7llwzyrq", wantErr: "failed to decode bech32"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := Parse(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Parse(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if addr.Kind != tt.kind {
+				t.Errorf("Parse(%q) kind = %s, want %s", tt.input, addr.Kind, tt.kind)
+			}
+			if got := addr.Hex(); got != keyOneHex {
+				t.Errorf("Parse(%q).Hex() = %s, want %s", tt.input, got, keyOneHex)
+			}
+		})
+	}
+}
+
+func TestParseConverts(t *testing.T) {
+	addr, err := Parse(keyOneHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := addr.Account(); got != keyOneAccount {
+		t.Errorf("Account() = %s, want %s", got, keyOneAccount)
+	}
+	if got := addr.Valoper(); got != keyOneValoper {
+		t.Errorf("Valoper() = %s, want %s", got, keyOneValoper)
+	}
+	if got := addr.Valcons(); got != keyOneValcons {
+		t.Errorf("Valcons() = %s, want %s", got, keyOneValcons)
+	}
+}
+
+func TestChecksumHex(t *testing.T) {
+	// Test vectors from EIP-55.
+	tests := []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+		"0x52908400098527886E0F7030069857D2E4169EE7",
+		"0xde709f2102306220921060314715629080e2fb77",
+	}
+	for _, want := range tests {
+		b, err := hex.DecodeString(strings.ToLower(want[2:]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ChecksumHex(b); got != want {
+			t.Errorf("ChecksumHex(%x) = %s, want %s", b, got, want)
+		}
+	}
+}
+
+func TestFromConsensusPubKey(t *testing.T) {
+	// The RFC 8032 test 1 public key; its address is the first 20 bytes of
+	// its sha256.
+	const want = "21fe31dfa154a261626bf854046fd2271b7bed4b"
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "json", input: `{"@type":"/cosmos.crypto.ed25519.PubKey","key":"11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo="}`},
+		{name: "bare base64", input: "11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo="},
+		{name: "wrong key type", input: `{"@type":"/ethermint.crypto.v1.ethsecp256k1.PubKey","key":"11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo="}`, wantErr: "expected a ed25519 pubkey"},
+		{name: "wrong length", input: "Anm+Zn753LusVaBilc6HCwcCm/zbLc4o2VnygVsW+BeY", wantErr: "expected a 32 byte ed25519 key"},
+		{name: "not base64", input: "not a key!", wantErr: "invalid base64 pubkey"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := FromConsensusPubKey(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("FromConsensusPubKey error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromConsensusPubKey error = %v", err)
+			}
+			if addr.Kind != KindValcons {
+				t.Errorf("kind = %s, want %s", addr.Kind, KindValcons)
+			}
+			if got := hex.EncodeToString(addr.Bytes); got != want {
+				t.Errorf("address = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestFromAccountPubKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		// The compressed public key of private key 1, i.e. the generator point.
+		{name: "json", input: `{"@type":"/ethermint.crypto.v1.ethsecp256k1.PubKey","key":"Anm+Zn753LusVaBilc6HCwcCm/zbLc4o2VnygVsW+BeY"}`},
+		{name: "bare base64", input: "Anm+Zn753LusVaBilc6HCwcCm/zbLc4o2VnygVsW+BeY"},
+		{name: "wrong key type", input: `{"@type":"/cosmos.crypto.ed25519.PubKey","key":"Anm+Zn753LusVaBilc6HCwcCm/zbLc4o2VnygVsW+BeY"}`, wantErr: "expected a ethsecp256k1 pubkey"},
+		{name: "bad prefix byte", input: "BXm+Zn753LusVaBilc6HCwcCm/zbLc4o2VnygVsW+BeY", wantErr: "invalid compressed secp256k1 pubkey"},
+		{name: "ed25519 sized key", input: "11qYAYKxCrfVS/7TyWQHOg7hcvPapiMlrwIaaPcHURo=", wantErr: "invalid compressed secp256k1 pubkey"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr, err := FromAccountPubKey(tt.input)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("FromAccountPubKey error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FromAccountPubKey error = %v", err)
+			}
+			if addr.Kind != KindAccount {
+				t.Errorf("kind = %s, want %s", addr.Kind, KindAccount)
+			}
+			if got := addr.Hex(); got != keyOneHex {
+				t.Errorf("address = %s, want %s", got, keyOneHex)
+			}
+		})
+	}
+}
+
+func TestHexToBech(t *testing.T) {
+	got, err := HexToBech(strings.ToLower(keyOneHex), ValoperHRP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != keyOneValoper {
+		t.Errorf("HexToBech = %s, want %s", got, keyOneValoper)
+	}
+	back, err := BechToHex(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if back != keyOneHex {
+		t.Errorf("BechToHex = %s, want %s", back, keyOneHex)
+	}
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+	"github.com/yourname/ethermint-validator-cli/denom"
+)
+
+type coinJSON struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
+// AccountBalance is the balance of one denom split into spendable and locked
+// (vesting) amounts.
+type AccountBalance struct {
+	Denom     string `json:"denom" yaml:"denom"`
+	Total     string `json:"total" yaml:"total"`
+	Spendable string `json:"spendable" yaml:"spendable"`
+	Locked    string `json:"locked" yaml:"locked"`
+	Display   string `json:"display,omitempty" yaml:"display,omitempty"`
+	// SpendableDisplay and LockedDisplay are Spendable and Locked in display
+	// units, like Display is for Total.
+	SpendableDisplay string `json:"spendable_display,omitempty" yaml:"spendable_display,omitempty"`
+	LockedDisplay    string `json:"locked_display,omitempty" yaml:"locked_display,omitempty"`
+}
+
+type AccountDelegation struct {
+	Validator string `json:"validator" yaml:"validator"`
+	Amount    string `json:"amount" yaml:"amount"`
+	Display   string `json:"display" yaml:"display"`
+}
+
+type AccountUnbonding struct {
+	Validator      string `json:"validator" yaml:"validator"`
+	CreationHeight string `json:"creation_height" yaml:"creation_height"`
+	CompletionTime string `json:"completion_time" yaml:"completion_time"`
+	Amount         string `json:"amount" yaml:"amount"`
+	Display        string `json:"display" yaml:"display"`
+}
+
+type AccountReward struct {
+	Validator string `json:"validator" yaml:"validator"`
+	Amount    string `json:"amount" yaml:"amount"`
+	Display   string `json:"display" yaml:"display"`
+}
+
+// AccountOverview is everything the account command reports for a wallet.
+type AccountOverview struct {
+	Address          string              `json:"address" yaml:"address"`
+	EthAddress       string              `json:"eth_address" yaml:"eth_address"`
+	ValidatorAddress string              `json:"validator_address" yaml:"validator_address"`
+	AccountNumber    string              `json:"account_number" yaml:"account_number"`
+	Sequence         string              `json:"sequence" yaml:"sequence"`
+	Balances         []AccountBalance    `json:"balances" yaml:"balances"`
+	Delegations      []AccountDelegation `json:"delegations" yaml:"delegations"`
+	Unbondings       []AccountUnbonding  `json:"unbondings" yaml:"unbondings"`
+	Rewards          []AccountReward     `json:"rewards" yaml:"rewards"`
+	Commission       []AccountBalance    `json:"commission" yaml:"commission"`
+}
+
+func accountCmd() *cobra.Command {
+	var mynode string
+	var jsonOutput bool
+
+	cmd := &cobra.Command{
+		Use:   "account",
+		Short: "Show a full overview of the validator wallet",
+		Long: `Shows all balances (spendable vs locked), delegations, unbonding entries,
+outstanding rewards, validator commission and the account number and sequence
+of the validator wallet, in both ethm1 and 0x form.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if jsonOutput {
+				outputFormat = outputJSON
+			}
+			return accountCmdLogic(mynode)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Print the overview as JSON")
+	cmd.Flags().MarkDeprecated("json", "use --output json instead")
+	return cmd
+}
+
+func accountCmdLogic(mynode string) error {
+	err := godotenv.Load(filepath.Join(mynode, ".env"))
+	if err != nil {
+		return envFileError(filepath.Join(mynode, ".env"), err)
+	}
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
+
+	overview, err := getAccountOverview(mynode, "tcp://localhost:"+rpcPort)
+	if err != nil {
+		return err
+	}
+
+	return render(overview)
+}
+
+func getAccountOverview(mynode, node string) (AccountOverview, error) {
+	var overview AccountOverview
+	var err error
+
+	if overview.Address, err = keyAddress(mynode); err != nil {
+		return overview, err
+	}
+	if overview.EthAddress, err = Bech32ToEthAddress(overview.Address); err != nil {
+		return overview, err
+	}
+	if overview.ValidatorAddress, err = validatorOperatorAddress(mynode); err != nil {
+		return overview, err
+	}
+	if overview.AccountNumber, overview.Sequence, err = queryAccountNumberAndSequence(overview.Address, node); err != nil {
+		log.Warnf("⚠️ Could not fetch account number and sequence (the account may not exist on chain yet): %v", err)
+	}
+
+	md := denomMetadata(node)
+
+	total, err := queryBalances(overview.Address, node, "balances")
+	if err != nil {
+		return overview, err
+	}
+	spendable, err := queryBalances(overview.Address, node, "spendable-balances")
+	if err != nil {
+		log.Warnf("⚠️ Could not query spendable balances, treating all balances as spendable: %v", err)
+		spendable = total
+	}
+	overview.Balances = splitBalances(total, spendable, md)
+
+	if resp, err := queryDelegations(overview.Address, node); err != nil {
+		log.Warnf("⚠️ Could not query delegations: %v", err)
+	} else {
+		for _, d := range resp.DelegationResponses {
+			overview.Delegations = append(overview.Delegations, AccountDelegation{
+				Validator: d.Delegation.ValidatorAddress,
+				Amount:    d.Balance.Amount + d.Balance.Denom,
+				Display:   md.FormatString(d.Balance.Amount),
+			})
+		}
+	}
+
+	if resp, err := queryUnbondings(overview.Address, node); err != nil {
+		log.Warnf("⚠️ Could not query unbonding delegations: %v", err)
+	} else {
+		for _, u := range resp.UnbondingResponses {
+			for _, e := range u.Entries {
+				overview.Unbondings = append(overview.Unbondings, AccountUnbonding{
+					Validator:      u.ValidatorAddress,
+					CreationHeight: e.CreationHeight,
+					CompletionTime: e.CompletionTime,
+					Amount:         e.Balance + md.Base,
+					Display:        md.FormatString(e.Balance),
+				})
+			}
+		}
+	}
+
+	if output, err := runCmdCaptureOutput(Mrmintd, "query", "distribution", "rewards", overview.Address, "--node", node, "--output", "json"); err != nil {
+		log.Warnf("⚠️ Could not query outstanding rewards: %s", output)
+	} else {
+		var resp struct {
+			Rewards []struct {
+				ValidatorAddress string     `json:"validator_address"`
+				Reward           []coinJSON `json:"reward"`
+			} `json:"rewards"`
+		}
+		if err := parseQueryJSON(output, &resp); err != nil {
+			log.Warnf("⚠️ Could not parse outstanding rewards: %v", err)
+		} else {
+			for _, r := range resp.Rewards {
+				for _, c := range r.Reward {
+					amount := truncateDec(c.Amount)
+					overview.Rewards = append(overview.Rewards, AccountReward{
+						Validator: r.ValidatorAddress,
+						Amount:    amount + c.Denom,
+						Display:   displayFor(amount, c.Denom, md),
+					})
+				}
+			}
+		}
+	}
+
+	if output, err := runCmdCaptureOutput(Mrmintd, "query", "distribution", "commission", overview.ValidatorAddress, "--node", node, "--output", "json"); err != nil {
+		log.Debugf("No validator commission found: %s", output)
+	} else {
+		// Newer SDK versions nest the coins one level deeper.
+		var resp struct {
+			Commission json.RawMessage `json:"commission"`
+		}
+		var coins []coinJSON
+		if err := parseQueryJSON(output, &resp); err != nil {
+			log.Warnf("⚠️ Could not parse validator commission: %v", err)
+		} else if err := json.Unmarshal(resp.Commission, &coins); err != nil {
+			var nested struct {
+				Commission []coinJSON `json:"commission"`
+			}
+			if err := json.Unmarshal(resp.Commission, &nested); err != nil {
+				log.Warnf("⚠️ Could not parse validator commission: %v", err)
+			}
+			coins = nested.Commission
+		}
+		for _, c := range coins {
+			amount := truncateDec(c.Amount)
+			display := displayFor(amount, c.Denom, md)
+			overview.Commission = append(overview.Commission, AccountBalance{
+				Denom:            c.Denom,
+				Total:            amount,
+				Spendable:        amount,
+				Locked:           "0",
+				Display:          display,
+				SpendableDisplay: display,
+				LockedDisplay:    displayFor("0", c.Denom, md),
+			})
+		}
+	}
+
+	return overview, nil
+}
+
+// queryBalances fetches every page of the balances of address. kind is
+// "balances" or "spendable-balances".
+func queryBalances(address, node, kind string) ([]coinJSON, error) {
+	var all []coinJSON
+	err := queryAllPages(kind+" of "+address, func(data json.RawMessage) (int, error) {
+		var page struct {
+			Balances []coinJSON `json:"balances"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return 0, err
+		}
+		all = append(all, page.Balances...)
+		return len(page.Balances), nil
+	}, "query", "bank", kind, address, "--node", node, "--output", "json")
+	return all, err
+}
+
+func splitBalances(total, spendable []coinJSON, md denom.Metadata) []AccountBalance {
+	spendableByDenom := map[string]string{}
+	for _, c := range spendable {
+		spendableByDenom[c.Denom] = c.Amount
+	}
+
+	var balances []AccountBalance
+	for _, c := range total {
+		totalAmount, ok := new(big.Int).SetString(c.Amount, 10)
+		if !ok {
+			continue
+		}
+		spendableAmount, ok := new(big.Int).SetString(spendableByDenom[c.Denom], 10)
+		if !ok {
+			spendableAmount = big.NewInt(0)
+		}
+		locked := new(big.Int).Sub(totalAmount, spendableAmount)
+
+		balances = append(balances, AccountBalance{
+			Denom:            c.Denom,
+			Total:            totalAmount.String(),
+			Spendable:        spendableAmount.String(),
+			Locked:           locked.String(),
+			Display:          displayFor(totalAmount.String(), c.Denom, md),
+			SpendableDisplay: displayFor(spendableAmount.String(), c.Denom, md),
+			LockedDisplay:    displayFor(locked.String(), c.Denom, md),
+		})
+	}
+	return balances
+}
+
+// parseQueryJSON decodes the JSON printed by a query command whose stdout and
+// stderr are merged, skipping any warnings printed before it.
+func parseQueryJSON(output string, v any) error {
+	data, err := extractJSON(output)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// truncateDec drops the fractional part of a DecCoin amount, e.g. rewards
+// reported as "1234.500000000000000000".
+func truncateDec(amount string) string {
+	whole, _, _ := strings.Cut(amount, ".")
+	if whole == "" {
+		return "0"
+	}
+	return whole
+}
+
+func displayFor(amount, coinDenom string, md denom.Metadata) string {
+	if coinDenom != md.Base {
+		return amount + coinDenom
+	}
+	return md.FormatString(amount)
+}
+
+func (o AccountOverview) renderTable(w io.Writer) {
+	fmt.Fprintf(w, "Address\t%s\n", o.Address)
+	fmt.Fprintf(w, "Ethereum (0x) address\t%s\n", o.EthAddress)
+	fmt.Fprintf(w, "Validator address\t%s\n", o.ValidatorAddress)
+	fmt.Fprintf(w, "Account number\t%s\n", o.AccountNumber)
+	fmt.Fprintf(w, "Sequence\t%s\n", o.Sequence)
+
+	fmt.Fprintln(w, "\nBALANCES\tTOTAL\tSPENDABLE\tLOCKED")
+	for _, b := range o.Balances {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", b.Denom, b.Display, b.SpendableDisplay, b.LockedDisplay)
+	}
+
+	fmt.Fprintln(w, "\nDELEGATIONS\tAMOUNT")
+	for _, d := range o.Delegations {
+		fmt.Fprintf(w, "%s\t%s\n", d.Validator, d.Display)
+	}
+
+	fmt.Fprintln(w, "\nUNBONDING\tCREATION HEIGHT\tCOMPLETION TIME\tAMOUNT")
+	for _, u := range o.Unbondings {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", u.Validator, u.CreationHeight, u.CompletionTime, u.Display)
+	}
+
+	fmt.Fprintln(w, "\nOUTSTANDING REWARDS\tAMOUNT")
+	for _, r := range o.Rewards {
+		fmt.Fprintf(w, "%s\t%s\n", r.Validator, r.Display)
+	}
+
+	fmt.Fprintln(w, "\nVALIDATOR COMMISSION\tAMOUNT")
+	for _, c := range o.Commission {
+		fmt.Fprintf(w, "%s\t%s\n", c.Denom, c.Display)
+	}
+}
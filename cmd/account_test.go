@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func TestQueryBalancesPaginates(t *testing.T) {
+	denoms := make([]string, queryPageLimit+3)
+	for i := range denoms {
+		denoms[i] = fmt.Sprintf("ibc/%03d", i)
+	}
+	fake := &fakeTransport{run: func(args []string) (string, error) {
+		offset, _ := strconv.Atoi(flagValue(args, "--offset"))
+		end := min(offset+queryPageLimit, len(denoms))
+		out := `{"balances":[`
+		for i := offset; i < end; i++ {
+			if i > offset {
+				out += ","
+			}
+			out += fmt.Sprintf(`{"denom":%q,"amount":"%d"}`, denoms[i], i)
+		}
+		nextKey := "null"
+		if end < len(denoms) {
+			nextKey = `"aWJjLzEwMA=="`
+		}
+		return out + `],"pagination":{"next_key":` + nextKey + `}}`, nil
+	}}
+	useTransport(t, fake)
+
+	got, err := queryBalances("ethm1wallet", "tcp://localhost:26657", "spendable-balances")
+	if err != nil {
+		t.Fatalf("queryBalances: %v", err)
+	}
+	if len(got) != len(denoms) {
+		t.Fatalf("got %d balances, want %d", len(got), len(denoms))
+	}
+	for i, c := range got {
+		if c.Denom != denoms[i] {
+			t.Fatalf("balance %d is %s, want %s", i, c.Denom, denoms[i])
+		}
+	}
+	if kind := fake.calls[0][2]; kind != "spendable-balances" {
+		t.Errorf("queried %s, want spendable-balances", kind)
+	}
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"github.com/yourname/ethermint-validator-cli/address"
+)
+
+// AddressResult is one converted address. Consensus addresses only have the
+// valcons and consensus hex forms.
+type AddressResult struct {
+	Input        string `json:"input" yaml:"input"`
+	Kind         string `json:"kind" yaml:"kind"`
+	Account      string `json:"account,omitempty" yaml:"account,omitempty"`
+	Valoper      string `json:"valoper,omitempty" yaml:"valoper,omitempty"`
+	Hex          string `json:"hex,omitempty" yaml:"hex,omitempty"`
+	Valcons      string `json:"valcons,omitempty" yaml:"valcons,omitempty"`
+	ConsensusHex string `json:"consensus_hex,omitempty" yaml:"consensus_hex,omitempty"`
+	Error        string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// AddressesResult is the result of the address command.
+type AddressesResult struct {
+	Addresses []AddressResult `json:"addresses" yaml:"addresses"`
+}
+
+func (r AddressesResult) renderTable(w io.Writer) {
+	for i, a := range r.Addresses {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "Input\t%s (%s)\n", a.Input, a.Kind)
+		if a.Error != "" {
+			fmt.Fprintf(w, "Error\t%s\n", a.Error)
+			continue
+		}
+		if a.Account != "" {
+			fmt.Fprintf(w, "Account\t%s\n", a.Account)
+			fmt.Fprintf(w, "Validator operator\t%s\n", a.Valoper)
+			fmt.Fprintf(w, "Ethereum (0x)\t%s\n", a.Hex)
+		}
+		if a.Valcons != "" {
+			fmt.Fprintf(w, "Validator consensus\t%s\n", a.Valcons)
+			fmt.Fprintf(w, "Consensus hex\t%s\n", a.ConsensusHex)
+		}
+	}
+}
+
+func addressCmd() *cobra.Command {
+	var mynode string
+	var key string
+	var pubkey string
+
+	cmd := &cobra.Command{
+		Use:   "address [address...]",
+		Short: "Convert addresses between ethm1, ethmvaloper1, ethmvalcons1 and 0x",
+		Long: `Converts each address into every other form. Accepts ethm1, ethmvaloper1,
+ethmvalcons1 and 0x addresses; mixed case 0x addresses must have a valid EIP-55
+checksum.
+
+With --mynode the account, operator and consensus addresses of the node are
+derived from its key (--key, default: the node name) and its consensus key.
+With --pubkey the addresses are derived from an eth_secp256k1 account pubkey or
+an ed25519 consensus pubkey, in JSON or bare base64 form.
+
+Without arguments (or with "-") addresses are read from stdin, one per line.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return addressCmdLogic(args, mynode, key, pubkey)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Derive the addresses of this node")
+	cmd.Flags().StringVar(&key, "key", "", "Key name to derive from (default: the node name, requires --mynode)")
+	cmd.Flags().StringVar(&pubkey, "pubkey", "", "Account or consensus pubkey to derive from")
+	return cmd
+}
+
+func addressCmdLogic(args []string, mynode, key, pubkey string) error {
+	if key != "" && mynode == "" {
+		return fmt.Errorf("--key requires --mynode")
+	}
+
+	var result AddressesResult
+	switch {
+	case mynode != "":
+		result.Addresses = append(result.Addresses, nodeAddresses(mynode, key)...)
+	case pubkey != "":
+		result.Addresses = append(result.Addresses, pubkeyAddress(pubkey))
+	case len(args) == 0 || (len(args) == 1 && args[0] == "-"):
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			result.Addresses = append(result.Addresses, convertAddress(line))
+		}
+		if err := scanner.Err(); err != nil {
+			return fmt.Errorf("failed to read addresses from stdin: %w", err)
+		}
+	default:
+		for _, arg := range args {
+			result.Addresses = append(result.Addresses, convertAddress(arg))
+		}
+	}
+
+	if err := render(result); err != nil {
+		return err
+	}
+	failed := 0
+	for _, a := range result.Addresses {
+		if a.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d addresses could not be converted", failed, len(result.Addresses))
+	}
+	return nil
+}
+
+func convertAddress(input string) AddressResult {
+	a, err := address.Parse(input)
+	if err != nil {
+		return AddressResult{Input: input, Kind: "invalid", Error: err.Error()}
+	}
+	return addressResult(input, a)
+}
+
+func addressResult(input string, a address.Address) AddressResult {
+	r := AddressResult{Input: input, Kind: string(a.Kind)}
+	if a.Kind == address.KindValcons {
+		r.Valcons = a.Valcons()
+		r.ConsensusHex = strings.ToUpper(strings.TrimPrefix(a.Hex(), "0x"))
+		return r
+	}
+	r.Account = a.Account()
+	r.Valoper = a.Valoper()
+	r.Hex = a.Hex()
+	return r
+}
+
+func pubkeyAddress(pubkey string) AddressResult {
+	derive := address.FromAccountPubKey
+	if strings.Contains(strings.ToLower(pubkey), "ed25519") {
+		derive = address.FromConsensusPubKey
+	}
+	a, err := derive(pubkey)
+	if err != nil && !strings.HasPrefix(strings.TrimSpace(pubkey), "{") {
+		// A bare base64 key gives no type; fall back to the consensus key.
+		a, err = address.FromConsensusPubKey(pubkey)
+	}
+	if err != nil {
+		return AddressResult{Input: pubkey, Kind: "pubkey", Error: err.Error()}
+	}
+	return addressResult(pubkey, a)
+}
+
+func nodeAddresses(mynode, key string) []AddressResult {
+	if key == "" {
+		key = nodeKeyName(mynode)
+	}
+
+	var results []AddressResult
+	output, err := runCmdCaptureOutput(Mrmintd, "keys", "show", key, "-a", "--home", mynode, "--keyring-backend", "test")
+	if err != nil {
+		results = append(results, AddressResult{Input: key, Kind: "key", Error: strings.TrimSpace(output)})
+	} else {
+		results = append(results, convertAddress(strings.TrimSpace(output)))
+	}
+
+	output, err = runCmdCaptureOutput(Mrmintd, "tendermint", "show-validator", "--home", mynode)
+	if err != nil {
+		log.Warnf("⚠️ Could not read the consensus key of %s: %s", mynode, output)
+		return results
+	}
+	results = append(results, pubkeyAddress(lastLine(output)))
+	return results
+}
+
+// lastLine drops any warnings ethermintd prints before the value.
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
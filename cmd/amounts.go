@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/yourname/ethermint-validator-cli/denom"
+)
+
+// amountHelp is the example shown in the help text of every amount flag.
+const amountHelp = "e.g., 12.5MNT or 12500000000000000000mnt"
+
+// implausibleDisplayAmount is the number of whole display units above which
+// an amount flag is rejected as a probable base unit value.
+const implausibleDisplayAmount = 1_000_000_000_000
+
+var cachedDenomMetadata *denom.Metadata
+
+// denomMetadata returns the chain's metadata for the mnt denom, falling back
+// to denom.Default when the chain does not publish any or is unreachable.
+func denomMetadata(node string) denom.Metadata {
+	if cachedDenomMetadata != nil {
+		return *cachedDenomMetadata
+	}
+
+	md := denom.Default
+	output, err := runCmdCaptureOutput(Mrmintd, "query", "bank", "denom-metadata", "--node", node, "--output", "json")
+	if err != nil {
+		log.Debugf("Could not query denom metadata, using defaults: %s", output)
+		return md
+	}
+	raw, err := extractJSON(output)
+	if err != nil {
+		log.Debugf("Could not read denom metadata, using defaults: %v", err)
+		return md
+	}
+	if chainMd, ok, err := denom.ParseMetadata(raw, denom.Default.Base); err == nil && ok {
+		md = chainMd
+	}
+
+	cachedDenomMetadata = &md
+	return md
+}
+
+// parseAmountFlag validates a user supplied amount against the chain's denom
+// metadata and returns it in the base unit form expected by ethermintd.
+func parseAmountFlag(flag, amount, node string) (string, error) {
+	md := denomMetadata(node)
+	coin, err := denom.Parse(amount, md)
+	if err != nil {
+		return "", &ConfigError{Msg: "invalid --" + flag, Err: err}
+	}
+	if coin.Amount.Sign() <= 0 {
+		return "", &ConfigError{Msg: "invalid --" + flag + ": amount must be greater than zero"}
+	}
+	// A base unit value with the display suffix would turn into a 10^18
+	// times larger transaction; catch that.
+	if coin.Amount.Cmp(md.FromDisplay(implausibleDisplayAmount)) >= 0 {
+		return "", &ConfigError{
+			Msg:  fmt.Sprintf("invalid --%s: %s is implausibly large", flag, md.Format(coin.Amount)),
+			Hint: fmt.Sprintf("use the %s suffix for base units, e.g. %s%s", md.Base, strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(amount), md.Display)), md.Base),
+		}
+	}
+	return coin.String(), nil
+}
+
+// parseAmountListFlag is parseAmountFlag for comma separated coin lists such
+// as fee allowance spend limits.
+func parseAmountListFlag(flag, amounts, node string) (string, error) {
+	var coins []string
+	for _, amount := range strings.Split(amounts, ",") {
+		coin, err := parseAmountFlag(flag, amount, node)
+		if err != nil {
+			return "", err
+		}
+		coins = append(coins, coin)
+	}
+	return strings.Join(coins, ","), nil
+}
+
+// minStakeFundAmount returns the configured minimum stake in base units.
+func minStakeFundAmount(md denom.Metadata) (*big.Int, error) {
+	coin, err := denom.Parse(configCliParams.MinStakeFund.String()+md.Display, md)
+	if err != nil {
+		return nil, &ConfigError{Msg: fmt.Sprintf("invalid minStakeFund %q in remote config", configCliParams.MinStakeFund), Err: err}
+	}
+	return coin.Amount, nil
+}
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+	"github.com/yourname/ethermint-validator-cli/address"
+)
+
+// authzMsgTypes maps the short names accepted by --msg to the message type
+// URLs granted through authz.
+var authzMsgTypes = map[string]string{
+	"vote":                "/cosmos.gov.v1.MsgVote",
+	"withdraw-rewards":    "/cosmos.distribution.v1beta1.MsgWithdrawDelegatorReward",
+	"withdraw-commission": "/cosmos.distribution.v1beta1.MsgWithdrawValidatorCommission",
+	"delegate":            "/cosmos.staking.v1beta1.MsgDelegate",
+	"unjail":              "/cosmos.slashing.v1beta1.MsgUnjail",
+}
+
+func authzMsgNames() []string {
+	names := make([]string, 0, len(authzMsgTypes))
+	for name := range authzMsgTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// addGranteeFlag adds the --as-grantee and --granter flags to commands that
+// can run through an authz grant.
+func addGranteeFlag(cmd *cobra.Command, opts *TxOptions) {
+	cmd.Flags().StringVar(&opts.AsGrantee, "as-grantee", "", "Hot key that executes the transaction through an authz grant from the validator owner key (wraps it in MsgExec)")
+	cmd.Flags().StringVar(&opts.Granter, "granter", "", "Address (ethm1...) of the validator owner key that issued the grant (required with --as-grantee)")
+}
+
+func authzCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "authz",
+		Short: "Grant a hot operator key permissions on behalf of the validator owner key",
+		Long: `Grants and revokes Cosmos authz permissions from the validator owner key to a
+separate hot key. Once granted, vote-proposal, withdraw-rewards, unjail and
+self-delegate can be run with --as-grantee <hot-key> --granter <owner-address>,
+so the owner key never has to sit next to the node. withdraw-commission is used
+by 'withdraw-rewards --commission'.
+
+Supported --msg values: ` + strings.Join(authzMsgNames(), ", "),
+	}
+	cmd.AddCommand(authzGrantCmd(), authzRevokeCmd(), authzListCmd())
+	return cmd
+}
+
+func authzGrantCmd() *cobra.Command {
+	var mynode string
+	var grantee string
+	var msgs []string
+	var expireIn time.Duration
+	var txOpts TxOptions
+
+	cmd := &cobra.Command{
+		Use:   "grant",
+		Short: "Grant permissions from the validator owner key to a hot key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return authzGrantCmdLogic(mynode, grantee, msgs, expireIn, txOpts)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (key name of the validator owner)")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&grantee, "grantee", "", "Address (ethm1...) of the hot key receiving the permissions")
+	cmd.MarkFlagRequired("grantee")
+	cmd.Flags().StringSliceVar(&msgs, "msg", authzMsgNames(), "Permissions to grant: "+strings.Join(authzMsgNames(), ", "))
+	cmd.Flags().DurationVar(&expireIn, "expire-in", 365*24*time.Hour, "How long the grants stay valid (e.g. 720h)")
+	addTxFlags(cmd, &txOpts)
+	return cmd
+}
+
+func authzGrantCmdLogic(mynode, grantee string, msgs []string, expireIn time.Duration, txOpts TxOptions) error {
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
+
+	err := godotenv.Load(filepath.Join(mynode, ".env"))
+	if err != nil {
+		return envFileError(filepath.Join(mynode, ".env"), err)
+	}
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
+	rpcNode := "tcp://localhost:" + rpcPort
+
+	if err := validateAuthzMsgs(msgs); err != nil {
+		return err
+	}
+	if txOpts.GenerateOnly && len(msgs) > 1 && txOpts.OutputFile != "" {
+		return &ConfigError{Msg: "--output-file can only be used when granting a single --msg"}
+	}
+
+	expiration := strconv.FormatInt(time.Now().Add(expireIn).Unix(), 10)
+
+	var outputs []string
+	for _, msg := range msgs {
+		txArgs := []string{"tx", "authz", "grant", grantee}
+		if msg == "delegate" {
+			// Restrict delegation grants to the validator's own operator address.
+			valoper, err := txOperatorAddress(mynode, txOpts)
+			if err != nil {
+				return err
+			}
+			txArgs = append(txArgs, "delegate", "--allowed-validators", valoper)
+		} else {
+			txArgs = append(txArgs, "generic", "--msg-type", authzMsgTypes[msg])
+		}
+		txArgs = append(txArgs,
+			"--expiration", expiration,
+			"--from", nodeKeyName(mynode),
+			"--home", mynode,
+			"--keyring-backend", "test",
+			"--chain-id", configCliParams.ChaindId,
+			"--gas", "auto",
+			"--gas-prices", "7mnt",
+			"--gas-adjustment", "1.3",
+			"--node", rpcNode,
+		)
+
+		log.Infof("Granting '%s' (%s) to %s", msg, authzMsgTypes[msg], grantee)
+		if txOpts.GenerateOnly {
+			if err := generateUnsignedTx(mynode, txOpts, "authz-grant-"+msg, rpcNode, Mrmintd, txArgs...); err != nil {
+				return err
+			}
+			continue
+		}
+
+		output, err := runCmdCaptureOutput(Mrmintd, append(txOpts.apply(txArgs), "--yes")...)
+		if err != nil {
+			log.Errorf("❌ Failed to grant '%s' to %s: %s\nOutput: %s", msg, grantee, err, output)
+			return txError("authz grant", output, err)
+		}
+		log.Infof("✅ Grant '%s' sent successfully! Transaction output:\n%s", msg, output)
+		outputs = append(outputs, output)
+	}
+
+	log.Infof("The hot key can now run the granted commands with --as-grantee <hot-key-name> --granter <owner-address>.")
+	return renderTxOutputs("authz grant", outputs)
+}
+
+func authzRevokeCmd() *cobra.Command {
+	var mynode string
+	var grantee string
+	var msgs []string
+	var txOpts TxOptions
+
+	cmd := &cobra.Command{
+		Use:   "revoke",
+		Short: "Revoke permissions previously granted to a hot key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return authzRevokeCmdLogic(mynode, grantee, msgs, txOpts)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (key name of the validator owner)")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&grantee, "grantee", "", "Address (ethm1...) of the hot key losing the permissions")
+	cmd.MarkFlagRequired("grantee")
+	cmd.Flags().StringSliceVar(&msgs, "msg", authzMsgNames(), "Permissions to revoke: "+strings.Join(authzMsgNames(), ", "))
+	addTxFlags(cmd, &txOpts)
+	return cmd
+}
+
+func authzRevokeCmdLogic(mynode, grantee string, msgs []string, txOpts TxOptions) error {
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
+
+	err := godotenv.Load(filepath.Join(mynode, ".env"))
+	if err != nil {
+		return envFileError(filepath.Join(mynode, ".env"), err)
+	}
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
+	rpcNode := "tcp://localhost:" + rpcPort
+
+	if err := validateAuthzMsgs(msgs); err != nil {
+		return err
+	}
+	if txOpts.GenerateOnly && len(msgs) > 1 && txOpts.OutputFile != "" {
+		return &ConfigError{Msg: "--output-file can only be used when revoking a single --msg"}
+	}
+
+	var outputs []string
+	for _, msg := range msgs {
+		txArgs := []string{
+			"tx", "authz", "revoke", grantee, authzMsgTypes[msg],
+			"--from", nodeKeyName(mynode),
+			"--home", mynode,
+			"--keyring-backend", "test",
+			"--chain-id", configCliParams.ChaindId,
+			"--gas", "auto",
+			"--gas-prices", "7mnt",
+			"--gas-adjustment", "1.3",
+			"--node", rpcNode,
+		}
+
+		log.Infof("Revoking '%s' (%s) from %s", msg, authzMsgTypes[msg], grantee)
+		if txOpts.GenerateOnly {
+			if err := generateUnsignedTx(mynode, txOpts, "authz-revoke-"+msg, rpcNode, Mrmintd, txArgs...); err != nil {
+				return err
+			}
+			continue
+		}
+
+		output, err := runCmdCaptureOutput(Mrmintd, append(txOpts.apply(txArgs), "--yes")...)
+		if err != nil {
+			log.Errorf("❌ Failed to revoke '%s' from %s: %s\nOutput: %s", msg, grantee, err, output)
+			return txError("authz revoke", output, err)
+		}
+		log.Infof("✅ Revoke '%s' sent successfully! Transaction output:\n%s", msg, output)
+		outputs = append(outputs, output)
+	}
+	return renderTxOutputs("authz revoke", outputs)
+}
+
+func authzListCmd() *cobra.Command {
+	var mynode string
+	var grantee string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the grants given by the validator owner key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return authzListCmdLogic(mynode, grantee)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (key name of the validator owner)")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&grantee, "grantee", "", "Only show grants to this address")
+	return cmd
+}
+
+func authzListCmdLogic(mynode, grantee string) error {
+	err := godotenv.Load(filepath.Join(mynode, ".env"))
+	if err != nil {
+		return envFileError(filepath.Join(mynode, ".env"), err)
+	}
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
+
+	granter, err := keyAddress(mynode)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"query", "authz", "grants-by-granter", granter}
+	if grantee != "" {
+		args = []string{"query", "authz", "grants", granter, grantee}
+	}
+	args = append(args, "--node", "tcp://localhost:"+rpcPort, "--output", "json")
+
+	output, err := runCmdCaptureOutput(Mrmintd, args...)
+	if err != nil {
+		log.Errorf("❌ Failed to query grants for %s: %s\nOutput: %s", granter, err, output)
+		return &ChainQueryError{Query: "query grants of " + granter, Err: err}
+	}
+
+	return renderRaw(output)
+}
+
+func validateAuthzMsgs(msgs []string) error {
+	if len(msgs) == 0 {
+		return &ConfigError{Msg: "at least one --msg is required"}
+	}
+	for _, msg := range msgs {
+		if _, ok := authzMsgTypes[msg]; !ok {
+			return &ConfigError{Msg: fmt.Sprintf("unknown --msg %q. Must be one of: %s", msg, strings.Join(authzMsgNames(), ", "))}
+		}
+	}
+	return nil
+}
+
+// validatorOperatorAddress returns the ethmvaloper1... address of the node key.
+func validatorOperatorAddress(mynode string) (string, error) {
+	return txOperatorAddress(mynode, TxOptions{})
+}
+
+// granterOperatorAddress returns the ethmvaloper1... form of the --granter
+// address, so grantee commands never need the owner key in the keyring.
+func granterOperatorAddress(granter string) (string, error) {
+	addr, err := address.Parse(granter)
+	if err != nil {
+		return "", &ConfigError{Msg: fmt.Sprintf("invalid --granter %q", granter), Err: err}
+	}
+	return addr.Valoper(), nil
+}
+
+// execAsGrantee generates the transaction on behalf of the validator owner
+// (the --granter address) and executes it through MsgExec signed by the hot
+// grantee key.
+func execAsGrantee(mynode string, txOpts TxOptions, kind, rpcNode string, txArgs ...string) error {
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
+	granter := txOpts.Granter
+
+	// The owner key only needs to be known by address, it is never used to sign.
+	innerArgs := replaceFlagValue(txArgs, "--from", granter)
+	output, err := runCmdCaptureOutput(Mrmintd, append(innerArgs, "--generate-only")...)
+	if err != nil {
+		log.Errorf("❌ Failed to generate %s message for %s: %s\nOutput: %s", kind, granter, err, output)
+		return &RuntimeError{Msg: "failed to generate the " + kind + " message", Err: err}
+	}
+	innerTx, err := extractJSON(output)
+	if err != nil {
+		return &RuntimeError{Msg: "failed to read the generated " + kind + " message", Err: err}
+	}
+
+	innerPath, err := writeTempTx(innerTx)
+	if err != nil {
+		return err
+	}
+	defer transport.Remove(innerPath)
+
+	execArgs := []string{
+		"tx", "authz", "exec", innerPath,
+		"--from", txOpts.AsGrantee,
+		"--home", mynode,
+		"--keyring-backend", "test",
+		"--chain-id", configCliParams.ChaindId,
+		"--gas", "auto",
+		"--gas-prices", "7mnt",
+		"--gas-adjustment", "1.3",
+		"--node", rpcNode,
+	}
+
+	log.Infof("Executing %s for granter %s as grantee '%s'", kind, granter, txOpts.AsGrantee)
+	if txOpts.GenerateOnly {
+		txOpts.Signer = txOpts.AsGrantee
+		return generateUnsignedTx(mynode, txOpts, "exec-"+kind, rpcNode, Mrmintd, execArgs...)
+	}
+
+	output, err = runCmdCaptureOutput(Mrmintd, append(txOpts.apply(execArgs), "--yes")...)
+	if err != nil {
+		log.Errorf("❌ Failed to execute %s as grantee '%s': %s\nOutput: %s", kind, txOpts.AsGrantee, err, output)
+		log.Warnf("Please ensure the grant exists ('mrmintchain authz list --mynode %s') and the hot key has funds for fees.", mynode)
+		return txError("authz exec", output, err)
+	}
+
+	log.Infof("✅ %s executed through authz successfully! Transaction output:\n%s", kind, output)
+	return renderTxOutput("authz exec", output)
+}
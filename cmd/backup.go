@@ -0,0 +1,519 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/klauspost/compress/gzip"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/scrypt"
+	"golang.org/x/term"
+)
+
+// backupMagic starts every backup file and versions its format: the magic,
+// a scrypt salt and an XChaCha20-Poly1305 nonce, then the sealed tar.gz.
+const backupMagic = "MRMINT-BACKUP-1\n"
+
+// backupPassphraseEnv is read when no passphrase file is given, for
+// unattended backups.
+const backupPassphraseEnv = "MRMINT_BACKUP_PASSPHRASE"
+
+// backupManifestName is the first entry of the archive.
+const backupManifestName = "manifest.json"
+
+// backupRequiredFiles must be in every backup; without them the validator
+// identity is lost.
+var backupRequiredFiles = []string{
+	"config/priv_validator_key.json",
+	"config/node_key.json",
+}
+
+// BackupManifest describes the files of a backup, with paths relative to the
+// node directory.
+type BackupManifest struct {
+	Node      string       `json:"node" yaml:"node"`
+	CreatedAt time.Time    `json:"created_at" yaml:"created_at"`
+	Files     []BackupFile `json:"files" yaml:"files"`
+}
+
+type BackupFile struct {
+	Name   string `json:"name" yaml:"name"`
+	Size   int64  `json:"size" yaml:"size"`
+	SHA256 string `json:"sha256" yaml:"sha256"`
+}
+
+func (m BackupManifest) renderTable(w io.Writer) {
+	fmt.Fprintf(w, "Node:\t%s\n", m.Node)
+	fmt.Fprintf(w, "Created:\t%s\n", m.CreatedAt.Local().Format(time.DateTime))
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "FILE\tSIZE\tSHA256")
+	for _, f := range m.Files {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", f.Name, formatBytes(f.Size), f.SHA256[:16])
+	}
+}
+
+func backupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Create, verify and restore encrypted backups of the validator identity",
+		Long: `A backup holds the keyring, config/ (including priv_validator_key.json and
+node_key.json), node.yaml, .env, .validator-registered and
+priv_validator_state.json of a node, but no chain data. It is encrypted with a passphrase (scrypt and
+XChaCha20-Poly1305).
+
+The passphrase is read from --passphrase-file, else from ` + backupPassphraseEnv + `,
+else prompted for on the terminal.`,
+	}
+	cmd.AddCommand(backupCreateCmd(), backupVerifyCmd(), backupRestoreCmd())
+	return cmd
+}
+
+func backupCreateCmd() *cobra.Command {
+	var mynode, out, passphraseFile string
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Write an encrypted backup of the node's keys and configuration",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return backupCreateLogic(mynode, out, passphraseFile)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&out, "out", "", "Backup file to write (default: <mynode>-backup-<time>.mrmbak)")
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "Read the passphrase from this file")
+	return cmd
+}
+
+func backupVerifyCmd() *cobra.Command {
+	var file, passphraseFile string
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Decrypt a backup and check that it is complete and intact",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifest, _, err := readBackup(invocationPath(file), passphraseFile)
+			if err != nil {
+				return err
+			}
+			log.Infof("✅ Backup of %s is intact and holds %d files.", manifest.Node, len(manifest.Files))
+			return render(manifest)
+		},
+	}
+	cmd.Flags().StringVar(&file, "file", "", "Backup file to verify")
+	cmd.MarkFlagRequired("file")
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "Read the passphrase from this file")
+	return cmd
+}
+
+func backupRestoreCmd() *cobra.Command {
+	var mynode, file, passphraseFile string
+	var force bool
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore a node's keys and configuration from a backup",
+		Long: `Restores the files of a backup into the node directory (default: the node
+the backup was made from). The node must be stopped.
+
+A priv_validator_state.json on disk that is newer than the one in the backup
+is never overwritten: the validator signed after the backup was made, and
+rolling the state back risks double signing.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return backupRestoreLogic(mynode, invocationPath(file), passphraseFile, force)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Node to restore into (default: the node of the backup)")
+	cmd.Flags().StringVar(&file, "file", "", "Backup file to restore")
+	cmd.MarkFlagRequired("file")
+	cmd.Flags().StringVar(&passphraseFile, "passphrase-file", "", "Read the passphrase from this file")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace a different validator key already present in the node directory")
+	return cmd
+}
+
+func backupCreateLogic(mynode, out, passphraseFile string) error {
+	if out == "" {
+		out = fmt.Sprintf("%s-backup-%s.mrmbak", mynode, time.Now().Format("20060102-150405"))
+	}
+	out = invocationPath(out)
+
+	manifest := BackupManifest{Node: mynode, CreatedAt: time.Now().UTC()}
+	files, err := collectBackupFiles(mynode)
+	if err != nil {
+		return err
+	}
+	for _, name := range backupRequiredFiles {
+		if _, ok := files[name]; !ok {
+			return &ConfigError{Msg: "node " + mynode + " has no " + name, Hint: "run 'init-node --mynode " + mynode + "' first"}
+		}
+	}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sum := sha256.Sum256(files[name])
+		manifest.Files = append(manifest.Files, BackupFile{Name: name, Size: int64(len(files[name])), SHA256: hex.EncodeToString(sum[:])})
+	}
+
+	var archive bytes.Buffer
+	if err := writeBackupArchive(&archive, manifest, files); err != nil {
+		return &RuntimeError{Msg: "failed to write the backup archive", Err: err}
+	}
+
+	passphrase, err := backupPassphrase(passphraseFile, true)
+	if err != nil {
+		return err
+	}
+	sealed, err := sealBackup(archive.Bytes(), passphrase)
+	if err != nil {
+		return &RuntimeError{Msg: "failed to encrypt the backup", Err: err}
+	}
+	if err := os.WriteFile(out, sealed, 0600); err != nil {
+		return &RuntimeError{Msg: "failed to write " + out, Err: err}
+	}
+	log.Infof("🔐 Encrypted backup of %s (%d files) written to %s", mynode, len(manifest.Files), out)
+	log.Warn("⚠️ Keep the backup and its passphrase apart; anyone with both controls your validator.")
+	return nil
+}
+
+// collectBackupFiles reads the files that make up the node's identity, keyed
+// by their path relative to the node directory.
+func collectBackupFiles(mynode string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	add := func(rel string) error {
+		data, err := os.ReadFile(filepath.Join(mynode, rel))
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return &RuntimeError{Msg: "failed to read " + filepath.Join(mynode, rel), Err: err}
+		}
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	}
+	for _, dir := range []string{"config", "keyring-test"} {
+		err := filepath.WalkDir(filepath.Join(mynode, dir), func(p string, d fs.DirEntry, err error) error {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			// The address book is rebuilt from peers and only bloats the backup.
+			if !d.Type().IsRegular() || d.Name() == "addrbook.json" {
+				return nil
+			}
+			rel, err := filepath.Rel(mynode, p)
+			if err != nil {
+				return err
+			}
+			return add(rel)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, rel := range []string{nodeConfigFile, ".env", ".validator-registered", onboardingStateFile, filepath.Join("data", "priv_validator_state.json")} {
+		if err := add(rel); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func writeBackupArchive(w io.Writer, manifest BackupManifest, files map[string][]byte) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	entries := append([]BackupFile{{Name: backupManifestName}}, manifest.Files...)
+	for _, entry := range entries {
+		data := manifestJSON
+		if entry.Name != backupManifestName {
+			data = files[entry.Name]
+		}
+		hdr := &tar.Header{Name: entry.Name, Mode: 0600, Size: int64(len(data)), ModTime: manifest.CreatedAt}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// readBackup decrypts a backup and checks every file against the manifest.
+func readBackup(file, passphraseFile string) (BackupManifest, map[string][]byte, error) {
+	var manifest BackupManifest
+	sealed, err := os.ReadFile(file)
+	if err != nil {
+		return manifest, nil, &ConfigError{Msg: "failed to read " + file, Err: err}
+	}
+	passphrase, err := backupPassphrase(passphraseFile, false)
+	if err != nil {
+		return manifest, nil, err
+	}
+	archive, err := openBackup(sealed, passphrase)
+	if err != nil {
+		return manifest, nil, err
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return manifest, nil, &RuntimeError{Msg: "the backup archive is corrupted", Err: err}
+	}
+	tr := tar.NewReader(gz)
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return manifest, nil, &RuntimeError{Msg: "the backup archive is corrupted", Err: err}
+		}
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return manifest, nil, &RuntimeError{Msg: "the backup archive is corrupted", Err: fmt.Errorf("entry %q points outside the node directory", hdr.Name)}
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return manifest, nil, &RuntimeError{Msg: "the backup archive is corrupted", Err: err}
+		}
+		files[name] = data
+	}
+
+	manifestJSON, ok := files[backupManifestName]
+	if !ok {
+		return manifest, nil, &RuntimeError{Msg: "the backup has no manifest"}
+	}
+	delete(files, backupManifestName)
+	if err := json.Unmarshal(manifestJSON, &manifest); err != nil {
+		return manifest, nil, &RuntimeError{Msg: "the backup manifest is invalid", Err: err}
+	}
+
+	var problems []string
+	for _, f := range manifest.Files {
+		data, ok := files[f.Name]
+		if !ok {
+			problems = append(problems, f.Name+" is missing")
+			continue
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != f.SHA256 {
+			problems = append(problems, f.Name+" does not match its checksum")
+		}
+	}
+	if len(files) != len(manifest.Files) {
+		problems = append(problems, "the archive holds files that are not in the manifest")
+	}
+	for _, name := range backupRequiredFiles {
+		if _, ok := files[name]; !ok {
+			problems = append(problems, name+" is not in the backup")
+		}
+	}
+	if len(problems) > 0 {
+		return manifest, nil, &RuntimeError{Msg: "the backup failed verification", Err: errors.New(strings.Join(problems, "; "))}
+	}
+	return manifest, files, nil
+}
+
+func backupRestoreLogic(mynode, file, passphraseFile string, force bool) error {
+	manifest, files, err := readBackup(file, passphraseFile)
+	if err != nil {
+		return err
+	}
+	if mynode == "" {
+		mynode = manifest.Node
+	}
+	if nodeContainerRunning(mynode) {
+		return &RuntimeError{Msg: "node " + mynode + " is running", Hint: "stop it first with 'stop-node --mynode " + mynode + "'"}
+	}
+
+	keyPath := filepath.Join(mynode, "config", "priv_validator_key.json")
+	if current, err := os.ReadFile(keyPath); err == nil && !bytes.Equal(current, files["config/priv_validator_key.json"]) && !force {
+		return &RuntimeError{
+			Msg:  keyPath + " holds a different validator key than the backup",
+			Hint: "pass --force to replace it, after making sure that key is not in use anywhere",
+		}
+	}
+
+	stateName := "data/priv_validator_state.json"
+	statePath := filepath.Join(mynode, "data", "priv_validator_state.json")
+	if current, err := os.ReadFile(statePath); err == nil {
+		backupState, inBackup := files[stateName]
+		if !inBackup {
+			backupState = []byte(defaultPrivValidatorState)
+		}
+		newer, err := privValidatorStateNewer(current, backupState)
+		if err != nil {
+			return &RuntimeError{Msg: "failed to compare priv_validator_state.json", Err: err}
+		}
+		if newer {
+			return &RuntimeError{
+				Msg:  statePath + " is newer than the one in the backup",
+				Hint: "the validator signed after this backup was made; restoring it could lead to double signing",
+			}
+		}
+		// An identical or older state on disk is replaced by the backup's.
+	}
+
+	for _, f := range manifest.Files {
+		target := filepath.Join(mynode, filepath.FromSlash(f.Name))
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return &RuntimeError{Msg: "failed to create " + filepath.Dir(target), Err: err}
+		}
+		if err := os.WriteFile(target, files[f.Name], 0600); err != nil {
+			return &RuntimeError{Msg: "failed to write " + target, Err: err}
+		}
+	}
+	log.Infof("✅ Restored %d files of %s into %s", len(manifest.Files), manifest.Node, mynode)
+	return nil
+}
+
+// privValidatorState is the last height/round/step the validator signed.
+type privValidatorState struct {
+	Height string `json:"height"`
+	Round  int64  `json:"round"`
+	Step   int64  `json:"step"`
+}
+
+// privValidatorStateNewer reports whether state a is past state b.
+func privValidatorStateNewer(a, b []byte) (bool, error) {
+	var sa, sb privValidatorState
+	if err := json.Unmarshal(a, &sa); err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(b, &sb); err != nil {
+		return false, err
+	}
+	ha, err := strconv.ParseInt(sa.Height, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	hb, err := strconv.ParseInt(sb.Height, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	if ha != hb {
+		return ha > hb, nil
+	}
+	if sa.Round != sb.Round {
+		return sa.Round > sb.Round, nil
+	}
+	return sa.Step > sb.Step, nil
+}
+
+// backupPassphrase reads the passphrase from the file, the environment or
+// the terminal; confirm asks for it twice.
+func backupPassphrase(passphraseFile string, confirm bool) ([]byte, error) {
+	if passphraseFile != "" {
+		data, err := os.ReadFile(invocationPath(passphraseFile))
+		if err != nil {
+			return nil, &ConfigError{Msg: "failed to read the passphrase file", Err: err}
+		}
+		passphrase := bytes.TrimRight(data, "\r\n")
+		if len(passphrase) == 0 {
+			return nil, &ConfigError{Msg: "the passphrase file is empty"}
+		}
+		return passphrase, nil
+	}
+	if env := os.Getenv(backupPassphraseEnv); env != "" {
+		return []byte(env), nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, &ConfigError{Msg: "no backup passphrase given", Hint: "pass --passphrase-file or set " + backupPassphraseEnv}
+	}
+
+	fmt.Fprint(os.Stderr, "Backup passphrase: ")
+	passphrase, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, &UserAbort{Msg: "passphrase prompt cancelled"}
+	}
+	if len(passphrase) == 0 {
+		return nil, &ConfigError{Msg: "the passphrase cannot be empty"}
+	}
+	if confirm {
+		fmt.Fprint(os.Stderr, "Repeat the passphrase: ")
+		again, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, &UserAbort{Msg: "passphrase prompt cancelled"}
+		}
+		if !bytes.Equal(passphrase, again) {
+			return nil, &ConfigError{Msg: "the passphrases do not match"}
+		}
+	}
+	return passphrase, nil
+}
+
+func backupKey(passphrase, salt []byte) ([]byte, error) {
+	return scrypt.Key(passphrase, salt, 1<<15, 8, 1, chacha20poly1305.KeySize)
+}
+
+func sealBackup(plain, passphrase []byte) ([]byte, error) {
+	salt := make([]byte, 16)
+	nonce := make([]byte, chacha20poly1305.NonceSizeX)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key, err := backupKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+	header := append(append([]byte(backupMagic), salt...), nonce...)
+	return aead.Seal(header, nonce, plain, header), nil
+}
+
+func openBackup(sealed, passphrase []byte) ([]byte, error) {
+	headerLen := len(backupMagic) + 16 + chacha20poly1305.NonceSizeX
+	if len(sealed) < headerLen || string(sealed[:len(backupMagic)]) != backupMagic {
+		return nil, &ConfigError{Msg: "not a validator backup file"}
+	}
+	header := sealed[:headerLen]
+	salt := header[len(backupMagic) : len(backupMagic)+16]
+	nonce := header[len(backupMagic)+16:]
+	key, err := backupKey(passphrase, salt)
+	if err != nil {
+		return nil, &RuntimeError{Msg: "failed to derive the backup key", Err: err}
+	}
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, &RuntimeError{Msg: "failed to derive the backup key", Err: err}
+	}
+	plain, err := aead.Open(nil, nonce, sealed[headerLen:], header)
+	if err != nil {
+		return nil, &ConfigError{Msg: "failed to decrypt the backup", Hint: "the passphrase is wrong or the file is damaged"}
+	}
+	return plain, nil
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/gzip"
+)
+
+func TestPrivValidatorStateNewer(t *testing.T) {
+	state := func(height string, round, step int64) []byte {
+		data, _ := json.Marshal(privValidatorState{Height: height, Round: round, Step: step})
+		return data
+	}
+	tests := []struct {
+		name    string
+		a, b    []byte
+		want    bool
+		wantErr bool
+	}{
+		{name: "higher height", a: state("101", 0, 1), b: state("100", 5, 3), want: true},
+		{name: "lower height", a: state("99", 9, 3), b: state("100", 0, 1), want: false},
+		{name: "height beyond int32", a: state("3000000000", 0, 0), b: state("2999999999", 0, 0), want: true},
+		{name: "same height, higher round", a: state("100", 2, 1), b: state("100", 1, 3), want: true},
+		{name: "same height, lower round", a: state("100", 0, 3), b: state("100", 1, 1), want: false},
+		{name: "same round, higher step", a: state("100", 1, 3), b: state("100", 1, 2), want: true},
+		{name: "same round, lower step", a: state("100", 1, 1), b: state("100", 1, 2), want: false},
+		{name: "identical", a: state("100", 1, 2), b: state("100", 1, 2), want: false},
+		{name: "invalid height", a: state("tall", 0, 0), b: state("100", 0, 0), wantErr: true},
+		{name: "invalid json", a: []byte("{"), b: state("100", 0, 0), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := privValidatorStateNewer(tt.a, tt.b)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("privValidatorStateNewer error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("privValidatorStateNewer = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSealOpenBackup(t *testing.T) {
+	plain := []byte("validator identity")
+	sealed, err := sealBackup(plain, []byte("correct horse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	flipped := bytes.Clone(sealed)
+	flipped[len(flipped)-1] ^= 0x01
+	flippedHeader := bytes.Clone(sealed)
+	flippedHeader[len(backupMagic)] ^= 0x01
+
+	tests := []struct {
+		name       string
+		sealed     []byte
+		passphrase string
+		wantErr    string
+	}{
+		{name: "round trip", sealed: sealed, passphrase: "correct horse"},
+		{name: "wrong passphrase", sealed: sealed, passphrase: "battery staple", wantErr: "failed to decrypt the backup"},
+		{name: "flipped ciphertext byte", sealed: flipped, passphrase: "correct horse", wantErr: "failed to decrypt the backup"},
+		{name: "flipped salt byte", sealed: flippedHeader, passphrase: "correct horse", wantErr: "failed to decrypt the backup"},
+		{name: "not a backup", sealed: []byte("hello"), passphrase: "correct horse", wantErr: "not a validator backup file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := openBackup(tt.sealed, []byte(tt.passphrase))
+			if tt.wantErr == "" {
+				if err != nil || !bytes.Equal(got, plain) {
+					t.Fatalf("openBackup = %q, %v; want %q", got, err, plain)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("openBackup error = %v, want %q", err, tt.wantErr)
+			}
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) {
+				t.Errorf("openBackup error %T is not a ConfigError", err)
+			}
+		})
+	}
+}
+
+// testBackupFiles is a node identity as collectBackupFiles returns it.
+func testBackupFiles() map[string][]byte {
+	return map[string][]byte{
+		"config/priv_validator_key.json": []byte(`{"priv_key":"k"}`),
+		"config/node_key.json":           []byte(`{"priv_key":"n"}`),
+		"data/priv_validator_state.json": []byte(`{"height":"10","round":0,"step":0}`),
+	}
+}
+
+func testManifest(files map[string][]byte) BackupManifest {
+	manifest := BackupManifest{Node: "node1", CreatedAt: time.Unix(0, 0).UTC()}
+	for name, data := range files {
+		sum := sha256.Sum256(data)
+		manifest.Files = append(manifest.Files, BackupFile{Name: name, Size: int64(len(data)), SHA256: hex.EncodeToString(sum[:])})
+	}
+	sort.Slice(manifest.Files, func(i, j int) bool { return manifest.Files[i].Name < manifest.Files[j].Name })
+	return manifest
+}
+
+// writeTestBackup seals an archive holding the manifest and every file, which
+// do not have to agree, and returns the path of the backup file.
+func writeTestBackup(t *testing.T, dir string, manifest BackupManifest, files map[string][]byte, passphrase string) string {
+	t.Helper()
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	manifestJSON, _ := json.Marshal(manifest)
+	entries := map[string][]byte{backupManifestName: manifestJSON}
+	for name, data := range files {
+		entries[name] = data
+	}
+	for name, data := range entries {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(data))}); err != nil {
+			t.Fatal(err)
+		}
+		tw.Write(data)
+	}
+	tw.Close()
+	gz.Close()
+
+	sealed, err := sealBackup(archive.Bytes(), []byte(passphrase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, "node1.mrmbak")
+	if err := os.WriteFile(file, sealed, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestReadBackup(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(manifest *BackupManifest, files map[string][]byte)
+		wantErr string
+	}{
+		{name: "valid", edit: func(*BackupManifest, map[string][]byte) {}},
+		{
+			name: "entry missing from the manifest",
+			edit: func(m *BackupManifest, files map[string][]byte) {
+				m.Files = m.Files[:len(m.Files)-1]
+			},
+			wantErr: "the archive holds files that are not in the manifest",
+		},
+		{
+			name: "file missing from the archive",
+			edit: func(m *BackupManifest, files map[string][]byte) {
+				delete(files, "data/priv_validator_state.json")
+			},
+			wantErr: "data/priv_validator_state.json is missing",
+		},
+		{
+			name: "checksum mismatch",
+			edit: func(m *BackupManifest, files map[string][]byte) {
+				files["config/node_key.json"] = []byte(`{"priv_key":"x"}`)
+			},
+			wantErr: "config/node_key.json does not match its checksum",
+		},
+		{
+			name: "required file absent",
+			edit: func(m *BackupManifest, files map[string][]byte) {
+				delete(files, "config/priv_validator_key.json")
+				m.Files = slices.DeleteFunc(m.Files, func(f BackupFile) bool { return f.Name == "config/priv_validator_key.json" })
+			},
+			wantErr: "config/priv_validator_key.json is not in the backup",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			passphraseFile := filepath.Join(dir, "passphrase")
+			os.WriteFile(passphraseFile, []byte("correct horse\n"), 0600)
+
+			files := testBackupFiles()
+			manifest := testManifest(files)
+			tt.edit(&manifest, files)
+			file := writeTestBackup(t, dir, manifest, files, "correct horse")
+
+			_, got, err := readBackup(file, passphraseFile)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("readBackup: %v", err)
+				}
+				if len(got) != len(testBackupFiles()) {
+					t.Errorf("readBackup returned %d files, want %d", len(got), len(testBackupFiles()))
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("readBackup error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadBackupWrongPassphrase(t *testing.T) {
+	dir := t.TempDir()
+	passphraseFile := filepath.Join(dir, "passphrase")
+	os.WriteFile(passphraseFile, []byte("battery staple"), 0600)
+	files := testBackupFiles()
+	file := writeTestBackup(t, dir, testManifest(files), files, "correct horse")
+
+	if _, _, err := readBackup(file, passphraseFile); err == nil || !strings.Contains(err.Error(), "failed to decrypt the backup") {
+		t.Fatalf("readBackup error = %v, want a decryption failure", err)
+	}
+}
@@ -0,0 +1,386 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+// Managed ethermintd binaries live in ~/.mrmintchain/bin/<version>/ethermintd.
+// A release publishes one binary per platform and a SHA256SUMS file:
+//
+//	<release-url>/<version>/ethermintd-<version>-<os>-<arch>[.exe]
+//	<release-url>/<version>/SHA256SUMS
+const (
+	binDirName              = "bin"
+	defaultBinaryReleaseURL = "https://github.com/kamleshesporg/validatorOnboardingCLI/releases/download"
+	binarySumsFile          = "SHA256SUMS"
+)
+
+// activeNode is the node selected by --mynode or 'nodes use', resolved by
+// applyNodeFlag; its pinned binary version decides what Mrmintd runs.
+var activeNode NodeEntry
+
+// InstalledBinary is one version in ~/.mrmintchain/bin.
+type InstalledBinary struct {
+	Version  string   `json:"version" yaml:"version"`
+	Path     string   `json:"path" yaml:"path"`
+	Default  bool     `json:"default" yaml:"default"`
+	PinnedBy []string `json:"pinned_by,omitempty" yaml:"pinned_by,omitempty"`
+}
+
+// BinaryList is the result of binary list.
+type BinaryList struct {
+	Recommended string            `json:"recommended,omitempty" yaml:"recommended,omitempty"`
+	Binaries    []InstalledBinary `json:"binaries" yaml:"binaries"`
+}
+
+func (l BinaryList) renderTable(w io.Writer) {
+	if l.Recommended != "" {
+		fmt.Fprintf(w, "Network recommended version:\t%s\n\n", l.Recommended)
+	}
+	if len(l.Binaries) == 0 {
+		fmt.Fprintln(w, "No managed binaries installed; use 'binary install --version X'.")
+		return
+	}
+	fmt.Fprintln(w, "\tVERSION\tPINNED BY\tPATH")
+	for _, b := range l.Binaries {
+		marker := ""
+		if b.Default {
+			marker = "*"
+		}
+		pinned := strings.Join(b.PinnedBy, ", ")
+		if pinned == "" {
+			pinned = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", marker, b.Version, pinned, b.Path)
+	}
+}
+
+func binaryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "binary",
+		Short: "Install and pin verified ethermintd versions",
+		Long: `Managed binaries are downloaded from the release page, verified against the
+release SHA256SUMS and kept in ~/.mrmintchain/bin/<version>. A node pinned to
+a version runs that binary for every ethermintd command; nodes without a pin
+use the default version, or ./ethermintd when no default is set.`,
+	}
+	cmd.AddCommand(binaryInstallCmd(), binaryListCmd(), binaryUseCmd())
+	return cmd
+}
+
+func binaryInstallCmd() *cobra.Command {
+	var version, releaseURL, sum string
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Download and verify an ethermintd release for this platform",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return binaryInstallLogic(version, releaseURL, sum)
+		},
+	}
+	cmd.Flags().StringVar(&version, "version", "", "Release version to install, e.g. v0.22.0")
+	cmd.MarkFlagRequired("version")
+	cmd.Flags().StringVar(&releaseURL, "release-url", defaultBinaryReleaseURL, "Base URL of the releases")
+	cmd.Flags().StringVar(&sum, "sha256", "", "Expected SHA-256 of the binary (default: from the release SHA256SUMS)")
+	return cmd
+}
+
+func binaryListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List the installed ethermintd versions",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			list, err := binaryListLogic()
+			if err != nil {
+				return err
+			}
+			return render(list)
+		},
+	}
+}
+
+func binaryUseCmd() *cobra.Command {
+	var mynode string
+	cmd := &cobra.Command{
+		Use:   "use <version>",
+		Short: "Pin a node, or the default, to an installed ethermintd version",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return binaryUseLogic(mynode, args[0])
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Registered node to pin (default: set the default version)")
+	return cmd
+}
+
+func binariesDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", &ConfigError{Msg: "could not get user home directory", Err: err}
+	}
+	return filepath.Join(homeDir, configDirName, binDirName), nil
+}
+
+func binaryFileName() string {
+	if runtime.GOOS == "windows" {
+		return chainBinaryName + ".exe"
+	}
+	return chainBinaryName
+}
+
+// binaryPath is where version is installed.
+func binaryPath(version string) (string, error) {
+	dir, err := binariesDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, version, binaryFileName()), nil
+}
+
+func binaryInstallLogic(version, releaseURL, sum string) error {
+	if !safePathElement(version) {
+		return &usageError{Err: fmt.Errorf("invalid version %q", version)}
+	}
+	target, err := binaryPath(version)
+	if err != nil {
+		return err
+	}
+
+	artifact := fmt.Sprintf("%s-%s-%s-%s", chainBinaryName, version, runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		artifact += ".exe"
+	}
+	base := strings.TrimRight(releaseURL, "/") + "/" + version + "/"
+	if sum == "" {
+		sums, err := fetchURL(base + binarySumsFile)
+		if err != nil {
+			return &ChainQueryError{Query: "fetch the release checksums", Err: err, Hint: "check --version and --release-url, or pass the checksum with --sha256"}
+		}
+		if sum = lookupChecksum(string(sums), artifact); sum == "" {
+			return &ConfigError{Msg: "release " + version + " has no " + artifact, Hint: "this platform (" + runtime.GOOS + "/" + runtime.GOARCH + ") may not be published for " + version}
+		}
+	}
+
+	if exists(target) && verifySHA256(target, sum) == nil {
+		log.Infof("✅ ethermintd %s is already installed at %s", version, target)
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return &RuntimeError{Msg: "failed to create " + filepath.Dir(target), Err: err}
+	}
+	log.Infof("⬇️  Downloading %s", base+artifact)
+	if err := downloadResumable(base+artifact, target+".part"); err != nil {
+		return err
+	}
+	if err := verifySHA256(target+".part", sum); err != nil {
+		os.Remove(target + ".part")
+		return &RuntimeError{Msg: "binary checksum verification failed", Err: err, Hint: "the download was removed; run the install again"}
+	}
+	if err := os.Chmod(target+".part", 0755); err != nil {
+		return &RuntimeError{Msg: "failed to make the binary executable", Err: err}
+	}
+	if err := os.Rename(target+".part", target); err != nil {
+		return &RuntimeError{Msg: "failed to install the binary", Err: err}
+	}
+	log.Infof("✅ ethermintd %s installed at %s (sha256 verified)", version, target)
+	log.Infof("📌 Pin it with 'binary use %s' or 'binary use %s --mynode <node>'.", version, version)
+	return nil
+}
+
+// lookupChecksum finds the hash of file in sha256sum output.
+func lookupChecksum(sums, file string) string {
+	scanner := bufio.NewScanner(strings.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == file {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+func binaryListLogic() (BinaryList, error) {
+	list := BinaryList{Binaries: []InstalledBinary{}}
+	dir, err := binariesDir()
+	if err != nil {
+		return list, err
+	}
+	registry, err := loadNodeRegistry()
+	if err != nil {
+		return list, &ConfigError{Msg: "failed to read the node registry", Err: err}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		return list, &RuntimeError{Msg: "failed to read " + dir, Err: err}
+	}
+	for _, e := range entries {
+		path := filepath.Join(dir, e.Name(), binaryFileName())
+		if !e.IsDir() || !exists(path) {
+			continue
+		}
+		b := InstalledBinary{Version: e.Name(), Path: path, Default: registry.BinaryVersion == e.Name()}
+		for _, n := range registry.Nodes {
+			if n.BinaryVersion == e.Name() {
+				b.PinnedBy = append(b.PinnedBy, n.Name)
+			}
+		}
+		list.Binaries = append(list.Binaries, b)
+	}
+	sort.Slice(list.Binaries, func(i, j int) bool { return list.Binaries[i].Version < list.Binaries[j].Version })
+
+	if err := loadConfigCliParams(); err != nil {
+		log.Warnf("⚠️ Could not load the chain config: %v", err)
+	}
+	list.Recommended = recommendedBinaryVersion(configCliParams.BootNodeRpc)
+	return list, nil
+}
+
+func binaryUseLogic(mynode, version string) error {
+	if !safePathElement(version) {
+		return &usageError{Err: fmt.Errorf("invalid version %q", version)}
+	}
+	path, err := binaryPath(version)
+	if err != nil {
+		return err
+	}
+	if !exists(path) {
+		return &ConfigError{Msg: "ethermintd " + version + " is not installed", Hint: "install it with 'binary install --version " + version + "'"}
+	}
+	registry, err := loadNodeRegistry()
+	if err != nil {
+		return &ConfigError{Msg: "failed to read the node registry", Err: err}
+	}
+	if mynode == "" {
+		registry.BinaryVersion = version
+		log.Infof("📌 Nodes without a pinned version now use ethermintd %s", version)
+	} else {
+		// applyNodeFlag rewrote --mynode to the node directory name; the
+		// registry is keyed by the node name.
+		if activeNode.Name != "" {
+			mynode = activeNode.Name
+		}
+		entry, ok := registry.Find(mynode)
+		if !ok {
+			return &ConfigError{Msg: "node " + mynode + " is not registered", Hint: "register it with 'nodes add' first"}
+		}
+		entry.BinaryVersion = version
+		registry.Put(entry)
+		log.Infof("📌 Node %s now uses ethermintd %s", mynode, version)
+	}
+	if err := saveNodeRegistry(registry); err != nil {
+		return &RuntimeError{Msg: "failed to save the node registry", Err: err}
+	}
+	return nil
+}
+
+// pinnedBinaryVersion is the version the active node is pinned to, else the
+// default version, else "".
+func pinnedBinaryVersion() string {
+	if activeNode.BinaryVersion != "" {
+		return activeNode.BinaryVersion
+	}
+	registry, err := loadNodeRegistry()
+	if err != nil {
+		return ""
+	}
+	return registry.BinaryVersion
+}
+
+// applyPinnedBinary points Mrmintd at the pinned binary of the active node.
+// Remote nodes keep the binary of their workspace.
+func applyPinnedBinary() {
+	if activeNode.Remote != nil {
+		return
+	}
+	version := pinnedBinaryVersion()
+	if version == "" {
+		return
+	}
+	path, err := binaryPath(version)
+	if err != nil {
+		return
+	}
+	if !exists(path) {
+		log.Warnf("⚠️ ethermintd %s is pinned but not installed; using %s. Run 'binary install --version %s'.", version, Mrmintd, version)
+		return
+	}
+	Mrmintd = path
+	log.Debugf("Using ethermintd %s from %s", version, path)
+}
+
+// pinnedBinaryMount mounts the pinned binary over the one of the Docker
+// image, so the container runs the same version as the host commands. Only
+// Linux hosts can share their binary with the Linux container.
+func pinnedBinaryMount() []string {
+	version := pinnedBinaryVersion()
+	if version == "" || runtime.GOOS != "linux" || activeNode.Remote != nil {
+		return nil
+	}
+	path, err := binaryPath(version)
+	if err != nil || !exists(path) {
+		return nil
+	}
+	return []string{"-v", path + ":" + filepath.ToSlash(filepath.Join("/app", containerMrmintd)) + ":ro"}
+}
+
+// recommendedBinaryVersion is the version from the chain config, else the
+// application version the boot node reports.
+func recommendedBinaryVersion(bootRpc string) string {
+	if configCliParams.RecommendedVersion != "" {
+		return configCliParams.RecommendedVersion
+	}
+	if bootRpc == "" {
+		return ""
+	}
+	var resp struct {
+		Result struct {
+			Response struct {
+				Version string `json:"version"`
+			} `json:"response"`
+		} `json:"result"`
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+	r, err := client.Get(rpcHTTPURL(bootRpc) + "/abci_info")
+	if err != nil {
+		return ""
+	}
+	defer r.Body.Close()
+	if json.NewDecoder(r.Body).Decode(&resp) != nil {
+		return ""
+	}
+	return resp.Result.Response.Version
+}
+
+// warnBinaryVersion warns when the node's ethermintd differs from the
+// version the network recommends.
+func warnBinaryVersion(mynode string) {
+	env, _ := godotenv.Read(filepath.Join(mynode, ".env"))
+	recommended := recommendedBinaryVersion(env["BOOT_NODE_RPC"])
+	if recommended == "" {
+		return
+	}
+	version := pinnedBinaryVersion()
+	if version == "" {
+		var output syncBuffer
+		if transport.Run(nil, &output, &output, Mrmintd, "version") != nil {
+			return
+		}
+		version = strings.TrimSpace(output.String())
+	}
+	if strings.TrimPrefix(version, "v") != strings.TrimPrefix(recommended, "v") {
+		log.Warnf("⚠️ Node %s runs ethermintd %s but the network recommends %s. Install it with 'binary install --version %s' and pin it with 'binary use'.", mynode, version, recommended, recommended)
+	}
+}
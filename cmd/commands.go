@@ -4,13 +4,13 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 
 	"math/big"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -22,22 +22,37 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/mdp/qrterminal"
 	"github.com/spf13/cobra"
+	"github.com/yourname/ethermint-validator-cli/denom"
 	"golang.org/x/term"
 
 	"gopkg.in/yaml.v2"
 )
 
 type ConfigCliParams struct {
-	PersistentPeers string `json:"persistent_peers"`
-	GenesisUrl      string `json:"genesisUrl"`
-	ConfigTomlUrl   string `json:"configToml"`
-	ChaindId        string `json:"chindId"`
-	MinStakeFund    int64  `json:"minStakeFund"`
-	BootNodeRpc     string `json:"bootNodeRpc"`
+	PersistentPeers string      `json:"persistent_peers"`
+	GenesisUrl      string      `json:"genesisUrl"`
+	ConfigTomlUrl   string      `json:"configToml"`
+	ChaindId        string      `json:"chindId"`
+	MinStakeFund    json.Number `json:"minStakeFund"`
+	BootNodeRpc     string      `json:"bootNodeRpc"`
+	// StateSyncRpcServers are the RPC servers used by --state-sync.
+	StateSyncRpcServers []string `json:"stateSyncRpcServers,omitempty"`
+	// SnapshotIndexUrl is the index of the data snapshots used by 'snapshot'.
+	SnapshotIndexUrl string `json:"snapshotIndexUrl,omitempty"`
+	// RecommendedVersion is the ethermintd release validators should run.
+	RecommendedVersion string `json:"recommendedVersion,omitempty"`
+	// SystemRequirements override the minimums checked by doctor.
+	SystemRequirements *SystemRequirements `json:"systemRequirements,omitempty"`
 }
 
+// Mrmintd is the ethermintd run on the host: the workspace binary, or the
+// version pinned with 'binary use'.
 var Mrmintd = "./ethermintd"
 
+// containerMrmintd is the ethermintd of the node container, relative to its
+// working directory.
+const containerMrmintd = "./ethermintd"
+
 var configCliParams ConfigCliParams
 
 type ParamChange struct {
@@ -78,67 +93,80 @@ type ProposalFile struct {
 }
 
 func runCmd(command string, args ...string) error {
-	fmt.Printf("Running: %s %v\n", command, args)
-	cmd := exec.Command(command, args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	cmd.Stdin = os.Stdin
-	return cmd.Run()
+	fmt.Fprintf(os.Stderr, "Running: %s %v\n", command, args)
+	return transport.Run(os.Stdin, os.Stderr, os.Stderr, command, args...)
 }
 
 func runCmdCaptureOutput(command string, args ...string) (string, error) {
-	fmt.Printf("Running: %s %v\n", command, args)
-	cmd := exec.Command(command, args...)
-	output, err := cmd.CombinedOutput()
-	return string(output), err
+	fmt.Fprintf(os.Stderr, "Running: %s %v\n", command, args)
+	var output syncBuffer
+	err := transport.Run(nil, &output, &output, command, args...)
+	return output.String(), err
 }
 
 func initNodeCmd() *cobra.Command {
 	var mynode string
+	var stateSync stateSyncOptions
 
 	cmd := &cobra.Command{
 		Use:   "init-node",
 		Short: "Initialize Ethermint node",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return initNodeLogic(mynode)
+			if err := initNodeLogic(mynode); err != nil {
+				return err
+			}
+			if stateSync.Enabled {
+				return configureStateSync(mynode, stateSync)
+			}
+			return nil
 		},
 	}
 
 	cmd.Flags().StringVar(&mynode, "mynode", "mrmintchainNode001", "Your node name")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
+	addStateSyncFlags(cmd, &stateSync)
 	return cmd
 }
 
 // ✅ Extracted logic to reuse in auto-run
 func initNodeLogic(mynode string) error {
-	configCliParams = getConfigCliParams()
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
 
-	validatorName := mynode
+	validatorName := nodeKeyName(mynode)
 	mynode = "" + mynode
 
 	genesisPath := mynode + "/config/genesis.json"
 
 	if exists(genesisPath) {
 		log.Info("⚠️  genesis.json already exists: " + genesisPath)
-		if !yesNo("Delete and proceed?") {
-			log.Error("Cancelled")
-			return nil
+		proceed, err := yesNo("Delete and proceed?")
+		if err != nil {
+			return err
+		}
+		if !proceed {
+			return &UserAbort{Msg: "init cancelled, the existing node was kept"}
 		}
 		if err := os.RemoveAll(mynode); err != nil {
-			log.Error("failed to remove node folder: ")
-			return err
+			return &RuntimeError{Msg: "failed to remove node folder", Err: err}
 		}
 	}
 
 	if output, err := runCmdCaptureOutput(Mrmintd, "init", validatorName, "--chain-id", configCliParams.ChaindId, "--home", mynode); err != nil {
 		log.Errorf("init command failed: %s", output)
-		return err
+		return &RuntimeError{Msg: "ethermintd init failed", Err: err, Hint: "make sure ./ethermintd exists in the working directory and is executable"}
 	}
 
-	updateGenesis(mynode)
-	updateConfigToml(mynode)
+	if err := updateGenesis(mynode); err != nil {
+		return err
+	}
+	if err := updateConfigToml(mynode); err != nil {
+		return err
+	}
+	registerNode(mynode)
 
-	fmt.Println("✅ Node initialized.")
+	fmt.Fprintln(os.Stderr, "✅ Node initialized.")
 	return nil
 }
 
@@ -153,23 +181,26 @@ func addKeyCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
 	return cmd
 }
 
 func addKeyCmdLogic(mynode string) error {
-	permission := yesNo("Are you want to generate wallet ?")
+	permission, err := yesNo("Are you want to generate wallet ?")
+	if err != nil {
+		return err
+	}
 	if !permission {
-		log.Fatalf("Key Generation process stop.")
+		return &UserAbort{Msg: "key generation cancelled"}
 	}
 
-	validatorName := mynode
+	validatorName := nodeKeyName(mynode)
 	mynode = "" + mynode
 
 	output, err := runCmdCaptureOutput(Mrmintd, "keys", "add", validatorName, "--algo", "eth_secp256k1", "--keyring-backend", "test", "--home", mynode)
 	if err != nil {
 		log.Errorf("keys add command failed: %s\nOutput: %s", err, output)
-		return err
+		return &RuntimeError{Msg: "failed to add key '" + validatorName + "'", Err: err}
 	}
 
 	log.Printf("Key generation output: %s\n", output)
@@ -189,17 +220,19 @@ func addGenesisAccountCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
 	return cmd
 }
 
 func addGenesisAccountLogic(mynode string) error {
-	configCliParams = getConfigCliParams()
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
 
-	validatorName := mynode
+	validatorName := nodeKeyName(mynode)
 	mynode = "" + mynode
 
-	getAddr := exec.Command(Mrmintd, "keys", "show", validatorName, "-a", "--home", mynode, "--keyring-backend", "test")
+	getAddr := nodeCommand(Mrmintd, "keys", "show", validatorName, "-a", "--home", mynode, "--keyring-backend", "test")
 	addrOut, err := getAddr.Output()
 	if err != nil {
 		return err
@@ -207,7 +240,7 @@ func addGenesisAccountLogic(mynode string) error {
 	ethm1Address := strings.TrimSpace(string(addrOut))
 	ethAddress, err := Bech32ToEthAddress(ethm1Address)
 	if err != nil {
-		log.Fatalf("Invalid bech32 address: %v", err)
+		return &ChainQueryError{Query: "read the validator address", Err: err}
 	}
 
 	log.Info("Its your validator wallet : ")
@@ -222,177 +255,197 @@ func addGenesisAccountLogic(mynode string) error {
 	return nil
 }
 
-func getBalanceCmdLogic(walletEthmAddress string) (bool, int64) {
-	configCliParams = getConfigCliParams()
+// getBalanceCmdLogic returns the wallet's balance of the mnt denom in base units.
+func getBalanceCmdLogic(walletEthmAddress string) (bool, *big.Int) {
+	if err := loadConfigCliParams(); err != nil {
+		log.Errorf("Get balance command failed: %s", err)
+		return false, big.NewInt(0)
+	}
 	bootRpc := configCliParams.BootNodeRpc
 	if bootRpc == "" {
-		bootRpc = getEnvOrFail("BOOT_NODE_RPC")
+		bootRpc = os.Getenv("BOOT_NODE_RPC")
 	}
 	if bootRpc == "" {
 		log.Errorf("Boot node rpc not provided")
-		return false, 0
+		return false, big.NewInt(0)
 	}
-	output, err := runCmdCaptureOutput(Mrmintd, "query", "bank", "balances", walletEthmAddress, "--node", bootRpc)
-	if err != nil {
-		log.Errorf("Get balance command failed: %s\nOutput: %s", err, output)
-		return false, 0
-	}
-	var cResp CmdOutput
-
-	err = yaml.Unmarshal([]byte(output), &cResp)
+	balances, err := queryBalances(walletEthmAddress, bootRpc, "balances")
 	if err != nil {
 		log.Errorf("Get balance command failed: %s", err)
-		return false, 0
+		return false, big.NewInt(0)
 	}
 
-	if len(cResp.Balances) == 0 {
+	if len(balances) == 0 {
 		log.Error("The balances array is indeed empty, as expected. Please deposit fund then proceed")
-		return false, 0
-	} else {
+		return false, big.NewInt(0)
+	}
 
-		bigAmount := new(big.Int)
-		bigAmount, ok := bigAmount.SetString(cResp.Balances[0].Amount, 10)
+	md := denomMetadata(bootRpc)
+	balance := big.NewInt(0)
+	found := false
+	for _, b := range balances {
+		amount, ok := new(big.Int).SetString(b.Amount, 10)
 		if !ok {
-			log.Error("Invalid number")
-			return false, 0
+			log.Errorf("Invalid %s balance: %s", b.Denom, b.Amount)
+			continue
 		}
-
-		// Divide by Wei (as big.Int)
-		wei := big.NewInt(1e18)
-		exactBalance := new(big.Int).Div(bigAmount, wei)
-
-		// This block would only execute if balances was not empty.
-		log.Infof("💸 The balance is : %d %s", exactBalance, cResp.Balances[0].Denom)
-		log.Infof("💸 The Exact balance is : %s %s", cResp.Balances[0].Amount, cResp.Balances[0].Denom)
-
-		return true, exactBalance.Int64()
-
+		if b.Denom != md.Base {
+			log.Infof("💸 The balance is : %s %s", b.Amount, b.Denom)
+			continue
+		}
+		balance, found = amount, true
+		log.Infof("💸 The balance is : %s", md.Format(amount))
+		log.Infof("💸 The Exact balance is : %s %s", b.Amount, b.Denom)
 	}
+
+	return found, balance
 }
 
 func portsAndEnvGenerationCmd() *cobra.Command {
 	var mynode string
+	var opts portOptions
 
 	cmd := &cobra.Command{
 		Use:   "port-set",
 		Short: "To start node, ports and env generation",
+		Long: `Chooses the node ports and writes the node .env. Without flags each port is
+asked for. --auto picks a free block of consecutive ports, skipping ports used
+by sibling nodes, registered nodes and running Docker containers; --base-port
+sets where the block starts (P2P, RPC, gRPC, gRPC-web, JSON-RPC = base+0..4).
+
+Only P2P is published on all interfaces by default; RPC, gRPC, gRPC-web and
+JSON-RPC are published on 127.0.0.1. Override with --bind, e.g.
+--bind rpc=0.0.0.0,json-rpc=0.0.0.0.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return portsAndEnvGenerationLogic(mynode)
+			if opts.BasePort != 0 {
+				if _, err := parsePort(strconv.Itoa(opts.BasePort)); err != nil {
+					return &usageError{Err: fmt.Errorf("--base-port: %w", err)}
+				}
+			}
+			if _, err := bindAddresses(opts.Bind); err != nil {
+				return &usageError{Err: err}
+			}
+			return portSetLogic(mynode, opts)
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
+	cmd.Flags().BoolVar(&opts.Auto, "auto", false, "Pick a free block of ports without asking")
+	cmd.Flags().IntVar(&opts.BasePort, "base-port", 0, "First port of the block (P2P); the others follow it")
+	cmd.Flags().StringToStringVar(&opts.Bind, "bind", nil, "Publish address per service (p2p, rpc, grpc, grpc-web, json-rpc)")
 	return cmd
 }
 
+// portsAndEnvGenerationLogic asks for the ports and writes the node .env.
 func portsAndEnvGenerationLogic(mynode string) error {
-	configCliParams = getConfigCliParams()
-
-	fmt.Print("\n Please enter port - \n")
-	portsArray := []string{}
-
-	p2p := getPortInputAndCheck("P2P_PORT", "26666", portsArray)
-	portsArray = append(portsArray, p2p)
-	log.Infof("✅ p2p-laddr: %s", p2p)
-
-	rpc := getPortInputAndCheck("RPC_PORT", "26667", portsArray)
-	portsArray = append(portsArray, rpc)
-	log.Infof("✅ rpc-laddr: %s", rpc)
-
-	grpc := getPortInputAndCheck("GRPC_PORT", "9092", portsArray)
-	portsArray = append(portsArray, grpc)
-	log.Infof("✅ grpc-address: %s", grpc)
-
-	grpcWeb := getPortInputAndCheck("GRPC_WEB_PORT", "9093", portsArray)
-	portsArray = append(portsArray, grpcWeb)
-	log.Infof("✅ grpc-web-address: %s", grpcWeb)
-
-	jsonRpc := getPortInputAndCheck("JSON_RPC_PORT", "8547", portsArray)
-	log.Infof("✅ json-rpc-address: %s", jsonRpc)
-
-	// Construct .env content
-
-	envContent := fmt.Sprintf(`
-		P2P_PORT=%s
-		RPC_PORT=%s
-		GRPC_PORT=%s
-		GRPC_WEB_PORT=%s
-		JSON_RPC_PORT=%s
-		PERSISTENT_PEERS=%s
-		BOOT_NODE_RPC=%s`,
-		p2p,
-		rpc,
-		grpc,
-		grpcWeb,
-		jsonRpc,
-		configCliParams.PersistentPeers,
-		configCliParams.BootNodeRpc,
-	)
-
-	// Path to write .env
-	envPath := filepath.Join(mynode, ".env")
+	return portSetLogic(mynode, portOptions{})
+}
 
-	// Ensure the directory exists
-	if err := os.MkdirAll(mynode, os.ModePerm); err != nil {
-		log.Errorf("❌ Failed to create node directory: %v\n", err)
-		os.Exit(1)
+func portSetLogic(mynode string, opts portOptions) error {
+	if err := loadConfigCliParams(); err != nil {
+		return err
 	}
-
-	// Write to .env
-	err := os.WriteFile(envPath, []byte(envContent), 0644)
+	binds, err := bindAddresses(opts.Bind)
+	if err != nil {
+		return &usageError{Err: err}
+	}
+	ports, err := choosePorts(mynode, opts)
 	if err != nil {
-		log.Infof("❌ Failed to write .env file: %v\n", err)
-		os.Exit(1)
+		return err
 	}
 
-	log.Infof("✅ .env file generated at %s\n", envPath)
-	return err
+	// An existing node.yaml keeps everything but the ports, including the
+	// binds --bind does not override, so peers and a sentry layout survive.
+	cfg, err := readNodeConfig(mynode)
+	switch {
+	case err == nil:
+		for _, s := range portServices {
+			if sp, ok := cfg.Ports.service(s.Name); ok && sp.Bind != "" {
+				if _, override := opts.Bind[s.Name]; !override {
+					binds[s.Name] = sp.Bind
+				}
+			}
+		}
+	case errors.Is(err, os.ErrNotExist):
+		cfg = NodeConfig{SchemaVersion: nodeConfigVersion, Image: nodeImage(mynode)}
+		cfg.Network = NodeNetwork{
+			PersistentPeers: splitPeers(configCliParams.PersistentPeers),
+			BootNodeRpc:     configCliParams.BootNodeRpc,
+		}
+	default:
+		return err
+	}
+	for _, s := range portServices {
+		log.Infof("✅ %s: %s:%d", s.Name, binds[s.Name], ports[s.Key])
+		if sp, ok := cfg.Ports.service(s.Name); ok {
+			*sp = ServicePort{Port: ports[s.Key], Bind: binds[s.Name]}
+		}
+	}
+	cfg.applyDefaults()
+	if problems := cfg.Validate(); len(problems) > 0 {
+		return nodeConfigError(mynode, problems)
+	}
+	if err := saveNodeConfig(mynode, cfg); err != nil {
+		return err
+	}
+	registerNode(mynode)
+	return nil
 }
 
 func startNodeCmd() *cobra.Command {
 	var mynode string
+	var stateSync stateSyncOptions
 
 	cmd := &cobra.Command{
 		Use:   "start-node",
 		Short: "Start the Ethermint node",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if stateSync.Enabled {
+				// The chain config is only needed for its default RPC servers.
+				if len(stateSync.RPCServers) == 0 {
+					if err := loadConfigCliParams(); err != nil {
+						return err
+					}
+				}
+				if err := configureStateSync(mynode, stateSync); err != nil {
+					return err
+				}
+			}
 			return startNodeCmdLogic(mynode)
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
+	addStateSyncFlags(cmd, &stateSync)
 	return cmd
 }
 
 func startNodeCmdLogic(mynode string) error {
-	// Load the .env file
-	mynode = "" + mynode // Ensure mynode path is correctly formatted
-
-	err := godotenv.Load(filepath.Join(mynode, ".env"))
+	// node.yaml is the only source of the node settings, so the image can no
+	// longer depend on which .env godotenv happened to load first.
+	cfg, err := loadNodeConfig(mynode)
 	if err != nil {
-		log.Fatalf("❌ Failed to load .env: %v", err)
+		return err
 	}
+	ports := cfg.Ports
+	PersistentPeers := strings.Join(cfg.Network.PersistentPeers, ",")
 
-	err = godotenv.Load(filepath.Join(".env"))
-	if err != nil {
-		log.Fatalf("❌ Failed to load .env: %v", err)
+	// A container listens on all its interfaces and publishes the ports on
+	// the bind addresses; a native node binds them itself.
+	native := nodeRuntime(mynode) == runtimeNative
+	laddr := func(sp ServicePort) string {
+		if native && sp.Bind != "" {
+			return fmt.Sprintf("%s:%d", sp.Bind, sp.Port)
+		}
+		return fmt.Sprintf("0.0.0.0:%d", sp.Port)
 	}
+	p2pLaddr := "tcp://" + laddr(ports.P2P)
+	rpcLaddr := "tcp://" + laddr(ports.RPC)
+	grpcAddress := laddr(ports.GRPC)
+	grpcWebAddress := laddr(ports.GRPCWeb)
+	jsonRpcAddress := laddr(ports.JSONRPC)
 
-	// Read from environment variables (set during auto-setup)
-	p2pPort := getEnvOrFail("P2P_PORT")
-	rpcPort := getEnvOrFail("RPC_PORT")
-	grpcPort := getEnvOrFail("GRPC_PORT")
-	grpcWebPort := getEnvOrFail("GRPC_WEB_PORT")
-	jsonRpcPort := getEnvOrFail("JSON_RPC_PORT")
-	PersistentPeers := getEnvOrFail("PERSISTENT_PEERS")
-
-	p2pLaddr := "tcp://0.0.0.0:" + p2pPort
-	rpcLaddr := "tcp://0.0.0.0:" + rpcPort
-	grpcAddress := "0.0.0.0:" + grpcPort
-	grpcWebAddress := "0.0.0.0:" + grpcWebPort
-	jsonRpcAddress := "0.0.0.0:" + jsonRpcPort
-
-	log.Info("✅ Using Ports from ENV:")
+	log.Infof("✅ Using Ports from %s:", filepath.Join(mynode, nodeConfigFile))
 	log.Infof("  - p2p-laddr: %s", p2pLaddr)
 	log.Infof("  - rpc-laddr: %s", rpcLaddr)
 	log.Infof("  - grpc-address: %s", grpcAddress)
@@ -400,15 +453,29 @@ func startNodeCmdLogic(mynode string) error {
 	log.Infof("  - json-rpc-address: %s", jsonRpcAddress)
 	log.Infof("  - persistent-peers: %s \n", PersistentPeers)
 
-	imageName := getEnvOrFail("IMAGE_NAME")
-	if imageName == "" {
-		log.Fatal("❌ IMAGE_NAME is not set in environment")
+	startArgs := []string{"start",
+		"--home", mynode, // Relative to the workspace, mounted as /app in a container
+		"--p2p.laddr", p2pLaddr,
+		"--rpc.laddr", rpcLaddr,
+		"--grpc.address", grpcAddress,
+		"--grpc-web.address", grpcWebAddress,
+		"--json-rpc.address", jsonRpcAddress,
+		"--p2p.persistent_peers", PersistentPeers}
+	if len(cfg.Network.Seeds) > 0 {
+		startArgs = append(startArgs, "--p2p.seeds", strings.Join(cfg.Network.Seeds, ","))
+	}
+	warnBinaryVersion(mynode)
+
+	if native {
+		return startNativeNode(mynode, startArgs)
 	}
-	log.Infof("Docker image found : %s in %s File", imageName, filepath.Join(".env"))
+
+	imageName := cfg.Image
+	log.Infof("Docker image: %s", imageName)
 
 	// --- FIX IS HERE ---
 	// Get the absolute path of the current working directory
-	cwd, err := os.Getwd()
+	cwd, err := transport.WorkingDir()
 	if err != nil {
 		log.Errorf("❌ Failed to get current working directory: %v", err)
 		return err
@@ -418,22 +485,28 @@ func startNodeCmdLogic(mynode string) error {
 	hostNodePath := filepath.Join(cwd, mynode)
 	containerNodePath := filepath.Join("/app", mynode) // Assuming /app is where you want to mount inside Docker
 
+	container := nodeContainer(mynode)
+
 	// Run the command with ports from ENV and the absolute path for volume mount
-	err = runCmd("docker", "run", "-d", "-it", "--name", mynode,
+	runArgs := []string{"run", "-d", "-it", "--name", container,
 		"-v", fmt.Sprintf("%s:%s", hostNodePath, containerNodePath), // Use the absolute paths here
-		"-p", p2pPort+":"+p2pPort, // P2P port
-		"-p", rpcPort+":"+rpcPort, // RPC port
-		"-p", grpcPort+":"+grpcPort, // Ethereum JSON-RPC
-		"-p", grpcWebPort+":"+grpcWebPort, // gRPC
-		"-p", jsonRpcPort+":"+jsonRpcPort, // gRPC-Web
-		imageName, Mrmintd, "start",
-		"--home", mynode, // This refers to the path *inside* the container
-		"--p2p.laddr", p2pLaddr,
-		"--rpc.laddr", rpcLaddr,
-		"--grpc.address", grpcAddress,
-		"--grpc-web.address", grpcWebAddress,
-		"--json-rpc.address", jsonRpcAddress,
-		"--p2p.persistent_peers", PersistentPeers)
+	}
+	runArgs = append(runArgs, pinnedBinaryMount()...)
+	if cfg.DockerNetwork != "" {
+		if err := ensureDockerNetwork(cfg.DockerNetwork); err != nil {
+			return err
+		}
+		runArgs = append(runArgs, "--network", cfg.DockerNetwork)
+	}
+	runArgs = append(runArgs,
+		"-p", publishArg(ports.P2P), // P2P port
+		"-p", publishArg(ports.RPC), // RPC port
+		"-p", publishArg(ports.GRPC), // gRPC
+		"-p", publishArg(ports.GRPCWeb), // gRPC-Web
+		"-p", publishArg(ports.JSONRPC), // Ethereum JSON-RPC
+		imageName, nodeBinary(mynode))
+	runArgs = append(runArgs, startArgs...)
+	err = runCmd("docker", runArgs...)
 	if err != nil {
 		log.Errorf("❌ node start command failed: %s", err)
 		return err
@@ -441,13 +514,63 @@ func startNodeCmdLogic(mynode string) error {
 
 	log.Info("🚀 Node started successfully!")
 	log.Infof("🚀 Now you can check logs, stop, start, remove container with following commands: ")
-	log.Infof("===> docker logs %s", mynode)
-	log.Infof("===> docker stop %s", mynode)
-	log.Infof("===> docker start %s", mynode)
-	log.Infof("===> docker rm %s", mynode)
+	log.Infof("===> docker logs %s", container)
+	log.Infof("===> docker stop %s", container)
+	log.Infof("===> docker start %s", container)
+	log.Infof("===> docker rm %s", container)
+	return nil
+}
+
+// Native nodes run ethermintd as a background process of the workspace; its
+// pid and output are kept in the node home.
+const (
+	nativePidFile = "node.pid"
+	nativeLogFile = "node.log"
+)
+
+// startNativeNode starts ethermintd in the background where the node lives.
+func startNativeNode(mynode string, startArgs []string) error {
+	binary := nodeBinary(mynode)
+	if binary == containerMrmintd {
+		binary = Mrmintd
+	}
+	pidFile := shellQuote(filepath.ToSlash(filepath.Join(mynode, nativePidFile)))
+	logFile := shellQuote(filepath.ToSlash(filepath.Join(mynode, nativeLogFile)))
+	script := `if [ -f ` + pidFile + ` ] && kill -0 "$(cat ` + pidFile + `)" 2>/dev/null; then echo "already running with pid $(cat ` + pidFile + `)" >&2; exit 1; fi; ` +
+		`nohup "$0" "$@" >> ` + logFile + ` 2>&1 < /dev/null & echo $! > ` + pidFile
+	if err := runCmd("sh", append([]string{"-c", script, binary}, startArgs...)...); err != nil {
+		return &RuntimeError{Msg: "failed to start node " + mynode, Err: err, Hint: "check " + filepath.Join(mynode, nativeLogFile)}
+	}
+	log.Info("🚀 Node started successfully!")
+	log.Infof("🚀 Follow its logs with 'logs --mynode %s -f' and stop it with 'stop-node --mynode %s'.", mynode, mynode)
+	return nil
+}
+
+// stopNode stops the node container, or the process of a native node.
+func stopNode(mynode string) error {
+	if nodeRuntime(mynode) != runtimeNative {
+		return runCmd("docker", "stop", nodeContainer(mynode))
+	}
+	pidFile := shellQuote(filepath.ToSlash(filepath.Join(mynode, nativePidFile)))
+	script := `[ -f ` + pidFile + ` ] || { echo "not running: no ` + nativePidFile + `" >&2; exit 1; }; kill "$(cat ` + pidFile + `)" && rm -f ` + pidFile
+	if err := runCmd("sh", "-c", script); err != nil {
+		return &RuntimeError{Msg: "failed to stop node " + mynode, Err: err}
+	}
 	return nil
 }
 
+// removeNode stops the node and removes its container, so that start-node
+// can start it again with new flags.
+func removeNode(mynode string) error {
+	if nodeRuntime(mynode) != runtimeNative {
+		_, err := runCmdCaptureOutput("docker", "rm", "-f", nodeContainer(mynode))
+		return err
+	}
+	pidFile := shellQuote(filepath.ToSlash(filepath.Join(mynode, nativePidFile)))
+	_, err := runCmdCaptureOutput("sh", "-c", `if [ -f `+pidFile+` ]; then kill "$(cat `+pidFile+`)" 2>/dev/null; rm -f `+pidFile+`; fi`)
+	return err
+}
+
 func stopNodeCmd() *cobra.Command {
 	var mynode string
 
@@ -455,11 +578,11 @@ func stopNodeCmd() *cobra.Command {
 		Use:   "stop-node",
 		Short: "Stop the Ethermint node",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCmd("docker", "stop", mynode)
+			return stopNode(mynode)
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
 	return cmd
 }
 
@@ -470,11 +593,14 @@ func restartNodeCmd() *cobra.Command {
 		Use:   "restart-node",
 		Short: "Re-start the Ethermint node",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runCmd("docker", "start", mynode)
+			if nodeRuntime(mynode) == runtimeNative {
+				return startNodeCmdLogic(mynode)
+			}
+			return runCmd("docker", "start", nodeContainer(mynode))
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
 	return cmd
 }
 
@@ -489,40 +615,77 @@ func getValidatorBalanceCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
 	return cmd
 }
 
+// ValidatorBalanceResult is the result of the validator-balance command.
+type ValidatorBalanceResult struct {
+	Address    string           `json:"address" yaml:"address"`
+	EthAddress string           `json:"eth_address" yaml:"eth_address"`
+	Balances   []AccountBalance `json:"balances" yaml:"balances"`
+}
+
+func (r ValidatorBalanceResult) renderTable(w io.Writer) {
+	fmt.Fprintf(w, "Address\t%s\n", r.Address)
+	fmt.Fprintf(w, "Ethereum (0x) address\t%s\n", r.EthAddress)
+	for _, b := range r.Balances {
+		fmt.Fprintf(w, "Balance (%s)\t%s\n", b.Denom, b.Display)
+	}
+}
+
 func getValidatorBalanceCmdLogic(mynode string) error {
 	//Load env
 	err := godotenv.Load(filepath.Join(mynode, ".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load .env: %v", err)
+		return envFileError(filepath.Join(mynode, ".env"), err)
 	}
 
-	fmt.Println()
-	getAddr := exec.Command(Mrmintd, "keys", "show", mynode, "-a", "--home", mynode, "--keyring-backend", "test")
-	addrOut, err := getAddr.Output()
+	ethm1Address, err := keyAddress(mynode)
 	if err != nil {
 		return err
 	}
-	ethm1Address := strings.TrimSpace(string(addrOut))
-	getBalanceCmdLogic(ethm1Address)
-	return err
+	ethAddress, err := Bech32ToEthAddress(ethm1Address)
+	if err != nil {
+		return err
+	}
+
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
+	bootRpc := configCliParams.BootNodeRpc
+	if bootRpc == "" {
+		if bootRpc, err = requireEnv("BOOT_NODE_RPC"); err != nil {
+			return err
+		}
+	}
+	balances, err := queryBalances(ethm1Address, bootRpc, "balances")
+	if err != nil {
+		return err
+	}
+
+	md := denomMetadata(bootRpc)
+	return render(ValidatorBalanceResult{
+		Address:    ethm1Address,
+		EthAddress: ethAddress,
+		Balances:   splitBalances(balances, balances, md),
+	})
 }
 
 func stakeFundCmd() *cobra.Command {
 	var mynode string
+	var txOpts TxOptions
 
 	cmd := &cobra.Command{
 		Use:   "stake",
 		Short: "Stake the Ethermint node and update staking status on the platform",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return checkBlockBeforeStake(mynode)
+			return checkBlockBeforeStake(mynode, txOpts)
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
+	addTxFlags(cmd, &txOpts)
 	return cmd
 }
 
@@ -534,7 +697,7 @@ type BlockResponse struct {
 	} `json:"block"`
 }
 
-func checkBlockBeforeStake(mynode string) error {
+func checkBlockBeforeStake(mynode string, txOpts TxOptions) error {
 	// Step 1: Check if the validator has been registered with the platform first.
 	receiptPath := filepath.Join(mynode, ".validator-registered")
 	if _, err := os.Stat(receiptPath); os.IsNotExist(err) {
@@ -549,27 +712,33 @@ func checkBlockBeforeStake(mynode string) error {
 
 	err := godotenv.Load(filepath.Join(mynode, ".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load .env: %v", err)
+		return envFileError(filepath.Join(mynode, ".env"), err)
+	}
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
+	bootRpc, err := requireEnv("BOOT_NODE_RPC")
+	if err != nil {
+		return err
 	}
-	rpcPort := getEnvOrFail("RPC_PORT")
-	bootRpc := getEnvOrFail("BOOT_NODE_RPC")
 
-	outputLocal, err := runCmdCaptureOutput("docker", "exec", "-i", mynode, Mrmintd, "query", "block", "--node", "http://localhost:"+rpcPort)
+	outputLocal, err := runCmdCaptureOutput("docker", "exec", "-i", nodeContainer(mynode), containerMrmintd, "query", "block", "--node", "http://localhost:"+rpcPort)
 	if err != nil {
 		log.Errorf("Query block command error : %s \n", outputLocal)
-		return err
+		return &ChainQueryError{Query: "query the latest block of the node", Err: err}
 	}
 
-	outputBootNode, err := runCmdCaptureOutput("docker", "exec", "-i", mynode, Mrmintd, "query", "block", "--node", bootRpc)
+	outputBootNode, err := runCmdCaptureOutput("docker", "exec", "-i", nodeContainer(mynode), containerMrmintd, "query", "block", "--node", bootRpc)
 	if err != nil {
 		log.Errorf("Query block command error : %s \n", outputBootNode)
-		return err
+		return &ChainQueryError{Query: "query the latest block of the boot node", Err: err, Hint: "check BOOT_NODE_RPC in the node .env"}
 	}
 
 	var res BlockResponse
 	err = json.Unmarshal([]byte(outputBootNode), &res)
 	if err != nil {
-		panic(err)
+		return &ChainQueryError{Query: "parse the boot node block", Err: err}
 	}
 
 	bootBlockInt := new(big.Int)
@@ -581,7 +750,7 @@ func checkBlockBeforeStake(mynode string) error {
 	var resLocal BlockResponse
 	err = json.Unmarshal([]byte(outputLocal), &resLocal)
 	if err != nil {
-		panic(err)
+		return &ChainQueryError{Query: "parse the node block", Err: err}
 	}
 
 	localBlockInt := new(big.Int)
@@ -596,24 +765,21 @@ func checkBlockBeforeStake(mynode string) error {
 	bootBlockInt = new(big.Int).Sub(bootBlockInt, big.NewInt(5))
 
 	if localBlockInt.Int64() < bootBlockInt.Int64() {
-		log.Error("Please wait for complete syncing then stake fund for validator")
-		return err
+		return &ChainQueryError{
+			Query: "stake",
+			Err:   fmt.Errorf("node is at height %s, boot node at %s", localBlockInt, res.Block.Header.Height),
+			Hint:  "wait for the node to finish syncing, then stake again",
+		}
 	}
 	log.Info("\xE2\x9C\x94 The node is properly synced with the bootnode!")
 
 	// Prompt for email now that the node is synced.
-	reader := bufio.NewReader(os.Stdin)
-	fmt.Print("Enter your registered platform email address: ")
-	email, err := reader.ReadString('\n')
+	email, err := promptEmail()
 	if err != nil {
-		return fmt.Errorf("failed to read email: %w", err)
-	}
-	email = strings.TrimSpace(email)
-	if email == "" {
-		return fmt.Errorf("email cannot be empty")
+		return err
 	}
 
-	return stakeFundCmdLogic(mynode, email)
+	return stakeFundCmdLogic(mynode, email, txOpts)
 }
 
 type DepositParams struct {
@@ -623,68 +789,129 @@ type DepositParams struct {
 	} `yaml:"min_deposit"`
 }
 
-func stakeFundCmdLogic(mynode, email string) error {
-	configCliParams = getConfigCliParams() // Ensure config is loaded
-
-	getAddr := exec.Command(Mrmintd, "keys", "show", mynode, "-a", "--home", mynode, "--keyring-backend", "test")
-	addrOut, err := getAddr.Output()
-	if err != nil {
-		log.Errorf("Failed to get address for %s: %v", mynode, err)
+func stakeFundCmdLogic(mynode, email string, txOpts TxOptions) error {
+	// Ensure config is loaded
+	if err := loadConfigCliParams(); err != nil {
 		return err
 	}
-	ethm1Address := strings.TrimSpace(string(addrOut))
-	if ethm1Address == "" {
-		log.Fatalf("Ethm1 address not found for key '%s'. Please ensure the key exists.", mynode)
-	}
 
-	ethAddress, err := Bech32ToEthAddress(ethm1Address)
+	ethm1Address, ethAddress, err := validatorWallet(mynode, txOpts)
 	if err != nil {
-		log.Fatalf("Invalid bech32 address derived for QR: %v", err)
+		return err
 	}
 
 	log.Info("Its your validator wallet for staking: ")
 	log.Infof("Default ethm1 format : %s", ethm1Address)
 	log.Infof("Converted into Ethereum(0x) format : %s", ethAddress)
 
+	md := denomMetadata(configCliParams.BootNodeRpc)
+	requiredDeposit, err := stakeDepositAmount(md, txOpts)
+	if err != nil {
+		return err
+	}
+
 	// Generate QR Code
-	qrterminal.GenerateHalfBlock(ethAddress, qrterminal.L, os.Stdout)
-	log.Infof("📲 QR Code (scan it securely): Please send %d MNT coin to your validator wallet for validator staking.", configCliParams.MinStakeFund)
+	qrterminal.GenerateHalfBlock(ethAddress, qrterminal.L, os.Stderr)
+	log.Infof("📲 QR Code (scan it securely): Please send %s to your validator wallet for validator staking.", md.Format(requiredDeposit))
 
 	// Get confirmation for payment
-	getConfirmationForPayment("Have you deposited MNT?", ethm1Address)
+	if err := getConfirmationForPayment("Have you deposited MNT?", ethm1Address, requiredDeposit); err != nil {
+		return err
+	}
 
 	log.Info("✅ Funds deposit confirmation received. Proceeding with staking setup...")
 
-	rpcPort := getEnvOrFail("RPC_PORT")
+	result, err := createValidatorTx(mynode, email, txOpts)
+	if err != nil || result == nil {
+		return err
+	}
+	return render(result)
+}
+
+// validatorWallet returns the ethm1 and 0x addresses of the account that
+// creates the validator: the node key, or the --signer of a generated
+// transaction.
+func validatorWallet(mynode string, txOpts TxOptions) (string, string, error) {
+	ethm1Address, err := txSignerAddress(mynode, txOpts)
+	if err != nil {
+		return "", "", err
+	}
+
+	ethAddress, err := Bech32ToEthAddress(ethm1Address)
+	if err != nil {
+		return "", "", &ChainQueryError{Query: "read the validator address", Err: err}
+	}
+	return ethm1Address, ethAddress, nil
+}
+
+// stakeDepositAmount is what the validator wallet must hold before staking:
+// the minimum stake plus a fee reserve, or only the stake when a fee granter
+// pays the fees.
+func stakeDepositAmount(md denom.Metadata, txOpts TxOptions) (*big.Int, error) {
+	requiredDeposit, err := minStakeFundAmount(md)
+	if err != nil {
+		return nil, err
+	}
+	if txOpts.FeeGranter != "" {
+		log.Infof("Transaction fees will be paid by fee granter %s", txOpts.FeeGranter)
+		return requiredDeposit, nil
+	}
+	feeReserve, err := denom.Parse(stakeFeeReserve, md)
+	if err != nil {
+		return nil, err
+	}
+	return requiredDeposit.Add(requiredDeposit, feeReserve.Amount), nil
+}
+
+// createValidatorTx submits the create-validator transaction of a funded
+// node and updates the staking status on the platform. The result is nil
+// when the transaction was only generated.
+func createValidatorTx(mynode, email string, txOpts TxOptions) (*StakeResult, error) {
+	ethm1Address, ethAddress, err := validatorWallet(mynode, txOpts)
+	if err != nil {
+		return nil, err
+	}
+	md := denomMetadata(configCliParams.BootNodeRpc)
+
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return nil, err
+	}
 
-	fmt.Println()
-	output, err := runCmdCaptureOutput("docker", "exec", "-i", mynode, Mrmintd, "query", "gov", "param", "deposit", "--node", "tcp://localhost:"+rpcPort)
+	fmt.Fprintln(os.Stderr)
+	output, err := runCmdCaptureOutput("docker", "exec", "-i", nodeContainer(mynode), containerMrmintd, "query", "gov", "param", "deposit", "--node", "tcp://localhost:"+rpcPort)
 	if err != nil {
-		log.Fatalf("failed to get deposit params: %v", err)
+		return nil, &ChainQueryError{Query: "get deposit params", Err: err}
 	}
 	var cResp DepositParams // Assuming DepositParams struct is defined elsewhere
 
 	err = yaml.Unmarshal([]byte(output), &cResp)
 	if err != nil {
-		log.Errorf("Failed to unmarshal deposit params: %s", err)
-		return err // Return error if unmarshaling fails
+		return nil, &ChainQueryError{Query: "parse deposit params", Err: err}
+	}
+	if len(cResp.MinDeposit) == 0 {
+		return nil, &ChainQueryError{Query: "parse deposit params", Err: fmt.Errorf("no minimum deposit in %q", strings.TrimSpace(output))}
 	}
 
-	log.Infof("Minimum Deposit for Staking: %s%s", cResp.MinDeposit[0].Amount, cResp.MinDeposit[0].Denom)
-	fmt.Println()
+	log.Infof("Minimum Deposit for Staking: %s (%s%s)", md.FormatString(cResp.MinDeposit[0].Amount), cResp.MinDeposit[0].Amount, cResp.MinDeposit[0].Denom)
+	fmt.Fprintln(os.Stderr)
 
 	_, balance := getBalanceCmdLogic(ethm1Address)
-	log.Printf("Current wallet balance: %d MNT (for wallet: %s)", balance, ethAddress) // Clarified log message
+	log.Printf("Current wallet balance: %s (for wallet: %s)", md.Format(balance), ethAddress) // Clarified log message
 
-	pubkey, err := runCmdCaptureOutput("docker", "exec", "-i", mynode, Mrmintd, "tendermint", "show-validator", "--home", mynode)
+	pubkey, err := runCmdCaptureOutput("docker", "exec", "-i", nodeContainer(mynode), containerMrmintd, "tendermint", "show-validator", "--home", mynode)
 	if err != nil {
-		log.Fatalf("Failed to get validator pubkey: %v", err) // Clarified log message
+		return nil, &RuntimeError{Msg: "failed to get validator pubkey", Err: err, Hint: "make sure the node container is running ('start-node --mynode " + mynode + "')"}
 	}
 	pubkey = strings.TrimSpace(pubkey)
 
-	if !yesNo("Are you ready to proceed now for creating the validator staking transaction?") { // Clarified prompt
+	proceed, err := yesNo("Are you ready to proceed now for creating the validator staking transaction?") // Clarified prompt
+	if err != nil {
+		return nil, err
+	}
+	if !proceed {
 		log.Info("Staking process cancelled!")
-		return fmt.Errorf("staking process cancelled by user") // Return a proper error
+		return nil, &UserAbort{Msg: "staking process cancelled by user"}
 	}
 
 	commissionRate := getStakingInputs("Please enter commission rate (e.g., 0.30 for 30%):", "0.30") // Clarified prompt
@@ -696,14 +923,13 @@ func stakeFundCmdLogic(mynode, email string) error {
 	commissionMaxChangeRate := getStakingInputs("Please enter daily maximum commission change rate (e.g., 0.05 for 5% change per day):", "0.05") // Clarified prompt
 	log.Infof("✅ Maximum Daily Commission Change Rate: %s", commissionMaxChangeRate)
 
-	fmt.Println()
+	fmt.Fprintln(os.Stderr)
 	log.Print("🔑 Preparing staking transaction. Press Enter to continue...")
 	fmt.Scanln()
 
-	output, err = runCmdCaptureOutput("docker", "exec", "-i", mynode,
-		Mrmintd,
+	txArgs := []string{
 		"tx", "staking", "create-validator",
-		"--amount", cResp.MinDeposit[0].Amount+""+cResp.MinDeposit[0].Denom, // Amount for self-delegation from deposit param
+		"--amount", cResp.MinDeposit[0].Amount + "" + cResp.MinDeposit[0].Denom, // Amount for self-delegation from deposit param
 		"--pubkey", pubkey,
 		"--home", mynode, // Home path inside the Docker container
 		"--moniker", mynode,
@@ -711,23 +937,50 @@ func stakeFundCmdLogic(mynode, email string) error {
 		"--commission-rate", commissionRate,
 		"--commission-max-rate", commissionMaxRate,
 		"--commission-max-change-rate", commissionMaxChangeRate,
-		"--min-self-delegation=1", // This is usually 1 unit of smallest denom
-		"--from", mynode,          // Key name for signing
+		"--min-self-delegation=1",     // This is usually 1 unit of smallest denom
+		"--from", nodeKeyName(mynode), // Key name for signing
 		"--keyring-backend=test",
 		"--home", mynode, // This --home is for the keys backend context inside container
-		"--node", "tcp://localhost:"+rpcPort,
+		"--node", "tcp://localhost:" + rpcPort,
 		"--gas-prices", "14mnt", // Ensure this matches your chain's accepted gas denom
 		"--gas", "auto",
 		"--gas-adjustment", "1.2",
-		"--yes", // Auto-confirm transaction
-	)
+	}
+	if txOpts.GenerateOnly {
+		// Generated locally, so the container's keyring is never needed.
+		if err := generateUnsignedTx(mynode, txOpts, "create-validator", "tcp://localhost:"+rpcPort, Mrmintd, append(txArgs, "--chain-id", configCliParams.ChaindId)...); err != nil {
+			return nil, err
+		}
+		log.Info("The platform staking status will not be updated until the signed transaction is broadcast.")
+		return nil, nil
+	}
+
+	createValidatorArgs := append([]string{"exec", "-i", nodeContainer(mynode), containerMrmintd}, txOpts.apply(txArgs)...)
+	output, err = runCmdCaptureOutput("docker", append(createValidatorArgs, "--yes")...) // Auto-confirm transaction
 	if err != nil {
 		log.Errorf("❌ Stake command failed: %s", output)
-		return err
+		return nil, txError("stake", output, err)
 	}
 	log.Infof("✅ Stake Transaction Output: %s", output)
-	fmt.Println()
+	fmt.Fprintln(os.Stderr)
 	log.Infof("You can copy the staking transaction hash from above and check its details on an explorer or use the 'query-tx' command.")
+
+	tx, err := parseTxResult(output)
+	if err != nil {
+		log.Warnf("⚠️ Could not parse the staking transaction result: %v", err)
+	}
+	if err := txError("stake", output, nil); err != nil {
+		return nil, err
+	}
+	result := StakeResult{
+		Moniker:    mynode,
+		Address:    ethm1Address,
+		EthAddress: ethAddress,
+		Amount:     cResp.MinDeposit[0].Amount + cResp.MinDeposit[0].Denom,
+		Display:    md.FormatString(cResp.MinDeposit[0].Amount),
+		Tx:         tx,
+	}
+
 	// After successful on-chain staking, update the backend database.
 	log.Info("🔄 Updating validator staking status...")
 	if err := updateValidatorStakingInfoAPI(email); err != nil {
@@ -736,8 +989,29 @@ func stakeFundCmdLogic(mynode, email string) error {
 		log.Warnf("The on-chain staking was successful, but please notify the platform administrator to update your status manually.")
 	} else {
 		log.Info("✅ Validator staking status successfully updated...")
+		result.PlatformUpdated = true
 	}
-	return nil
+	return &result, nil
+}
+
+// StakeResult is the result of the stake command.
+type StakeResult struct {
+	Moniker         string   `json:"moniker" yaml:"moniker"`
+	Address         string   `json:"address" yaml:"address"`
+	EthAddress      string   `json:"eth_address" yaml:"eth_address"`
+	Amount          string   `json:"amount" yaml:"amount"`
+	Display         string   `json:"display" yaml:"display"`
+	Tx              TxResult `json:"tx" yaml:"tx"`
+	PlatformUpdated bool     `json:"platform_updated" yaml:"platform_updated"`
+}
+
+func (r StakeResult) renderTable(w io.Writer) {
+	fmt.Fprintf(w, "Moniker\t%s\n", r.Moniker)
+	fmt.Fprintf(w, "Address\t%s\n", r.Address)
+	fmt.Fprintf(w, "Ethereum (0x) address\t%s\n", r.EthAddress)
+	fmt.Fprintf(w, "Staked\t%s\n", r.Display)
+	r.Tx.renderTable(w)
+	fmt.Fprintf(w, "Platform updated\t%t\n", r.PlatformUpdated)
 }
 
 func updateValidatorStakingInfoAPI(email string) error {
@@ -778,12 +1052,13 @@ func getValidatorStatusCmd() *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
 	return cmd
 }
 
 func unjailCmd() *cobra.Command {
 	var mynode string
+	var txOpts TxOptions
 
 	cmd := &cobra.Command{
 		Use:   "unjail",
@@ -791,41 +1066,47 @@ func unjailCmd() *cobra.Command {
 		Long: `Sends an unjail transaction to bring a jailed validator back online.
 The validator must have sufficient funds to cover the transaction fees.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return unjailCmdLogic(mynode)
+			return unjailCmdLogic(mynode, txOpts)
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (key name for the jailed validator account)")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
+	addTxFlags(cmd, &txOpts)
+	addGranteeFlag(cmd, &txOpts)
 	return cmd
 }
 
-func unjailCmdLogic(mynode string) error {
-	configCliParams = getConfigCliParams()
+func unjailCmdLogic(mynode string, txOpts TxOptions) error {
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
 
 	err := godotenv.Load(filepath.Join(mynode, ".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load node-specific .env for '%s': %v", mynode, err)
+		return envFileError(filepath.Join(mynode, ".env"), err)
 	}
 
 	err = godotenv.Load(filepath.Join(".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load global .env: %v", err)
+		return envFileError(".env", err)
 	}
 
-	rpcPort := getEnvOrFail("RPC_PORT")
-
-	getDelegatorAddrCmd := exec.Command(Mrmintd, "keys", "show", mynode, "-a", "--home", mynode, "--keyring-backend", "test")
-	delegatorAddrOut, err := getDelegatorAddrCmd.Output()
+	rpcPort, err := requireEnv("RPC_PORT")
 	if err != nil {
-		log.Errorf("Failed to get delegator address for '%s': %s\nOutput: %s", mynode, err, string(delegatorAddrOut))
 		return err
 	}
-	delegatorAddress := strings.TrimSpace(string(delegatorAddrOut))
+
+	delegatorAddress := txOpts.Granter
+	if txOpts.AsGrantee == "" {
+		if delegatorAddress, err = txSignerAddress(mynode, txOpts); err != nil {
+			return err
+		}
+	}
 
 	log.Infof("Attempting to unjail validator using key '%s' (delegator address: %s)", mynode, delegatorAddress)
 	log.Infof("Sending unjail transaction to local node RPC: tcp://localhost:%s", rpcPort)
 
-	output, err := runCmdCaptureOutput(Mrmintd,
+	txArgs := []string{
 		"tx", "slashing", "unjail",
 		"--from", delegatorAddress,
 		"--home", mynode, // Home path for keyring and node data
@@ -834,20 +1115,27 @@ func unjailCmdLogic(mynode string) error {
 		"--gas", "auto",
 		"--gas-prices", "7mnt", // Automatically estimate gas required
 		"--gas-adjustment", "1.4", // Add a buffer to gas estimate
-		"--node", "tcp://localhost:"+rpcPort, // Target your local node's RPC
-		"--yes", // Automatically confirm the transaction
-	)
+		"--node", "tcp://localhost:" + rpcPort, // Target your local node's RPC
+	}
+	if txOpts.AsGrantee != "" {
+		return execAsGrantee(mynode, txOpts, "unjail", "tcp://localhost:"+rpcPort, txArgs...)
+	}
+	if txOpts.GenerateOnly {
+		return generateUnsignedTx(mynode, txOpts, "unjail", "tcp://localhost:"+rpcPort, Mrmintd, txArgs...)
+	}
+
+	output, err := runCmdCaptureOutput(Mrmintd, append(txOpts.apply(txArgs), "--yes")...)
 
 	if err != nil {
 		log.Errorf("❌ Failed to unjail validator '%s': %s\nOutput: %s", mynode, err, output)
 		log.Warnf("Please ensure your validator is actually jailed and has sufficient funds for transaction fees.")
-		return err
+		return txError("unjail", output, err)
 	}
 
 	log.Infof("✅ Validator '%s' unjail transaction sent successfully! Transaction output:\n%s", mynode, output)
 	log.Info("Great!You unjail yourself, Please monitor the chain and verify your validator's status using 'mrmintchain validator-info --mynode %s' after a few blocks.", mynode)
 
-	return nil
+	return renderTxOutput("unjail", output)
 }
 
 type ValidatorDevKey []struct {
@@ -855,53 +1143,117 @@ type ValidatorDevKey []struct {
 }
 
 type ValidatorDevInfo struct {
-	Status string `yaml:"status"`
+	OperatorAddress string `json:"operator_address"`
+	Jailed          bool   `json:"jailed"`
+	Status          string `json:"status"`
+	Tokens          string `json:"tokens"`
+	DelegatorShares string `json:"delegator_shares"`
+	Description     struct {
+		Moniker string `json:"moniker"`
+	} `json:"description"`
+	Commission struct {
+		CommissionRates struct {
+			Rate          string `json:"rate"`
+			MaxRate       string `json:"max_rate"`
+			MaxChangeRate string `json:"max_change_rate"`
+		} `json:"commission_rates"`
+	} `json:"commission"`
+}
+
+// ValidatorInfoResult is the result of the validator-info command.
+type ValidatorInfoResult struct {
+	OperatorAddress string `json:"operator_address" yaml:"operator_address"`
+	Moniker         string `json:"moniker" yaml:"moniker"`
+	Status          string `json:"status" yaml:"status"`
+	Active          bool   `json:"active" yaml:"active"`
+	Jailed          bool   `json:"jailed" yaml:"jailed"`
+	Tokens          string `json:"tokens" yaml:"tokens"`
+	TokensDisplay   string `json:"tokens_display" yaml:"tokens_display"`
+	DelegatorShares string `json:"delegator_shares" yaml:"delegator_shares"`
+	CommissionRate  string `json:"commission_rate" yaml:"commission_rate"`
+	MaxRate         string `json:"commission_max_rate" yaml:"commission_max_rate"`
+	MaxChangeRate   string `json:"commission_max_change_rate" yaml:"commission_max_change_rate"`
+}
+
+func (r ValidatorInfoResult) renderTable(w io.Writer) {
+	fmt.Fprintf(w, "Operator address\t%s\n", r.OperatorAddress)
+	fmt.Fprintf(w, "Moniker\t%s\n", r.Moniker)
+	fmt.Fprintf(w, "Status\t%s\n", r.Status)
+	fmt.Fprintf(w, "Jailed\t%t\n", r.Jailed)
+	fmt.Fprintf(w, "Tokens\t%s\n", r.TokensDisplay)
+	fmt.Fprintf(w, "Commission rate\t%s (max %s, max change %s)\n", r.CommissionRate, r.MaxRate, r.MaxChangeRate)
 }
 
 func getValidatorStatusCmdLogic(mynode string) error {
 
 	err := godotenv.Load(filepath.Join(mynode, ".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load .env: %v", err)
+		return envFileError(filepath.Join(mynode, ".env"), err)
 	}
-	rpcPort := getEnvOrFail("RPC_PORT")
-
-	getAddr := exec.Command(Mrmintd, "keys", "show", mynode, "--bech", "val", "--home", mynode, "--keyring-backend", "test")
-	output, err := getAddr.Output()
+	rpcPort, err := requireEnv("RPC_PORT")
 	if err != nil {
-		log.Errorf("Key show command failed : %s", string(output))
+		return err
 	}
-	var cResp ValidatorDevKey
 
-	err = yaml.Unmarshal(output, &cResp)
+	valoper, err := validatorOperatorAddress(mynode)
 	if err != nil {
-		log.Errorf("Get balance command failed: %s", err)
+		return err
 	}
 
-	outputInfo, err := runCmdCaptureOutput("docker", "exec", "-i", mynode, Mrmintd, "query", "staking", "validator", cResp[0].Address, "--node", "http://localhost:"+rpcPort, "--output", "json")
+	outputInfo, err := runCmdCaptureOutput("docker", "exec", "-i", nodeContainer(mynode), containerMrmintd, "query", "staking", "validator", valoper, "--node", "http://localhost:"+rpcPort, "--output", "json")
 	if err != nil {
-		log.Fatalf("Failed to get validator info : %s", outputInfo)
+		log.Errorf("Failed to get validator info : %s", outputInfo)
 		return err
 	}
 
-	var cRespp ValidatorDevInfo
-	err = yaml.Unmarshal([]byte(outputInfo), &cRespp)
+	info, err := parseValidatorInfo(outputInfo)
 	if err != nil {
-		log.Errorf("Get balance command failed: %s", err)
+		log.Errorf("Failed to parse validator info: %s", err)
+		return err
 	}
-	log.Infof("Validator details in JSON : %s", outputInfo)
-	fmt.Println()
-	if cRespp.Status == "BOND_STATUS_BONDED" {
+	if info.Status == "BOND_STATUS_BONDED" {
 		log.Info("\xE2\x9C\x94 Validator is active!")
 	}
-	if cRespp.Status == "BOND_STATUS_UNBONDED" {
+	if info.Status == "BOND_STATUS_UNBONDED" {
 		log.Info("Validator is de-active!")
 	}
-	return err
+
+	return render(ValidatorInfoResult{
+		OperatorAddress: info.OperatorAddress,
+		Moniker:         info.Description.Moniker,
+		Status:          info.Status,
+		Active:          info.Status == "BOND_STATUS_BONDED",
+		Jailed:          info.Jailed,
+		Tokens:          info.Tokens,
+		TokensDisplay:   denomMetadata("tcp://localhost:" + rpcPort).FormatString(info.Tokens),
+		DelegatorShares: info.DelegatorShares,
+		CommissionRate:  info.Commission.CommissionRates.Rate,
+		MaxRate:         info.Commission.CommissionRates.MaxRate,
+		MaxChangeRate:   info.Commission.CommissionRates.MaxChangeRate,
+	})
+}
+
+// parseValidatorInfo reads "query staking validator" output, which newer SDK
+// versions wrap in a "validator" object.
+func parseValidatorInfo(output string) (ValidatorDevInfo, error) {
+	var info ValidatorDevInfo
+	data, err := extractJSON(output)
+	if err != nil {
+		return info, err
+	}
+	var wrapped struct {
+		Validator *ValidatorDevInfo `json:"validator"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err == nil && wrapped.Validator != nil {
+		return *wrapped.Validator, nil
+	}
+	err = json.Unmarshal(data, &info)
+	return info, err
 }
 
 func setWithdrawAddress() *cobra.Command {
 	var mynode string
+	var txOpts TxOptions
 	var address string
 
 	cmd := &cobra.Command{
@@ -910,7 +1262,7 @@ func setWithdrawAddress() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// Prompt for email
 			reader := bufio.NewReader(os.Stdin)
-			fmt.Print("Enter your registered platform email address: ")
+			fmt.Fprint(os.Stderr, "Enter your registered platform email address: ")
 			email, err := reader.ReadString('\n')
 			if err != nil {
 				return fmt.Errorf("failed to read email: %w", err)
@@ -919,62 +1271,72 @@ func setWithdrawAddress() *cobra.Command {
 			if email == "" {
 				return fmt.Errorf("email cannot be empty")
 			}
-			return setWithdrawAddressLogic(mynode, address, email)
+			return setWithdrawAddressLogic(mynode, address, email, txOpts)
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
 
 	cmd.Flags().StringVar(&address, "address", "", "The bech32 wallet address to set for withdrawals")
 	cmd.MarkFlagRequired("address")
 
+	addTxFlags(cmd, &txOpts)
 	return cmd
 }
 
-func setWithdrawAddressLogic(mynode, address, email string) error {
-	configCliParams = getConfigCliParams()
+func setWithdrawAddressLogic(mynode, address, email string, txOpts TxOptions) error {
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
 
 	err := godotenv.Load(filepath.Join(mynode, ".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load node-specific .env for '%s': %v", mynode, err)
+		return envFileError(filepath.Join(mynode, ".env"), err)
 	}
 
 	err = godotenv.Load(filepath.Join(".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load global .env: %v", err)
+		return envFileError(".env", err)
 	}
 
-	rpcPort := getEnvOrFail("RPC_PORT") // Get RPC port from loaded .env
+	rpcPort, err := requireEnv("RPC_PORT") // Get RPC port from loaded .env
+	if err != nil {
+		return err
+	}
 
-	getAddr := exec.Command(Mrmintd, "keys", "show", mynode, "-a", "--home", mynode, "--keyring-backend", "test")
-	addrOut, err := getAddr.Output()
+	validatorDelegatorAddress, err := txSignerAddress(mynode, txOpts)
 	if err != nil {
-		log.Errorf("Failed to get validator address for '%s': %s\nOutput: %s", mynode, err, string(addrOut))
 		return err
 	}
-	validatorDelegatorAddress := strings.TrimSpace(string(addrOut))
 
 	log.Infof("Attempting to set withdraw address for validator '%s' (delegator address: %s) to '%s'", mynode, validatorDelegatorAddress, address)
 
-	output, err := runCmdCaptureOutput(Mrmintd,
+	txArgs := []string{
 		"tx", "distribution", "set-withdraw-addr", address,
-		"--from", mynode,
+		"--from", nodeKeyName(mynode),
 		"--home", mynode,
 		"--chain-id", configCliParams.ChaindId,
 		"--gas-prices", "7mnt",
 		"--keyring-backend", "test",
 		"--gas-adjustment", "1.1",
-		"--node", "tcp://localhost:"+rpcPort,
-		"--yes",
-	)
+		"--node", "tcp://localhost:" + rpcPort,
+	}
+	if txOpts.GenerateOnly {
+		return generateUnsignedTx(mynode, txOpts, "set-withdraw-addr", "tcp://localhost:"+rpcPort, Mrmintd, txArgs...)
+	}
+
+	output, err := runCmdCaptureOutput(Mrmintd, append(txOpts.apply(txArgs), "--yes")...)
 
 	if err != nil {
 		log.Errorf("❌ Failed to set withdraw address for '%s': %s\nOutput: %s", mynode, err, output)
-		return err
+		return txError("set withdraw address", output, err)
 	}
 
 	log.Infof("✅ Withdraw address set successfully! Transaction output:\n%s", output)
 	log.Info("Please monitor the chain to confirm the on-chain transaction.")
+	if err := renderTxOutput("set withdraw address", output); err != nil {
+		return err
+	}
 
 	// Step 2: Update the withdraw address on the platform via API.
 	log.Info("🔄 Updating withdraw address...")
@@ -985,10 +1347,10 @@ func setWithdrawAddressLogic(mynode, address, email string) error {
 		return nil // Don't fail the command
 	}
 
-	// Create request body
+	// Create request body. The platform stores addresses lower case.
 	requestBody, err := json.Marshal(map[string]string{
 		"email":                    email,
-		"validatorWithdrawAddress": withdrawEthAddress,
+		"validatorWithdrawAddress": strings.ToLower(withdrawEthAddress),
 	})
 	if err != nil {
 		log.Warnf("⚠️ Failed to create API request payload: %v", err)
@@ -1016,74 +1378,72 @@ func setWithdrawAddressLogic(mynode, address, email string) error {
 
 func delegateSelfStakeCmd() *cobra.Command {
 	var mynode string
+	var txOpts TxOptions
 	var amount string
 
 	cmd := &cobra.Command{
 		Use:   "self-delegate",
 		Short: "Delegate more tokens to your validator (increase self-delegation)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return delegateSelfStakeLogic(mynode, amount)
+			return delegateSelfStakeLogic(mynode, amount, txOpts)
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (key name for your validator account)")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
 
-	cmd.Flags().StringVar(&amount, "amount", "", "Amount of tokens to delegate (e.g., 1000000000000000000mnt)")
+	cmd.Flags().StringVar(&amount, "amount", "", "Amount of tokens to delegate ("+amountHelp+")")
 	cmd.MarkFlagRequired("amount")
 
+	addTxFlags(cmd, &txOpts)
+	addGranteeFlag(cmd, &txOpts)
 	return cmd
 }
 
-func delegateSelfStakeLogic(mynode string, amount string) error {
-	configCliParams = getConfigCliParams() // Ensure config is loaded
-
-	err := godotenv.Load(filepath.Join(mynode, ".env"))
-	if err != nil {
-		log.Fatalf("❌ Failed to load .env: %v", err)
+func delegateSelfStakeLogic(mynode string, amount string, txOpts TxOptions) error {
+	// Ensure config is loaded
+	if err := loadConfigCliParams(); err != nil {
+		return err
 	}
-	rpcPort := getEnvOrFail("RPC_PORT")
 
-	getDelegatorAddrCmd := exec.Command(Mrmintd, "keys", "show", mynode, "-a", "--home", mynode, "--keyring-backend", "test")
-	delegatorAddrOut, err := getDelegatorAddrCmd.Output()
+	err := godotenv.Load(filepath.Join(mynode, ".env"))
 	if err != nil {
-		log.Errorf("Failed to get delegator address: %s\nOutput: %s", err, string(delegatorAddrOut))
-		return err
+		return envFileError(filepath.Join(mynode, ".env"), err)
 	}
-	validatorDelegatorAddress := strings.TrimSpace(string(delegatorAddrOut))
-
-	getValidatorOperatorAddrCmd := exec.Command(Mrmintd, "keys", "show", mynode, "--bech", "val", "--home", mynode, "--keyring-backend", "test")
-	validatorOperatorAddrOut, err := getValidatorOperatorAddrCmd.Output()
+	rpcPort, err := requireEnv("RPC_PORT")
 	if err != nil {
-		log.Errorf("Failed to get validator operator address: %s\nOutput: %s", err, string(validatorOperatorAddrOut))
 		return err
 	}
 
-	var keyInfo []struct {
-		Address string `yaml:"address"`
-	}
-
-	err = yaml.Unmarshal(validatorOperatorAddrOut, &keyInfo)
+	amount, err = parseAmountFlag("amount", amount, "tcp://localhost:"+rpcPort)
 	if err != nil {
-		log.Errorf("Failed to parse validator operator address output: %s\nOutput: %s", err, string(validatorOperatorAddrOut))
 		return err
 	}
 
-	if len(keyInfo) == 0 || keyInfo[0].Address == "" {
-		log.Errorf("Could not find validator operator address in output: %s", string(validatorOperatorAddrOut))
-		return fmt.Errorf("validator operator address not found")
+	// Through a grant the owner key is only known by its --granter address.
+	validatorDelegatorAddress := txOpts.Granter
+	var validatorOperatorAddress string
+	if txOpts.AsGrantee != "" {
+		if validatorOperatorAddress, err = granterOperatorAddress(txOpts.Granter); err != nil {
+			return err
+		}
+	} else {
+		if validatorDelegatorAddress, err = txSignerAddress(mynode, txOpts); err != nil {
+			return err
+		}
+		if validatorOperatorAddress, err = txOperatorAddress(mynode, txOpts); err != nil {
+			return err
+		}
 	}
 
-	validatorOperatorAddress := keyInfo[0].Address
-
 	validatorStatusOutput, err := runCmdCaptureOutput(Mrmintd, "query", "staking", "validator", validatorOperatorAddress, "--node", "tcp://localhost:"+rpcPort, "--output", "json")
 	if err != nil {
 
 		if strings.Contains(validatorStatusOutput, "not found") || strings.Contains(validatorStatusOutput, "no such validator") {
 			log.Errorf("❌ Validator '%s' not found on chain. Please create your validator first using the 'stake' command.", mynode)
-			return fmt.Errorf("validator not found on chain")
+			return &ChainQueryError{Query: "query validator " + validatorOperatorAddress, Err: fmt.Errorf("validator not found on chain"), Hint: "create the validator first with 'stake --mynode " + mynode + "'"}
 		} else {
 			log.Errorf("❌ Failed to query validator status: %s\nOutput: %s", err, validatorStatusOutput)
-			return err
+			return &ChainQueryError{Query: "query validator " + validatorOperatorAddress, Err: err}
 		}
 	}
 
@@ -1094,7 +1454,7 @@ func delegateSelfStakeLogic(mynode string, amount string) error {
 	err = json.Unmarshal([]byte(validatorStatusOutput), &validatorInfo)
 	if err != nil {
 		log.Errorf("Failed to parse validator status JSON: %s\nOutput: %s", err, validatorStatusOutput)
-		return err
+		return &ChainQueryError{Query: "parse validator " + validatorOperatorAddress, Err: err}
 	}
 
 	if validatorInfo.Status != "BOND_STATUS_BONDED" && validatorInfo.Status != "BOND_STATUS_UNBONDING" {
@@ -1103,7 +1463,7 @@ func delegateSelfStakeLogic(mynode string, amount string) error {
 
 	log.Infof("Attempting to self-delegate '%s' from '%s' to validator '%s'", amount, validatorDelegatorAddress, validatorOperatorAddress)
 
-	output, err := runCmdCaptureOutput(Mrmintd,
+	txArgs := []string{
 		"tx", "staking", "delegate", validatorOperatorAddress, amount,
 		"--from", validatorDelegatorAddress,
 		"--home", mynode,
@@ -1111,21 +1471,29 @@ func delegateSelfStakeLogic(mynode string, amount string) error {
 		"--chain-id", configCliParams.ChaindId,
 		"--gas-prices", "7mnt",
 		"--gas-adjustment", "1.1",
-		"--node", "tcp://localhost:"+rpcPort,
-		"--yes",
-	)
+		"--node", "tcp://localhost:" + rpcPort,
+	}
+	if txOpts.AsGrantee != "" {
+		return execAsGrantee(mynode, txOpts, "delegate", "tcp://localhost:"+rpcPort, txArgs...)
+	}
+	if txOpts.GenerateOnly {
+		return generateUnsignedTx(mynode, txOpts, "delegate", "tcp://localhost:"+rpcPort, Mrmintd, txArgs...)
+	}
+
+	output, err := runCmdCaptureOutput(Mrmintd, append(txOpts.apply(txArgs), "--yes")...)
 
 	if err != nil {
 		log.Errorf("❌ Failed to self-delegate tokens: %s\nOutput: %s", err, output)
-		return err
+		return txError("self-delegate", output, err)
 	}
 
 	log.Infof("✅ Tokens self-delegated successfully! Transaction output:\n%s", output)
-	return nil
+	return renderTxOutput("self-delegate", output)
 }
 
 func unstakeCmd() *cobra.Command {
 	var mynode string
+	var txOpts TxOptions
 	var amount string
 
 	cmd := &cobra.Command{
@@ -1134,130 +1502,142 @@ func unstakeCmd() *cobra.Command {
 		Long: `Initiates the unbonding process for a specified amount of tokens from your validator.
 The tokens will be locked for the unbonding period (e.g., 21 days) before becoming liquid again.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return unstakeCmdLogic(mynode, amount)
+			return unstakeCmdLogic(mynode, amount, txOpts)
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (key name of the validator)")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
 
-	cmd.Flags().StringVar(&amount, "amount", "", "Amount of tokens to unstake (e.g., 50mnt)")
+	cmd.Flags().StringVar(&amount, "amount", "", "Amount of tokens to unstake ("+amountHelp+")")
 	cmd.MarkFlagRequired("amount")
 
+	addTxFlags(cmd, &txOpts)
 	return cmd
 }
 
-func unstakeCmdLogic(mynode string, amount string) error {
-	configCliParams = getConfigCliParams()
+func unstakeCmdLogic(mynode string, amount string, txOpts TxOptions) error {
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
 
 	err := godotenv.Load(filepath.Join(mynode, ".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load node-specific .env for '%s': %v", mynode, err)
+		return envFileError(filepath.Join(mynode, ".env"), err)
 	}
 
 	err = godotenv.Load(filepath.Join(".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load global .env: %v", err)
+		return envFileError(".env", err)
 	}
 
-	rpcPort := getEnvOrFail("RPC_PORT")
-
-	getDelegatorAddrCmd := exec.Command(Mrmintd, "keys", "show", mynode, "-a", "--home", mynode, "--keyring-backend", "test")
-	delegatorAddrOut, err := getDelegatorAddrCmd.Output()
+	rpcPort, err := requireEnv("RPC_PORT")
 	if err != nil {
-		log.Errorf("Failed to get delegator address for '%s': %s\nOutput: %s", mynode, err, string(delegatorAddrOut))
 		return err
 	}
 
-	getValidatorOperatorAddrCmd := exec.Command(Mrmintd, "keys", "show", mynode, "--bech", "val", "--home", mynode, "--keyring-backend", "test")
-	validatorOperatorAddrOut, err := getValidatorOperatorAddrCmd.Output()
+	amount, err = parseAmountFlag("amount", amount, "tcp://localhost:"+rpcPort)
 	if err != nil {
-		log.Errorf("Failed to get validator operator address for '%s': %s\nOutput: %s", mynode, err, string(validatorOperatorAddrOut))
 		return err
 	}
 
-	var keyInfo []struct {
-		Address string `yaml:"address"`
-	}
-
-	err = yaml.Unmarshal(validatorOperatorAddrOut, &keyInfo)
+	validatorOperatorAddress, err := txOperatorAddress(mynode, txOpts)
 	if err != nil {
-		log.Errorf("Failed to parse validator operator address output for '%s': %s\nOutput: %s", mynode, err, string(validatorOperatorAddrOut))
 		return err
 	}
 
-	if len(keyInfo) == 0 || keyInfo[0].Address == "" {
-		log.Errorf("Could not find validator operator address in output for '%s': %s", mynode, string(validatorOperatorAddrOut))
-		return fmt.Errorf("validator operator address not found")
-	}
-
-	validatorOperatorAddress := keyInfo[0].Address // This is the clean ethmvaloper1... address
-
 	log.Infof("Attempting to unstake '%s' from validator '%s' (%s)", amount, mynode, validatorOperatorAddress)
 	log.Infof("Sending undelegation transaction to local node RPC: tcp://localhost:%s", rpcPort)
 
-	output, err := runCmdCaptureOutput(Mrmintd,
+	txArgs := []string{
 		"tx", "staking", "unbond", validatorOperatorAddress, amount,
-		"--from", mynode, // Use the key name for --from flag
+		"--from", nodeKeyName(mynode), // Use the key name for --from flag
 		"--home", mynode, // Pass --home for keyring access
 		"--keyring-backend", "test",
 		"--chain-id", configCliParams.ChaindId,
 		"--gas-prices", "7mnt",
 		"--gas-adjustment", "1.1",
-		"--node", "tcp://localhost:"+rpcPort, // Target your local node's RPC
-		"--yes", // Automatically confirm transaction
-	)
+		"--node", "tcp://localhost:" + rpcPort, // Target your local node's RPC
+	}
+	if txOpts.GenerateOnly {
+		return generateUnsignedTx(mynode, txOpts, "unbond", "tcp://localhost:"+rpcPort, Mrmintd, txArgs...)
+	}
+
+	output, err := runCmdCaptureOutput(Mrmintd, append(txOpts.apply(txArgs), "--yes")...)
 
 	if err != nil {
 		log.Errorf("❌ Failed to unstake tokens from '%s': %s\nOutput: %s", mynode, err, output)
-		return err
+		return txError("unstake", output, err)
 	}
 
 	log.Infof("✅ Unstake (undelegate) transaction sent successfully for '%s'! Transaction output:\n%s", mynode, output)
 	log.Info("Tokens will be liquid after the unbonding period (typically 21 days). Please monitor your balance.")
 
-	return nil
+	return renderTxOutput("unstake", output)
 }
 
 func withdrawRewardsCmd() *cobra.Command {
 	var mynode string
+	var commission bool
+	var txOpts TxOptions
 
 	cmd := &cobra.Command{
 		Use:   "withdraw-rewards",
 		Short: "Withdraw all accumulated staking rewards and validator commission",
-		Long: `Sends a transaction to withdraw all accumulated staking rewards from your self-delegation
-and any commission earned as a validator to your primary wallet address (which is also the
-delegator address in this context).`,
+		Long: `Sends a transaction to withdraw all accumulated staking rewards from your delegations
+to your primary wallet address (which is also the delegator address in this context).
+With --commission it withdraws the commission earned as a validator together with the
+rewards of the self-delegation instead.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return withdrawRewardsCmdLogic(mynode)
+			return withdrawRewardsCmdLogic(mynode, commission, txOpts)
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (key name of the validator)")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
+	cmd.Flags().BoolVar(&commission, "commission", false, "Withdraw the validator commission and the self-delegation rewards")
 
+	addTxFlags(cmd, &txOpts)
+	addGranteeFlag(cmd, &txOpts)
 	return cmd
 }
 
-func withdrawRewardsCmdLogic(mynode string) error {
-	configCliParams = getConfigCliParams()
+func withdrawRewardsCmdLogic(mynode string, commission bool, txOpts TxOptions) error {
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
 
 	err := godotenv.Load(filepath.Join(mynode, ".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load node-specific .env for '%s': %v", mynode, err)
+		return envFileError(filepath.Join(mynode, ".env"), err)
 	}
 
 	err = godotenv.Load(filepath.Join(".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load global .env: %v", err)
+		return envFileError(".env", err)
 	}
 
-	rpcPort := getEnvOrFail("RPC_PORT") // Get RPC port from loaded .env
+	rpcPort, err := requireEnv("RPC_PORT") // Get RPC port from loaded .env
+	if err != nil {
+		return err
+	}
 
 	log.Infof("Attempting to withdraw all rewards for validator '%s'", mynode)
 	log.Infof("Sending withdraw transaction to local node RPC: tcp://localhost:%s", rpcPort)
 
-	output, err := runCmdCaptureOutput(Mrmintd,
-		"tx", "distribution", "withdraw-all-rewards",
-		"--from", mynode,
+	txArgs := []string{"tx", "distribution", "withdraw-all-rewards"}
+	if commission {
+		var valoper string
+		if txOpts.AsGrantee != "" {
+			valoper, err = granterOperatorAddress(txOpts.Granter)
+		} else {
+			valoper, err = txOperatorAddress(mynode, txOpts)
+		}
+		if err != nil {
+			return err
+		}
+		txArgs = []string{"tx", "distribution", "withdraw-rewards", valoper, "--commission"}
+	}
+	txArgs = append(txArgs,
+		"--from", nodeKeyName(mynode),
 		"--home", mynode,
 		"--keyring-backend", "test",
 		"--chain-id", configCliParams.ChaindId,
@@ -1265,23 +1645,31 @@ func withdrawRewardsCmdLogic(mynode string) error {
 		"--gas-prices", "7mnt",
 		"--gas-adjustment", "1.3",
 		"--node", "tcp://localhost:"+rpcPort,
-		"--yes",
 	)
+	if txOpts.AsGrantee != "" {
+		return execAsGrantee(mynode, txOpts, "withdraw-rewards", "tcp://localhost:"+rpcPort, txArgs...)
+	}
+	if txOpts.GenerateOnly {
+		return generateUnsignedTx(mynode, txOpts, "withdraw-rewards", "tcp://localhost:"+rpcPort, Mrmintd, txArgs...)
+	}
+
+	output, err := runCmdCaptureOutput(Mrmintd, append(txOpts.apply(txArgs), "--yes")...)
 
 	if err != nil {
 		log.Errorf("❌ Failed to withdraw rewards for '%s': %s\nOutput: %s", mynode, err, output)
 		log.Warnf("Please ensure your node is running and synced, and you have accumulated rewards to withdraw.")
-		return err
+		return txError("withdraw rewards", output, err)
 	}
 
 	log.Infof("✅ Withdraw rewards transaction sent successfully for '%s'! Transaction output:\n%s", mynode, output)
 	log.Info("Please check your account balance to confirm the rewards have been received.")
 
-	return nil
+	return renderTxOutput("withdraw rewards", output)
 }
 
 func editCommissionCmd() *cobra.Command {
 	var mynode string
+	var txOpts TxOptions
 	var commissionRate string // Use string to pass directly to ethermintd
 
 	cmd := &cobra.Command{
@@ -1292,85 +1680,93 @@ Note: The new rate must adhere to the 'max-rate' and 'max-change-rate' defined
 during your validator's creation. Changes are typically limited to once per 24 hours.
 Example: --commission-rate "0.10" for 10% commission.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return editCommissionCmdLogic(mynode, commissionRate)
+			return editCommissionCmdLogic(mynode, commissionRate, txOpts)
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (key name of the validator)")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
 
 	cmd.Flags().StringVar(&commissionRate, "commission-rate", "", "New commission rate (e.g., \"0.10\" for 10%)")
 	cmd.MarkFlagRequired("commission-rate")
 
+	addTxFlags(cmd, &txOpts)
 	return cmd
 }
 
-func editCommissionCmdLogic(mynode string, commissionRate string) error {
-	configCliParams = getConfigCliParams() // Ensure config is loaded
+func editCommissionCmdLogic(mynode string, commissionRate string, txOpts TxOptions) error {
+	// Ensure config is loaded
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
 
 	// Load node-specific .env for RPC port
 	err := godotenv.Load(filepath.Join(mynode, ".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load node-specific .env for '%s': %v", mynode, err)
+		return envFileError(filepath.Join(mynode, ".env"), err)
 	}
 
 	// Load global .env for consistency and general configs (like chain-id if dynamic)
 	err = godotenv.Load(filepath.Join(".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load global .env: %v", err)
+		return envFileError(".env", err)
 	}
 
-	rpcPort := getEnvOrFail("RPC_PORT") // Get RPC port from loaded .env
+	rpcPort, err := requireEnv("RPC_PORT") // Get RPC port from loaded .env
+	if err != nil {
+		return err
+	}
 
 	// Input validation for commission rate
 	rateFloat, err := strconv.ParseFloat(commissionRate, 64)
 	if err != nil {
 		log.Errorf("❌ Invalid commission rate format: %s. Must be a decimal (e.g., 0.10).", commissionRate)
-		return fmt.Errorf("invalid commission rate")
+		return &ConfigError{Msg: fmt.Sprintf("invalid commission rate %q: must be a decimal, e.g. 0.10", commissionRate), Err: err}
 	}
 	if rateFloat < 0 || rateFloat > 1 {
 		log.Errorf("❌ Commission rate must be between 0 and 1 (e.g., 0.05 for 5%%, 0.10 for 10%%). Got: %s", commissionRate)
-		return fmt.Errorf("commission rate out of valid range")
+		return &ConfigError{Msg: fmt.Sprintf("commission rate %s out of range: must be between 0 and 1", commissionRate)}
 	}
 
 	// Get the delegator's address (ethm1...) -- This is the --from address for the transaction
-	// This command uses the node's home directory for keyring access.
-	getDelegatorAddrCmd := exec.Command(Mrmintd, "keys", "show", mynode, "-a", "--home", mynode, "--keyring-backend", "test")
-	delegatorAddrOut, err := getDelegatorAddrCmd.Output()
+	// A generated transaction can name it with --signer instead of the keyring.
+	delegatorAddress, err := txSignerAddress(mynode, txOpts)
 	if err != nil {
-		log.Errorf("Failed to get delegator address for '%s': %s\nOutput: %s", mynode, err, string(delegatorAddrOut))
 		return err
 	}
-	delegatorAddress := strings.TrimSpace(string(delegatorAddrOut))
 
 	log.Infof("Attempting to set commission rate for validator '%s' (%s) to '%s'", mynode, delegatorAddress, commissionRate)
 	log.Infof("Sending edit-validator transaction to local node RPC: tcp://localhost:%s", rpcPort)
 
 	// Construct and run the edit-validator command
 	// `ethermintd tx staking edit-validator --commission-rate [new-rate] --from [key-name] ...`
-	output, err := runCmdCaptureOutput(Mrmintd,
+	txArgs := []string{
 		"tx", "staking", "edit-validator",
 		"--commission-rate", commissionRate, // Pass the new rate
-		"--from", mynode, // Use the key name for --from flag
+		"--from", nodeKeyName(mynode), // Use the key name for --from flag
 		"--home", mynode, // Pass --home for keyring access
 		"--keyring-backend", "test",
 		"--chain-id", configCliParams.ChaindId,
 		"--gas", "auto",
 		"--gas-prices", "7mnt", // Explicitly setting gas prices
 		"--gas-adjustment", "1.1",
-		"--node", "tcp://localhost:"+rpcPort, // Target your local node's RPC
-		"--yes", // Automatically confirm transaction
-	)
+		"--node", "tcp://localhost:" + rpcPort, // Target your local node's RPC
+	}
+	if txOpts.GenerateOnly {
+		return generateUnsignedTx(mynode, txOpts, "edit-validator", "tcp://localhost:"+rpcPort, Mrmintd, txArgs...)
+	}
+
+	output, err := runCmdCaptureOutput(Mrmintd, append(txOpts.apply(txArgs), "--yes")...)
 
 	if err != nil {
 		log.Errorf("❌ Failed to edit validator commission for '%s': %s\nOutput: %s", mynode, err, output)
 		log.Warnf("Please ensure your validator is bonded and that the new commission rate adheres to 'max-rate' and 'max-change-rate' rules.")
-		return err
+		return txError("edit commission", output, err)
 	}
 
 	log.Infof("✅ Validator commission edit transaction sent successfully for '%s'! Transaction output:\n%s", mynode, output)
 	log.Info("Please monitor the chain and verify the new commission rate using 'mrmintchain validator-info --mynode %s'.", mynode)
 
-	return nil
+	return renderTxOutput("edit commission", output)
 }
 
 func queryProposalsCmd() *cobra.Command {
@@ -1386,14 +1782,20 @@ func queryProposalsCmd() *cobra.Command {
 }
 
 func queryProposalsCmdLogic() error {
-	configCliParams = getConfigCliParams() // Ensure config is loaded
+	// Ensure config is loaded
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
 
 	err := godotenv.Load(filepath.Join(".env"))
 	if err != nil {
 		log.Warnf("Could not load global .env file. Assuming default RPC port.")
 	}
 
-	rpcPort := getEnvOrFail("RPC_PORT")
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
 
 	log.Infof("Querying all governance proposals from RPC: tcp://localhost:%s", rpcPort)
 
@@ -1406,28 +1808,83 @@ func queryProposalsCmdLogic() error {
 	if cmdErr != nil {
 		if strings.Contains(output, "no proposals found") {
 			log.Warnf("ℹ️ No governance proposals found on the chain.")
-			return nil
+			return render(ProposalsResult{Proposals: []ProposalResult{}})
 		}
 		log.Errorf("❌ Failed to query proposals: %s\nOutput: %s", cmdErr, output)
 		log.Warnf("Please ensure your node is running and synced.")
 		return cmdErr
 	}
 
-	var prettyJSON bytes.Buffer
-	if err = json.Indent(&prettyJSON, []byte(output), "", "  "); err != nil {
-		log.Errorf("Failed to pretty-print JSON output: %v", err)
-		fmt.Println(output)
-		return nil
+	var resp struct {
+		Proposals []struct {
+			ID            string `json:"id"`
+			ProposalID    string `json:"proposal_id"`
+			Title         string `json:"title"`
+			Status        string `json:"status"`
+			SubmitTime    string `json:"submit_time"`
+			VotingEndTime string `json:"voting_end_time"`
+			Metadata      string `json:"metadata"`
+			Content       struct {
+				Title string `json:"title"`
+			} `json:"content"`
+		} `json:"proposals"`
+	}
+	data, err := extractJSON(output)
+	if err == nil {
+		err = json.Unmarshal(data, &resp)
+	}
+	if err != nil {
+		log.Errorf("Failed to parse proposals: %v", err)
+		return err
 	}
 
-	log.Infof("✅ Successfully retrieved governance proposals:")
-	fmt.Println(prettyJSON.String())
+	result := ProposalsResult{Proposals: []ProposalResult{}}
+	for _, p := range resp.Proposals {
+		proposal := ProposalResult{
+			ID:            p.ID,
+			Title:         p.Title,
+			Status:        p.Status,
+			SubmitTime:    p.SubmitTime,
+			VotingEndTime: p.VotingEndTime,
+		}
+		// v1beta1 proposals use proposal_id and keep the title in the content.
+		if proposal.ID == "" {
+			proposal.ID = p.ProposalID
+		}
+		if proposal.Title == "" {
+			proposal.Title = p.Content.Title
+		}
+		result.Proposals = append(result.Proposals, proposal)
+	}
 
-	return nil
+	log.Infof("✅ Successfully retrieved %d governance proposals", len(result.Proposals))
+	return render(result)
+}
+
+// ProposalResult is one governance proposal as reported by query-proposals.
+type ProposalResult struct {
+	ID            string `json:"id" yaml:"id"`
+	Title         string `json:"title" yaml:"title"`
+	Status        string `json:"status" yaml:"status"`
+	SubmitTime    string `json:"submit_time" yaml:"submit_time"`
+	VotingEndTime string `json:"voting_end_time" yaml:"voting_end_time"`
+}
+
+// ProposalsResult is the result of the query-proposals command.
+type ProposalsResult struct {
+	Proposals []ProposalResult `json:"proposals" yaml:"proposals"`
+}
+
+func (r ProposalsResult) renderTable(w io.Writer) {
+	fmt.Fprintln(w, "ID\tSTATUS\tVOTING END\tTITLE")
+	for _, p := range r.Proposals {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", p.ID, p.Status, p.VotingEndTime, p.Title)
+	}
 }
 
 func voteProposalCmd() *cobra.Command {
 	var mynode string
+	var txOpts TxOptions
 	var proposalID uint64
 	var voteOption string
 
@@ -1438,11 +1895,11 @@ func voteProposalCmd() *cobra.Command {
 Valid vote options are: "yes", "no", "abstain", "no_with_veto".
 Example: --proposal-id 1 --option "yes"`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return voteProposalCmdLogic(mynode, proposalID, voteOption)
+			return voteProposalCmdLogic(mynode, proposalID, voteOption, txOpts)
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (key name of the voter)")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
 
 	cmd.Flags().Uint64Var(&proposalID, "proposal-id", 0, "The ID of the proposal to vote on")
 	cmd.MarkFlagRequired("proposal-id")
@@ -1450,23 +1907,31 @@ Example: --proposal-id 1 --option "yes"`,
 	cmd.Flags().StringVar(&voteOption, "option", "", "Your vote option: yes, no, abstain, no_with_veto")
 	cmd.MarkFlagRequired("option")
 
+	addTxFlags(cmd, &txOpts)
+	addGranteeFlag(cmd, &txOpts)
 	return cmd
 }
 
-func voteProposalCmdLogic(mynode string, proposalID uint64, voteOption string) error {
-	configCliParams = getConfigCliParams() // Ensure config is loaded
+func voteProposalCmdLogic(mynode string, proposalID uint64, voteOption string, txOpts TxOptions) error {
+	// Ensure config is loaded
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
 
 	err := godotenv.Load(filepath.Join(mynode, ".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load node-specific .env for '%s': %v", mynode, err)
+		return envFileError(filepath.Join(mynode, ".env"), err)
 	}
 
 	err = godotenv.Load(filepath.Join(".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load global .env: %v", err)
+		return envFileError(".env", err)
 	}
 
-	rpcPort := getEnvOrFail("RPC_PORT")
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
 
 	validOptions := map[string]bool{
 		"yes":          true,
@@ -1476,41 +1941,49 @@ func voteProposalCmdLogic(mynode string, proposalID uint64, voteOption string) e
 	}
 	if !validOptions[strings.ToLower(voteOption)] {
 		log.Errorf("❌ Invalid vote option: %s. Must be one of: yes, no, abstain, no_with_veto.", voteOption)
-		return fmt.Errorf("invalid vote option")
+		return &ConfigError{Msg: fmt.Sprintf("invalid vote option %q: must be one of yes, no, abstain, no_with_veto", voteOption)}
 	}
 
 	log.Infof("Attempting to cast '%s' vote on proposal ID %d for voter '%s'", voteOption, proposalID, mynode)
 	log.Infof("Sending vote transaction to local node RPC: tcp://localhost:%s", rpcPort)
 
-	output, err := runCmdCaptureOutput(Mrmintd,
+	txArgs := []string{
 		"tx", "gov", "vote",
 		fmt.Sprintf("%d", proposalID), // Proposal ID
 		strings.ToLower(voteOption),   // Vote option
-		"--from", mynode,
+		"--from", nodeKeyName(mynode),
 		"--home", mynode,
 		"--keyring-backend", "test",
 		"--chain-id", configCliParams.ChaindId,
 		"--gas", "auto",
 		"--gas-prices", "7mnt",
 		"--gas-adjustment", "1.1",
-		"--node", "tcp://localhost:"+rpcPort, // Target your local node's RPC
-		"--yes",
-	)
+		"--node", "tcp://localhost:" + rpcPort, // Target your local node's RPC
+	}
+	if txOpts.AsGrantee != "" {
+		return execAsGrantee(mynode, txOpts, "vote", "tcp://localhost:"+rpcPort, txArgs...)
+	}
+	if txOpts.GenerateOnly {
+		return generateUnsignedTx(mynode, txOpts, "vote", "tcp://localhost:"+rpcPort, Mrmintd, txArgs...)
+	}
+
+	output, err := runCmdCaptureOutput(Mrmintd, append(txOpts.apply(txArgs), "--yes")...)
 
 	if err != nil {
 		log.Errorf("❌ Failed to cast vote on proposal %d for '%s': %s\nOutput: %s", proposalID, mynode, err, output)
 		log.Warnf("Please ensure your node is running and synced, the proposal is in the 'voting_period', and your key has funds for fees.")
-		return err
+		return txError("vote", output, err)
 	}
 
 	log.Infof("✅ Vote transaction sent successfully for proposal %d! Transaction output:\n%s", proposalID, output)
 	log.Info("You can verify your vote using 'ethermintd query gov vote %d %s --node tcp://localhost:%s'.", proposalID, mynode, rpcPort)
 
-	return nil
+	return renderTxOutput("vote", output)
 }
 
 func submitParamChangeProposalCmd() *cobra.Command {
 	var mynode string
+	var txOpts TxOptions
 	var title string
 	var description string
 	var deposit string // This will be a separate flag for ethermintd tx
@@ -1527,16 +2000,16 @@ It's used for parameters related to 'mint', 'rewards' (distribution), 'gov', 'st
 The proposal requires an initial deposit to enter the voting period.
 Example: --module "mint" --param-key "MintDenom" --param-value "\"mnt\""`, // Escaped quotes for JSON string
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return submitParamChangeProposalCmdLogic(mynode, title, description, deposit, module, paramKey, paramValue)
+			return submitParamChangeProposalCmdLogic(mynode, title, description, deposit, module, paramKey, paramValue, txOpts)
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Name of the key (from your validator) to submit the proposal")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
 	cmd.Flags().StringVar(&title, "title", "", "Title of the proposal")
 	cmd.MarkFlagRequired("title")
 	cmd.Flags().StringVar(&description, "description", "", "Description of the proposal")
 	cmd.MarkFlagRequired("description")
-	cmd.Flags().StringVar(&deposit, "deposit", "", "Initial deposit amount (e.g., 1000000000000000000mnt)")
+	cmd.Flags().StringVar(&deposit, "deposit", "", "Initial deposit amount ("+amountHelp+")")
 	cmd.MarkFlagRequired("deposit")
 	cmd.Flags().StringVar(&module, "module", "", "Name of the module to change parameter in (e.g., mint, distribution, gov, staking)")
 	cmd.MarkFlagRequired("module")
@@ -1545,24 +2018,34 @@ Example: --module "mint" --param-key "MintDenom" --param-value "\"mnt\""`, // Es
 	cmd.Flags().StringVar(&paramValue, "param-value", "", "New value for the parameter (must be correctly formatted JSON string if complex, e.g., '\"mnt\"' for a string value, or '\"1000\"' for a number, or '{\"key\":\"value\"}' for an object)")
 	cmd.MarkFlagRequired("param-value")
 
+	addTxFlags(cmd, &txOpts)
 	return cmd
 }
 
-func submitParamChangeProposalCmdLogic(
-	mynode, title, description, deposit, module, paramKey, paramValue string,
+func submitParamChangeProposalCmdLogic(mynode, title, description, deposit, module, paramKey, paramValue string, txOpts TxOptions,
 ) error {
-	configCliParams = getConfigCliParams()
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
 
 	err := godotenv.Load(filepath.Join(mynode, ".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load node-specific .env for '%s': %v", mynode, err)
+		return envFileError(filepath.Join(mynode, ".env"), err)
 	}
 	err = godotenv.Load(filepath.Join(".env"))
 	if err != nil {
-		log.Fatalf("❌ Failed to load global .env: %v", err)
+		return envFileError(".env", err)
+	}
+
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
 	}
 
-	rpcPort := getEnvOrFail("RPC_PORT")
+	deposit, err = parseAmountFlag("deposit", deposit, "tcp://localhost:"+rpcPort)
+	if err != nil {
+		return err
+	}
 
 	parsedParamValue := json.RawMessage(fmt.Sprintf("%q", paramValue))
 
@@ -1581,7 +2064,7 @@ func submitParamChangeProposalCmdLogic(
 
 	paramChangeContentBytes, err := json.Marshal(paramChangeContent)
 	if err != nil {
-		log.Fatalf("❌ Failed to marshal param change content: %v", err)
+		return fmt.Errorf("failed to marshal param change content: %w", err)
 	}
 
 	govAuthorityAddress := "ethm10d07y265gmmuvt4z0w9aw880jnsr700jpva843" // **IMPORTANT: Make this dynamic if it changes!**
@@ -1594,7 +2077,7 @@ func submitParamChangeProposalCmdLogic(
 
 	legacyContentWrapperBytes, err := json.Marshal(legacyContentWrapper)
 	if err != nil {
-		log.Fatalf("❌ Failed to marshal legacy content wrapper: %v", err)
+		return fmt.Errorf("failed to marshal legacy content wrapper: %w", err)
 	}
 
 	proposalFile := ProposalFile{
@@ -1604,52 +2087,50 @@ func submitParamChangeProposalCmdLogic(
 
 	proposalJSON, err := json.MarshalIndent(proposalFile, "", "  ")
 	if err != nil {
-		log.Fatalf("❌ Failed to marshal full proposal file to JSON: %v", err)
+		return fmt.Errorf("failed to marshal full proposal file to JSON: %w", err)
 	}
 
-	tmpFile, err := os.CreateTemp(os.TempDir(), "param-change-proposal-*.json")
+	proposalPath, err := transport.WriteTemp("param-change-proposal-*.json", proposalJSON)
 	if err != nil {
-		log.Fatalf("❌ Failed to create temporary file for proposal: %v", err)
-	}
-
-	if _, err := tmpFile.Write(proposalJSON); err != nil {
-		log.Fatalf("❌ Failed to write proposal JSON to temporary file: %v", err)
-	}
-	if err := tmpFile.Close(); err != nil {
-		log.Fatalf("❌ Failed to close temporary file: %v", err)
+		return &RuntimeError{Msg: "failed to write the proposal to a temporary file", Err: err}
 	}
+	defer transport.Remove(proposalPath)
 
 	log.Infof("Submitting parameter change proposal for '%s' module, key '%s' to value '%s'", module, paramKey, paramValue)
 	log.Infof("Proposal Title: '%s', Description: '%s', Deposit: '%s'", title, description, deposit)
-	log.Infof("Using temporary proposal file: %s", tmpFile.Name())
+	log.Infof("Using temporary proposal file: %s", proposalPath)
 	log.Infof("Sending proposal transaction to local node RPC: tcp://localhost:%s", rpcPort)
 	log.Debugf("Generated Proposal JSON:\n%s", string(proposalJSON))
 
-	output, cmdErr := runCmdCaptureOutput(Mrmintd,
+	txArgs := []string{
 		"tx", "gov", "submit-proposal",
-		tmpFile.Name(),
-		"--from", mynode,
+		proposalPath,
+		"--from", nodeKeyName(mynode),
 		"--home", mynode,
 		"--keyring-backend", "test",
 		"--chain-id", configCliParams.ChaindId,
 		"--gas", "auto",
 		"--gas-prices", "7mnt",
 		"--gas-adjustment", "1.1",
-		"--node", "tcp://localhost:"+rpcPort,
-		"--yes",
-	)
+		"--node", "tcp://localhost:" + rpcPort,
+	}
+	if txOpts.GenerateOnly {
+		return generateUnsignedTx(mynode, txOpts, "submit-proposal", "tcp://localhost:"+rpcPort, Mrmintd, txArgs...)
+	}
+
+	output, cmdErr := runCmdCaptureOutput(Mrmintd, append(txOpts.apply(txArgs), "--yes")...)
 
 	if cmdErr != nil {
 		log.Errorf("❌ Failed to submit parameter change proposal: %s\nOutput: %s", cmdErr, output)
 		log.Warnf("Please ensure your node is running and synced, your key has sufficient funds for the deposit, and the parameter values are correctly formatted within the JSON structure.")
-		return cmdErr
+		return txError("submit proposal", output, cmdErr)
 	}
 
 	log.Infof("✅ Parameter change proposal submitted successfully! Transaction output:\n%s", output)
 	log.Info("The proposal will enter the 'deposit_period'. If sufficient deposit is reached, it will move to 'voting_period'.")
 	log.Info("You can track its status using 'mrmintchain query-proposals'.")
 
-	return nil
+	return renderTxOutput("submit proposal", output)
 }
 
 func queryTxCmd() *cobra.Command {
@@ -1669,7 +2150,7 @@ to load RPC connection details from the node's .env file.`,
 	}
 
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (directory where .env is located)")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
 	return cmd
 }
 
@@ -1677,10 +2158,13 @@ func queryTxCmdLogic(mynode, txHash string) error {
 	envPath := filepath.Join(mynode, ".env")
 	err := godotenv.Load(envPath)
 	if err != nil {
-		log.Fatalf("❌ Failed to load .env file from '%s' for node '%s': %v", envPath, mynode, err)
+		return envFileError(envPath, err)
 	}
 
-	rpcPort := getEnvOrFail("RPC_PORT")
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
 	rpcLaddr := "tcp://localhost:" + rpcPort
 
 	log.Infof("🔍 Attempting to query transaction %s using RPC endpoint: %s", txHash, rpcLaddr)
@@ -1692,9 +2176,13 @@ func queryTxCmdLogic(mynode, txHash string) error {
 		return err
 	}
 
-	fmt.Println(output)
+	result, err := parseTxResult(output)
+	if err != nil {
+		log.Errorf("Failed to parse transaction %s: %v", txHash, err)
+		return err
+	}
 	log.Info("✅ Transaction query complete.")
-	return nil
+	return render(result)
 }
 
 // func loginCmd() *cobra.Command {
@@ -1783,93 +2271,103 @@ You will be securely prompted for your password. If authentication is successful
 it retrieves validator addresses and proceeds to update the 
 validator details using the provided API endpoint.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			var password, token string
-			reader := bufio.NewReader(os.Stdin)
+			_, err := registerValidatorLogic(mynode)
+			return err
+		},
+	}
 
-			// Prompt for Email
-			fmt.Print("Enter registered email address: ")
-			email, err := reader.ReadString('\n')
-			if err != nil {
-				return fmt.Errorf("failed to read email: %w", err)
-			}
-			email = strings.TrimSpace(email)
-			if email == "" {
-				return fmt.Errorf("email cannot be empty")
-			}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Your node name (to derive validator addresses)")
+	requireNode(cmd)
 
-			// Prompt for Password (hidden)
-			fmt.Print("Enter registered password: ")
-			bytePassword, err := term.ReadPassword(int(os.Stdin.Fd()))
-			if err != nil {
-				return fmt.Errorf("failed to read password: %w", err)
-			}
-			password = string(bytePassword)
-			fmt.Println() // Add a newline for better formatting after password input.
-			if password == "" {
-				return fmt.Errorf("password cannot be empty")
-			}
+	return cmd
+}
 
-			// Prompt for 2FA Token (hidden)
-			fmt.Print("Enter 2FA token: ")
-			byteToken, err := term.ReadPassword(int(os.Stdin.Fd()))
-			if err != nil {
-				return fmt.Errorf("failed to read 2FA token: %w", err)
-			}
-			token = string(byteToken)
-			fmt.Println() // Add a newline
+// registerValidatorLogic authenticates with the platform and registers the
+// node's validator addresses. It returns the registered email.
+func registerValidatorLogic(mynode string) (string, error) {
+	var password, token string
+	reader := bufio.NewReader(os.Stdin)
 
-			// Step 1: Authenticate with the platform API.
-			log.Info("🔐 Authenticating with the platform...")
-			authToken, err := authenticateAndGetToken(email, password, token)
-			if err != nil {
-				return fmt.Errorf("platform authentication failed: User not found")
-			}
-			log.Info("✅ Credentials verified successfully...")
+	// Prompt for Email
+	fmt.Fprint(os.Stderr, "Enter registered email address: ")
+	email, err := reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read email: %w", err)
+	}
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return "", fmt.Errorf("email cannot be empty")
+	}
 
-			// Step 2: Get validator addresses from the node key
-			log.Info("🔍 Retrieving validator addresses....")
+	// Prompt for Password (hidden)
+	fmt.Fprint(os.Stderr, "Enter registered password: ")
+	bytePassword, err := term.ReadPassword(int(os.Stdin.Fd()))
+	if err != nil {
+		return "", fmt.Errorf("failed to read password: %w", err)
+	}
+	password = string(bytePassword)
+	fmt.Fprintln(os.Stderr) // Add a newline for better formatting after password input.
+	if password == "" {
+		return "", fmt.Errorf("password cannot be empty")
+	}
 
-			// Get validator wallet address (ethm1...)
-			getWalletAddrCmd := exec.Command(Mrmintd, "keys", "show", mynode, "-a", "--home", mynode, "--keyring-backend", "test")
-			walletAddrOut, err := getWalletAddrCmd.Output()
-			if err != nil {
-				return fmt.Errorf("failed to get validator wallet address for '%s': %w. Output: %s", mynode, err, string(walletAddrOut))
-			}
-			validatorWalletAddress := strings.TrimSpace(string(walletAddrOut))
+	// Prompt for 2FA Token (hidden)
+	fmt.Fprint(os.Stderr, "Enter 2FA token: ")
+	byteToken, err := term.ReadPassword(int(os.Stdin.Fd()))
+	if err != nil {
+		return "", fmt.Errorf("failed to read 2FA token: %w", err)
+	}
+	token = string(byteToken)
+	fmt.Fprintln(os.Stderr) // Add a newline
 
-			// Get validator operator address (ethmvaloper...)
-			getOperatorAddrCmd := exec.Command(Mrmintd, "keys", "show", mynode, "--bech", "val", "--home", mynode, "--keyring-backend", "test")
-			operatorAddrOut, err := getOperatorAddrCmd.Output()
-			if err != nil {
-				return fmt.Errorf("failed to get validator operator address for '%s': %w. Output: %s", mynode, err, string(operatorAddrOut))
-			}
-			var keyInfo []struct {
-				Address string `yaml:"address"`
-			}
-			if err := yaml.Unmarshal(operatorAddrOut, &keyInfo); err != nil {
-				return fmt.Errorf("failed to parse validator operator address output: %w. Output: %s", err, string(operatorAddrOut))
-			}
-			if len(keyInfo) == 0 || keyInfo[0].Address == "" {
-				return fmt.Errorf("could not find validator operator address in output: %s", string(operatorAddrOut))
-			}
-			validatorOperatorAddress := keyInfo[0].Address
+	// Step 1: Authenticate with the platform API.
+	log.Info("🔐 Authenticating with the platform...")
+	authToken, err := authenticateAndGetToken(email, password, token)
+	if err != nil {
+		return "", fmt.Errorf("platform authentication failed: User not found")
+	}
+	log.Info("✅ Credentials verified successfully...")
 
-			// Convert wallet address to ETH format (0x...)
-			validatorEthAddress, err := Bech32ToEthAddress(validatorWalletAddress)
-			if err != nil {
-				return fmt.Errorf("failed to convert bech32 address to eth address: %w", err)
-			}
+	// Step 2: Get validator addresses from the node key
+	log.Info("🔍 Retrieving validator addresses....")
 
-			// Step 3: Update validator details via API.
-			log.Info("🔄 Updating validator details in the platform...")
-			return updateValidatorInfoAPI(authToken, email, validatorOperatorAddress, validatorWalletAddress, validatorEthAddress, mynode)
-		},
+	// Get validator wallet address (ethm1...)
+	getWalletAddrCmd := nodeCommand(Mrmintd, "keys", "show", nodeKeyName(mynode), "-a", "--home", mynode, "--keyring-backend", "test")
+	walletAddrOut, err := getWalletAddrCmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get validator wallet address for '%s': %w. Output: %s", mynode, err, string(walletAddrOut))
 	}
+	validatorWalletAddress := strings.TrimSpace(string(walletAddrOut))
 
-	cmd.Flags().StringVar(&mynode, "mynode", "", "Your node name (to derive validator addresses)")
-	cmd.MarkFlagRequired("mynode")
+	// Get validator operator address (ethmvaloper...)
+	getOperatorAddrCmd := nodeCommand(Mrmintd, "keys", "show", nodeKeyName(mynode), "--bech", "val", "--home", mynode, "--keyring-backend", "test")
+	operatorAddrOut, err := getOperatorAddrCmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to get validator operator address for '%s': %w. Output: %s", mynode, err, string(operatorAddrOut))
+	}
+	var keyInfo []struct {
+		Address string `yaml:"address"`
+	}
+	if err := yaml.Unmarshal(operatorAddrOut, &keyInfo); err != nil {
+		return "", fmt.Errorf("failed to parse validator operator address output: %w. Output: %s", err, string(operatorAddrOut))
+	}
+	if len(keyInfo) == 0 || keyInfo[0].Address == "" {
+		return "", fmt.Errorf("could not find validator operator address in output: %s", string(operatorAddrOut))
+	}
+	validatorOperatorAddress := keyInfo[0].Address
 
-	return cmd
+	// Convert wallet address to ETH format (0x...)
+	validatorEthAddress, err := Bech32ToEthAddress(validatorWalletAddress)
+	if err != nil {
+		return "", fmt.Errorf("failed to convert bech32 address to eth address: %w", err)
+	}
+
+	// Step 3: Update validator details via API.
+	log.Info("🔄 Updating validator details in the platform...")
+	if err := updateValidatorInfoAPI(authToken, email, validatorOperatorAddress, validatorWalletAddress, validatorEthAddress, mynode); err != nil {
+		return "", err
+	}
+	return email, nil
 }
 
 // authenticateAndGetToken handles the authentication API call and returns the token.
@@ -1940,12 +2438,12 @@ func updateValidatorInfoAPI(authToken, email, operatorAddr, walletAddr, ethAddr,
 	// IMPORTANT: This URL should be made configurable, e.g., from the remote config file.
 	apiURL := "http://15.207.226.255:8961/api/validator/updateValidatorInfo"
 
-	// Create the request body
+	// Create the request body. The platform stores addresses lower case.
 	requestBody, err := json.Marshal(map[string]string{
 		"email":                    email,
 		"validatorOperatorAddress": operatorAddr,
 		"validatorWalletAddress":   walletAddr,
-		"validatorEthAddress":      ethAddr,
+		"validatorEthAddress":      strings.ToLower(ethAddr),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to create update-validator-info request payload: %w", err)
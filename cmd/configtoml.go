@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// setTomlValues sets keys of one section of a TOML file such as the node's
+// config.toml, editing the file line by line so that comments, ordering and
+// the rest of the file stay untouched. Values are TOML literals; use
+// tomlString for strings. Keys missing from the section are appended to it,
+// and a missing section is appended to the file. An empty section name is
+// the top-level table before the first header.
+func setTomlValues(path, section string, values map[string]string, order ...string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return &ConfigError{Msg: "failed to read " + path, Err: err, Hint: "run 'init-node' to create the node configuration"}
+	}
+	lines := strings.Split(string(data), "\n")
+
+	start, end := tomlSectionBounds(lines, section)
+	if start < 0 && section != "" {
+		lines = append(trimTrailingBlank(lines), "", "["+section+"]")
+		start, end = len(lines)-1, len(lines)
+	}
+
+	set := map[string]bool{}
+	for i := start + 1; i < end; i++ {
+		key, ok := tomlKey(lines[i])
+		if !ok {
+			continue
+		}
+		if value, found := values[key]; found {
+			lines[i] = key + " = " + value
+			set[key] = true
+		}
+	}
+
+	// Append the missing keys after the last non-blank line of the section.
+	insertAt := end
+	for insertAt > start+1 && strings.TrimSpace(lines[insertAt-1]) == "" {
+		insertAt--
+	}
+	var missing []string
+	for _, key := range tomlKeyOrder(values, order) {
+		if _, ok := values[key]; ok && !set[key] {
+			missing = append(missing, key+" = "+values[key])
+		}
+	}
+	if len(missing) > 0 {
+		lines = append(lines[:insertAt], append(missing, lines[insertAt:]...)...)
+	}
+
+	out := strings.Join(lines, "\n")
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	if err := os.WriteFile(path, []byte(out), 0644); err != nil {
+		return &RuntimeError{Msg: "failed to write " + path, Err: err}
+	}
+	return nil
+}
+
+// tomlStringValue reads a string key of one section of a TOML file. It only
+// understands the single-line basic strings setTomlValues and ethermintd
+// write; found is false when the key is missing.
+func tomlStringValue(path, section, key string) (value string, found bool, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false, &ConfigError{Msg: "failed to read " + path, Err: err, Hint: "run 'init-node' to create the node configuration"}
+	}
+	lines := strings.Split(string(data), "\n")
+	start, end := tomlSectionBounds(lines, section)
+	if section != "" && start < 0 {
+		return "", false, nil
+	}
+	for i := start + 1; i < end; i++ {
+		if k, ok := tomlKey(lines[i]); ok && k == key {
+			_, raw, _ := strings.Cut(lines[i], "=")
+			value, err := strconv.Unquote(strings.TrimSpace(raw))
+			if err != nil {
+				return "", true, &ConfigError{Msg: fmt.Sprintf("%s in [%s] of %s is not a string", key, section, path), Err: err}
+			}
+			return value, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// tomlSectionBounds returns the index of the section header and of the line
+// after the section's last line. The top-level table has start -1.
+func tomlSectionBounds(lines []string, section string) (int, int) {
+	start := -1
+	if section != "" {
+		for i, line := range lines {
+			if strings.TrimSpace(line) == "["+section+"]" {
+				start = i
+				break
+			}
+		}
+		if start < 0 {
+			return -1, -1
+		}
+	}
+	for i := start + 1; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "[") {
+			return start, i
+		}
+	}
+	return start, len(lines)
+}
+
+func tomlKey(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", false
+	}
+	key, _, ok := strings.Cut(line, "=")
+	return strings.TrimSpace(key), ok
+}
+
+func tomlKeyOrder(values map[string]string, order []string) []string {
+	keys := append([]string{}, order...)
+	seen := map[string]bool{}
+	for _, key := range keys {
+		seen[key] = true
+	}
+	var rest []string
+	for key := range values {
+		if !seen[key] {
+			rest = append(rest, key)
+		}
+	}
+	sort.Strings(rest)
+	return append(keys, rest...)
+}
+
+func trimTrailingBlank(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// tomlString quotes s as a TOML basic string. Only the quote, the backslash
+// and control characters are escaped, with the escapes the TOML spec allows;
+// other characters are written as they are.
+func tomlString(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&b, `\u%04X`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// tomlBool formats b as a TOML boolean.
+func tomlBool(b bool) string {
+	return fmt.Sprintf("%t", b)
+}
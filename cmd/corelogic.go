@@ -3,6 +3,7 @@ package main
 import (
 	"bufio"
 	"fmt"
+	"math/big"
 	"net"
 	"os"
 	"strconv"
@@ -11,100 +12,79 @@ import (
 	"github.com/charmbracelet/log"
 )
 
-func getConfirmationForPayment(s string, ethm1Address string) bool {
-
+// getConfirmationForPayment waits until the wallet holds at least required
+// base units. Closing stdin cancels the wait.
+func getConfirmationForPayment(s string, ethm1Address string, required *big.Int) error {
 	reader := bufio.NewReader(os.Stdin)
-
-	fmt.Printf("%s (yes/no): ", s)
-	input, _ := reader.ReadString('\n')
-	input = strings.TrimSpace(input)
-	input = strings.ToLower(input)
-
-	if input == "yes" || input == "y" {
-		_, exactBalance := getBalanceCmdLogic(ethm1Address)
-		if exactBalance < configCliParams.MinStakeFund {
-			log.Errorf("😧 The balances is less then mininmum deposit amount %d mnt, Please deposit more", configCliParams.MinStakeFund)
-
+	for {
+		fmt.Fprintf(os.Stderr, "%s (yes/no): ", s)
+		input, err := reader.ReadString('\n')
+		if err != nil && input == "" {
+			return &UserAbort{Msg: "deposit confirmation cancelled"}
+		}
+		input = strings.TrimSpace(input)
+		input = strings.ToLower(input)
+
+		switch input {
+		case "yes", "y":
+			_, exactBalance := getBalanceCmdLogic(ethm1Address)
+			if exactBalance.Cmp(required) >= 0 {
+				log.Info("✅ Your fund deposited!")
+				return nil
+			}
+			log.Errorf("😧 The balances is less then mininmum deposit amount %s, Please deposit more", denomMetadata(configCliParams.BootNodeRpc).Format(required))
 			log.Error("❌ Balance not deposited yet, Please try again.")
-			getConfirmationForPayment(s, ethm1Address)
-		} else {
-			log.Info("✅ Your fund deposited!")
-			return true
+		case "no", "n":
+			fmt.Fprintln(os.Stderr, "Please deposit mnt first then you can proceed")
+		default:
+			log.Info("Invalid input. Please enter 'yes' or 'no'.")
 		}
-		// Perform actions for "yes"
-	} else if input == "no" || input == "n" {
-		fmt.Println("Please deposit mnt first then you can proceed")
-		getConfirmationForPayment(s, ethm1Address)
-		// Perform actions for "no" or exit
-	} else {
-		log.Info("Invalid input. Please enter 'yes' or 'no'.")
-		// Handle invalid input, possibly re-prompting the user
-		getConfirmationForPayment(s, ethm1Address)
 	}
-
-	return true
-}
-
-// Define a struct for items within the 'balances' array
-type BalanceItem struct {
-	Amount string `yaml:"amount"` // Ensure the field name matches your struct (e.g., Amount)
-	Denom  string `yaml:"denom"`
 }
 
-// Updated struct for the overall YAML output
-type CmdOutput struct {
-	Balances   []BalanceItem `yaml:"balances"`
-	Pagination struct {
-		NextKey interface{} `yaml:"next_key"`
-		Total   string      `yaml:"total"`
-	} `yaml:"pagination"`
-}
-
-func getEnvOrFail(key string) string {
+// requireEnv returns the value of a variable of the loaded .env files.
+func requireEnv(key string) (string, error) {
 	value := os.Getenv(key)
 	if value == "" {
-		log.Fatalf("❌ Missing required environment variable: %s", key)
+		return "", &ConfigError{
+			Msg:  "missing required environment variable " + key,
+			Hint: "run 'port-set --mynode <node>' to regenerate the node .env",
+		}
 	}
-	return value
+	return value, nil
 }
 
-func getPortInputAndCheck(prompt string, defaultPort string, existing []string) string {
+func getPortInputAndCheck(prompt string, defaultPort string, picked map[int]bool, reserved map[int]string, own map[int]bool) (int, error) {
 	reader := bufio.NewReader(os.Stdin)
 
 	for {
-		fmt.Printf("%s [default (%s)]: ", prompt, defaultPort)
-		input, _ := reader.ReadString('\n')
+		fmt.Fprintf(os.Stderr, "%s [default (%s)]: ", prompt, defaultPort)
+		input, err := reader.ReadString('\n')
+		if err != nil && input == "" {
+			return 0, &UserAbort{Msg: "port prompt cancelled"}
+		}
 		input = strings.TrimSpace(input)
 
 		if input == "" {
 			input = defaultPort
 		}
 
-		// Check numeric
-		if _, err := strconv.Atoi(input); err != nil {
-			log.Error("❌ Invalid input. Please enter numeric port.")
+		port, err := parsePort(input)
+		if err != nil {
+			log.Errorf("❌ Invalid port: %v", err)
 			continue
 		}
-
-		// Check port length (4 or 5 digits)
-		if len(input) != 4 && len(input) != 5 {
-			log.Error("❌ Port must be 4 or 5 digits.")
-			continue
+		if port < 1024 {
+			log.Warnf("⚠️ Port %d is privileged; publishing it needs root.", port)
 		}
 
-		// Check for duplicates
-		if checkArrayAlreadyExists(existing, input) {
-			log.Errorf("❌ Port %s already used.\n", input)
+		// Check duplicates, sibling nodes and availability
+		if problem := portProblem(port, reserved, own, picked); problem != "" {
+			log.Errorf("❌ %s", problem)
 			continue
 		}
 
-		// Check availability
-		if err := checkPort(input); err != nil {
-			log.Errorf("❌ Port %s not available: %s\n", input, err)
-			continue
-		}
-
-		return input
+		return port, nil
 	}
 }
 
@@ -117,20 +97,11 @@ func checkPort(port string) error {
 	return nil
 }
 
-func checkArrayAlreadyExists(slice []string, value string) bool {
-	for _, item := range slice {
-		if item == value {
-			return true
-		}
-	}
-	return false
-}
-
 func getStakingInputs(prompt string, defaultValue string) string {
 	reader := bufio.NewReader(os.Stdin)
 
 	for {
-		fmt.Printf("%s [default (%s)]: ", prompt, defaultValue)
+		fmt.Fprintf(os.Stderr, "%s [default (%s)]: ", prompt, defaultValue)
 		input, _ := reader.ReadString('\n')
 		input = strings.TrimSpace(input)
 
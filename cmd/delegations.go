@@ -0,0 +1,492 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+type DelegationsResponse struct {
+	DelegationResponses []struct {
+		Delegation struct {
+			DelegatorAddress string `json:"delegator_address"`
+			ValidatorAddress string `json:"validator_address"`
+			Shares           string `json:"shares"`
+		} `json:"delegation"`
+		Balance struct {
+			Denom  string `json:"denom"`
+			Amount string `json:"amount"`
+		} `json:"balance"`
+	} `json:"delegation_responses"`
+}
+
+type UnbondingResponse struct {
+	UnbondingResponses []struct {
+		DelegatorAddress string `json:"delegator_address"`
+		ValidatorAddress string `json:"validator_address"`
+		Entries          []struct {
+			CreationHeight string `json:"creation_height"`
+			CompletionTime string `json:"completion_time"`
+			InitialBalance string `json:"initial_balance"`
+			Balance        string `json:"balance"`
+		} `json:"entries"`
+	} `json:"unbonding_responses"`
+}
+
+// queryDelegations fetches every page of the delegations of delegator.
+func queryDelegations(delegator, node string) (DelegationsResponse, error) {
+	var all DelegationsResponse
+	err := queryAllPages("delegations", func(data json.RawMessage) (int, error) {
+		var page DelegationsResponse
+		if err := json.Unmarshal(data, &page); err != nil {
+			return 0, err
+		}
+		all.DelegationResponses = append(all.DelegationResponses, page.DelegationResponses...)
+		return len(page.DelegationResponses), nil
+	}, "query", "staking", "delegations", delegator, "--node", node, "--output", "json")
+	return all, err
+}
+
+// queryUnbondings fetches every unbonding delegation of delegator.
+func queryUnbondings(delegator, node string) (UnbondingResponse, error) {
+	var all UnbondingResponse
+	err := queryAllPages("unbonding delegations", func(data json.RawMessage) (int, error) {
+		var page UnbondingResponse
+		if err := json.Unmarshal(data, &page); err != nil {
+			return 0, err
+		}
+		all.UnbondingResponses = append(all.UnbondingResponses, page.UnbondingResponses...)
+		return len(page.UnbondingResponses), nil
+	}, "query", "staking", "unbonding-delegations", delegator, "--node", node, "--output", "json")
+	return all, err
+}
+
+// queryPageLimit is the page size of queryAllPages.
+const queryPageLimit = 100
+
+// queryAllPages runs a paginated query, handing the JSON of each page to add,
+// which returns the number of entries on it. Pages are requested by offset:
+// the CLI takes --page-key as raw bytes, not in the base64 form of
+// pagination.next_key.
+func queryAllPages(what string, add func(data json.RawMessage) (int, error), args ...string) error {
+	offset := 0
+	for {
+		pageArgs := append(append([]string{}, args...), "--limit", strconv.Itoa(queryPageLimit), "--offset", strconv.Itoa(offset))
+		output, err := runCmdCaptureOutput(Mrmintd, pageArgs...)
+		if err != nil {
+			return &ChainQueryError{Query: "query " + what, Err: fmt.Errorf("%w: %s", err, strings.TrimSpace(output))}
+		}
+
+		data, err := extractJSON(output)
+		if err != nil {
+			return &ChainQueryError{Query: "parse " + what, Err: err}
+		}
+		var page struct {
+			Pagination struct {
+				NextKey string `json:"next_key"`
+			} `json:"pagination"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return &ChainQueryError{Query: "parse " + what, Err: err}
+		}
+		n, err := add(data)
+		if err != nil {
+			return &ChainQueryError{Query: "parse " + what, Err: err}
+		}
+
+		if page.Pagination.NextKey == "" || n == 0 {
+			return nil
+		}
+		offset += n
+	}
+}
+
+func delegationsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "delegations",
+		Short: "Inspect the delegations of a wallet",
+	}
+	cmd.AddCommand(delegationsListCmd())
+	return cmd
+}
+
+func delegationsListCmd() *cobra.Command {
+	var mynode string
+	var delegator string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List all delegations of the node wallet (or --delegator)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return delegationsListCmdLogic(mynode, delegator)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&delegator, "delegator", "", "Delegator address to inspect (default: the node wallet)")
+	return cmd
+}
+
+func delegationsListCmdLogic(mynode, delegator string) error {
+	err := godotenv.Load(filepath.Join(mynode, ".env"))
+	if err != nil {
+		return envFileError(filepath.Join(mynode, ".env"), err)
+	}
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
+
+	if delegator == "" {
+		if delegator, err = keyAddress(mynode); err != nil {
+			return err
+		}
+	}
+
+	resp, err := queryDelegations(delegator, "tcp://localhost:"+rpcPort)
+	if err != nil {
+		log.Errorf("❌ Failed to query delegations for %s: %v", delegator, err)
+		return err
+	}
+
+	if len(resp.DelegationResponses) == 0 {
+		log.Infof("ℹ️ No delegations found for %s", delegator)
+	}
+
+	md := denomMetadata("tcp://localhost:" + rpcPort)
+	result := DelegationsResult{Delegator: delegator, Delegations: []AccountDelegation{}}
+	for _, d := range resp.DelegationResponses {
+		result.Delegations = append(result.Delegations, AccountDelegation{
+			Validator: d.Delegation.ValidatorAddress,
+			Amount:    d.Balance.Amount + d.Balance.Denom,
+			Display:   md.FormatString(d.Balance.Amount),
+		})
+	}
+	return render(result)
+}
+
+// DelegationsResult is the result of the delegations list command.
+type DelegationsResult struct {
+	Delegator   string              `json:"delegator" yaml:"delegator"`
+	Delegations []AccountDelegation `json:"delegations" yaml:"delegations"`
+}
+
+func (r DelegationsResult) renderTable(w io.Writer) {
+	fmt.Fprintln(w, "VALIDATOR\tAMOUNT")
+	for _, d := range r.Delegations {
+		fmt.Fprintf(w, "%s\t%s\n", d.Validator, d.Display)
+	}
+}
+
+func unbondingCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unbonding",
+		Short: "Inspect unbonding delegations of a wallet",
+	}
+	cmd.AddCommand(unbondingListCmd())
+	return cmd
+}
+
+func unbondingListCmd() *cobra.Command {
+	var mynode string
+	var delegator string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List unbonding entries of the node wallet (or --delegator)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return unbondingListCmdLogic(mynode, delegator)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&delegator, "delegator", "", "Delegator address to inspect (default: the node wallet)")
+	return cmd
+}
+
+func unbondingListCmdLogic(mynode, delegator string) error {
+	err := godotenv.Load(filepath.Join(mynode, ".env"))
+	if err != nil {
+		return envFileError(filepath.Join(mynode, ".env"), err)
+	}
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
+
+	if delegator == "" {
+		if delegator, err = keyAddress(mynode); err != nil {
+			return err
+		}
+	}
+
+	resp, err := queryUnbondings(delegator, "tcp://localhost:"+rpcPort)
+	if err != nil {
+		log.Errorf("❌ Failed to query unbonding delegations for %s: %v", delegator, err)
+		return err
+	}
+
+	if len(resp.UnbondingResponses) == 0 {
+		log.Infof("ℹ️ No unbonding delegations found for %s", delegator)
+	}
+
+	md := denomMetadata("tcp://localhost:" + rpcPort)
+	result := UnbondingResult{Delegator: delegator, Entries: []AccountUnbonding{}}
+	for _, u := range resp.UnbondingResponses {
+		for _, e := range u.Entries {
+			result.Entries = append(result.Entries, AccountUnbonding{
+				Validator:      u.ValidatorAddress,
+				CreationHeight: e.CreationHeight,
+				CompletionTime: e.CompletionTime,
+				Amount:         e.Balance + md.Base,
+				Display:        md.FormatString(e.Balance),
+			})
+		}
+	}
+	return render(result)
+}
+
+// UnbondingResult is the result of the unbonding list command.
+type UnbondingResult struct {
+	Delegator string             `json:"delegator" yaml:"delegator"`
+	Entries   []AccountUnbonding `json:"entries" yaml:"entries"`
+}
+
+func (r UnbondingResult) renderTable(w io.Writer) {
+	fmt.Fprintln(w, "VALIDATOR\tCREATION HEIGHT\tCOMPLETION TIME\tBALANCE")
+	for _, e := range r.Entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", e.Validator, e.CreationHeight, e.CompletionTime, e.Display)
+	}
+}
+
+func delegateCmd() *cobra.Command {
+	var mynode string
+	var validator string
+	var amount string
+	var txOpts TxOptions
+
+	cmd := &cobra.Command{
+		Use:   "delegate",
+		Short: "Delegate tokens from the node wallet to any validator",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return delegateCmdLogic(mynode, validator, amount, txOpts)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (key name of the delegator)")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&validator, "to", "", "Validator operator address (ethmvaloper1...) to delegate to")
+	cmd.MarkFlagRequired("to")
+	cmd.Flags().StringVar(&amount, "amount", "", "Amount of tokens to delegate ("+amountHelp+")")
+	cmd.MarkFlagRequired("amount")
+	addTxFlags(cmd, &txOpts)
+	return cmd
+}
+
+func delegateCmdLogic(mynode, validator, amount string, txOpts TxOptions) error {
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
+
+	err := godotenv.Load(filepath.Join(mynode, ".env"))
+	if err != nil {
+		return envFileError(filepath.Join(mynode, ".env"), err)
+	}
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
+
+	amount, err = parseAmountFlag("amount", amount, "tcp://localhost:"+rpcPort)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Attempting to delegate '%s' from '%s' to validator '%s'", amount, mynode, validator)
+
+	txArgs := []string{
+		"tx", "staking", "delegate", validator, amount,
+		"--from", nodeKeyName(mynode),
+		"--home", mynode,
+		"--keyring-backend", "test",
+		"--chain-id", configCliParams.ChaindId,
+		"--gas", "auto",
+		"--gas-prices", "7mnt",
+		"--gas-adjustment", "1.3",
+		"--node", "tcp://localhost:" + rpcPort,
+	}
+	if txOpts.GenerateOnly {
+		return generateUnsignedTx(mynode, txOpts, "delegate", "tcp://localhost:"+rpcPort, Mrmintd, txArgs...)
+	}
+
+	output, err := runCmdCaptureOutput(Mrmintd, append(txOpts.apply(txArgs), "--yes")...)
+	if err != nil {
+		log.Errorf("❌ Failed to delegate tokens: %s\nOutput: %s", err, output)
+		return txError("delegate", output, err)
+	}
+
+	log.Infof("✅ Tokens delegated successfully! Transaction output:\n%s", output)
+	return renderTxOutput("delegate", output)
+}
+
+func redelegateCmd() *cobra.Command {
+	var mynode string
+	var srcValidator string
+	var dstValidator string
+	var amount string
+	var txOpts TxOptions
+
+	cmd := &cobra.Command{
+		Use:   "redelegate",
+		Short: "Move a delegation from one validator to another without unbonding",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return redelegateCmdLogic(mynode, srcValidator, dstValidator, amount, txOpts)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (key name of the delegator)")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&srcValidator, "from-validator", "", "Validator operator address (ethmvaloper1...) to move the delegation from")
+	cmd.MarkFlagRequired("from-validator")
+	cmd.Flags().StringVar(&dstValidator, "to-validator", "", "Validator operator address (ethmvaloper1...) to move the delegation to")
+	cmd.MarkFlagRequired("to-validator")
+	cmd.Flags().StringVar(&amount, "amount", "", "Amount of tokens to redelegate ("+amountHelp+")")
+	cmd.MarkFlagRequired("amount")
+	addTxFlags(cmd, &txOpts)
+	return cmd
+}
+
+func redelegateCmdLogic(mynode, srcValidator, dstValidator, amount string, txOpts TxOptions) error {
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
+
+	err := godotenv.Load(filepath.Join(mynode, ".env"))
+	if err != nil {
+		return envFileError(filepath.Join(mynode, ".env"), err)
+	}
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
+
+	amount, err = parseAmountFlag("amount", amount, "tcp://localhost:"+rpcPort)
+	if err != nil {
+		return err
+	}
+
+	if srcValidator == dstValidator {
+		return fmt.Errorf("--from-validator and --to-validator must be different")
+	}
+
+	log.Infof("Attempting to redelegate '%s' from validator '%s' to '%s'", amount, srcValidator, dstValidator)
+
+	txArgs := []string{
+		"tx", "staking", "redelegate", srcValidator, dstValidator, amount,
+		"--from", nodeKeyName(mynode),
+		"--home", mynode,
+		"--keyring-backend", "test",
+		"--chain-id", configCliParams.ChaindId,
+		"--gas", "auto",
+		"--gas-prices", "7mnt",
+		"--gas-adjustment", "1.3",
+		"--node", "tcp://localhost:" + rpcPort,
+	}
+	if txOpts.GenerateOnly {
+		return generateUnsignedTx(mynode, txOpts, "redelegate", "tcp://localhost:"+rpcPort, Mrmintd, txArgs...)
+	}
+
+	output, err := runCmdCaptureOutput(Mrmintd, append(txOpts.apply(txArgs), "--yes")...)
+	if err != nil {
+		log.Errorf("❌ Failed to redelegate tokens: %s\nOutput: %s", err, output)
+		log.Warnf("Please note that a delegation that was itself redelegated cannot be redelegated again until the first redelegation completes.")
+		return txError("redelegate", output, err)
+	}
+
+	log.Infof("✅ Tokens redelegated successfully! Transaction output:\n%s", output)
+	return renderTxOutput("redelegate", output)
+}
+
+func cancelUnbondingCmd() *cobra.Command {
+	var mynode string
+	var validator string
+	var amount string
+	var creationHeight string
+	var txOpts TxOptions
+
+	cmd := &cobra.Command{
+		Use:   "cancel-unbonding",
+		Short: "Cancel an unbonding entry and delegate the tokens back to the validator",
+		Long: `Cancels (part of) an unbonding delegation entry and returns the tokens to the validator.
+The entry is identified by the validator and its creation height, as shown by 'unbonding list'.
+This requires a chain running Cosmos SDK v0.46 or newer.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cancelUnbondingCmdLogic(mynode, validator, amount, creationHeight, txOpts)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (key name of the delegator)")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&validator, "validator", "", "Validator operator address (ethmvaloper1...) of the unbonding entry")
+	cmd.MarkFlagRequired("validator")
+	cmd.Flags().StringVar(&amount, "amount", "", "Amount of tokens to return to the validator ("+amountHelp+")")
+	cmd.MarkFlagRequired("amount")
+	cmd.Flags().StringVar(&creationHeight, "creation-height", "", "Creation height of the unbonding entry")
+	cmd.MarkFlagRequired("creation-height")
+	addTxFlags(cmd, &txOpts)
+	return cmd
+}
+
+func cancelUnbondingCmdLogic(mynode, validator, amount, creationHeight string, txOpts TxOptions) error {
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
+
+	err := godotenv.Load(filepath.Join(mynode, ".env"))
+	if err != nil {
+		return envFileError(filepath.Join(mynode, ".env"), err)
+	}
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
+
+	amount, err = parseAmountFlag("amount", amount, "tcp://localhost:"+rpcPort)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Attempting to cancel unbonding of '%s' from validator '%s' (creation height %s)", amount, validator, creationHeight)
+
+	txArgs := []string{
+		"tx", "staking", "cancel-unbond", validator, amount, creationHeight,
+		"--from", nodeKeyName(mynode),
+		"--home", mynode,
+		"--keyring-backend", "test",
+		"--chain-id", configCliParams.ChaindId,
+		"--gas", "auto",
+		"--gas-prices", "7mnt",
+		"--gas-adjustment", "1.3",
+		"--node", "tcp://localhost:" + rpcPort,
+	}
+	if txOpts.GenerateOnly {
+		return generateUnsignedTx(mynode, txOpts, "cancel-unbond", "tcp://localhost:"+rpcPort, Mrmintd, txArgs...)
+	}
+
+	output, err := runCmdCaptureOutput(Mrmintd, append(txOpts.apply(txArgs), "--yes")...)
+	if err != nil {
+		if strings.Contains(output, "unknown command") {
+			log.Errorf("❌ This chain's ethermintd does not support cancelling unbonding delegations.")
+			return fmt.Errorf("cancel-unbond is not supported by %s", Mrmintd)
+		}
+		log.Errorf("❌ Failed to cancel unbonding: %s\nOutput: %s", err, output)
+		return txError("cancel unbonding", output, err)
+	}
+
+	log.Infof("✅ Unbonding cancelled successfully! Transaction output:\n%s", output)
+	return renderTxOutput("cancel unbonding", output)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// fakeTransport answers the commands of a test instead of ethermintd.
+type fakeTransport struct {
+	localTransport
+	run   func(args []string) (string, error)
+	calls [][]string
+}
+
+func (f *fakeTransport) Run(stdin io.Reader, stdout, stderr io.Writer, name string, args ...string) error {
+	f.calls = append(f.calls, args)
+	output, err := f.run(args)
+	io.WriteString(stdout, output)
+	return err
+}
+
+// useTransport swaps the node transport for the duration of a test.
+func useTransport(t *testing.T, tr Transport) {
+	t.Helper()
+	saved := transport
+	transport = tr
+	t.Cleanup(func() { transport = saved })
+}
+
+func flagValue(args []string, flag string) string {
+	if i := slices.Index(args, flag); i >= 0 && i+1 < len(args) {
+		return args[i+1]
+	}
+	return ""
+}
+
+// delegationPages serves total delegations in pages of the requested size
+// with base64 next keys, as the chain does.
+func delegationPages(total int) func(args []string) (string, error) {
+	return func(args []string) (string, error) {
+		if flagValue(args, "--page-key") != "" {
+			return "", fmt.Errorf("unexpected --page-key")
+		}
+		offset, _ := strconv.Atoi(flagValue(args, "--offset"))
+		limit, _ := strconv.Atoi(flagValue(args, "--limit"))
+		var page struct {
+			DelegationResponses []json.RawMessage `json:"delegation_responses"`
+			Pagination          struct {
+				NextKey *string `json:"next_key"`
+			} `json:"pagination"`
+		}
+		for i := offset; i < total && i < offset+limit; i++ {
+			page.DelegationResponses = append(page.DelegationResponses, json.RawMessage(fmt.Sprintf(
+				`{"delegation":{"validator_address":"ethmvaloper%d"},"balance":{"denom":"amnt","amount":"%d"}}`, i, i)))
+		}
+		if offset+limit < total {
+			key := "FHXZ3q0M/a7B2sZ1+g=="
+			page.Pagination.NextKey = &key
+		}
+		data, err := json.Marshal(page)
+		return "gas estimate: 1\n" + string(data), err
+	}
+}
+
+func TestQueryDelegationsPaginates(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		wantCalls int
+	}{
+		{name: "empty", total: 0, wantCalls: 1},
+		{name: "one partial page", total: 42, wantCalls: 1},
+		{name: "exactly one page", total: queryPageLimit, wantCalls: 1},
+		{name: "several pages", total: 2*queryPageLimit + 17, wantCalls: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeTransport{run: delegationPages(tt.total)}
+			useTransport(t, fake)
+
+			got, err := queryDelegations("ethm1delegator", "tcp://localhost:26657")
+			if err != nil {
+				t.Fatalf("queryDelegations: %v", err)
+			}
+			if len(got.DelegationResponses) != tt.total {
+				t.Fatalf("got %d delegations, want %d", len(got.DelegationResponses), tt.total)
+			}
+			for i, d := range got.DelegationResponses {
+				if want := fmt.Sprintf("ethmvaloper%d", i); d.Delegation.ValidatorAddress != want {
+					t.Fatalf("delegation %d is %s, want %s: pages were skipped or repeated", i, d.Delegation.ValidatorAddress, want)
+				}
+			}
+			if len(fake.calls) != tt.wantCalls {
+				t.Errorf("made %d queries, want %d", len(fake.calls), tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestQueryAllPagesStopsOnEmptyPage(t *testing.T) {
+	// A node that keeps returning a next key must not loop forever.
+	fake := &fakeTransport{run: func(args []string) (string, error) {
+		return `{"delegation_responses":[],"pagination":{"next_key":"AAAA"}}`, nil
+	}}
+	useTransport(t, fake)
+
+	if _, err := queryDelegations("ethm1delegator", "tcp://localhost:26657"); err != nil {
+		t.Fatalf("queryDelegations: %v", err)
+	}
+	if len(fake.calls) != 1 {
+		t.Errorf("made %d queries, want 1", len(fake.calls))
+	}
+}
+
+func TestQueryAllPagesReportsFailures(t *testing.T) {
+	fake := &fakeTransport{run: func(args []string) (string, error) {
+		return "Error: rpc error: code = Unavailable", fmt.Errorf("exit status 1")
+	}}
+	useTransport(t, fake)
+
+	_, err := queryDelegations("ethm1delegator", "tcp://localhost:26657")
+	if err == nil || !strings.Contains(err.Error(), "Unavailable") {
+		t.Fatalf("error = %v, want the query output", err)
+	}
+}
@@ -0,0 +1,432 @@
+package main
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+const (
+	checkPass = "pass"
+	checkWarn = "warn"
+	checkFail = "fail"
+)
+
+// SystemRequirements are the network minimums doctor checks against; the
+// chain config may override the defaults with "systemRequirements".
+type SystemRequirements struct {
+	CPUs         int    `json:"cpus" yaml:"cpus"`
+	MemoryGB     uint64 `json:"memoryGb" yaml:"memory_gb"`
+	DiskGB       uint64 `json:"diskGb" yaml:"disk_gb"`
+	Inodes       uint64 `json:"inodes" yaml:"inodes"`
+	OpenFiles    uint64 `json:"openFiles" yaml:"open_files"`
+	MaxClockSkew string `json:"maxClockSkew" yaml:"max_clock_skew"`
+}
+
+var defaultSystemRequirements = SystemRequirements{
+	CPUs:         4,
+	MemoryGB:     8,
+	DiskGB:       200,
+	Inodes:       1_000_000,
+	OpenFiles:    65536,
+	MaxClockSkew: "500ms",
+}
+
+// DoctorCheck is one line of the doctor report.
+type DoctorCheck struct {
+	Name   string `json:"name" yaml:"name"`
+	Status string `json:"status" yaml:"status"`
+	Detail string `json:"detail" yaml:"detail"`
+	Fix    string `json:"fix,omitempty" yaml:"fix,omitempty"`
+}
+
+// DoctorReport is the result of the doctor command.
+type DoctorReport struct {
+	Node   string        `json:"node" yaml:"node"`
+	Checks []DoctorCheck `json:"checks" yaml:"checks"`
+}
+
+func (r *DoctorReport) add(name, status, detail, fix string) {
+	r.Checks = append(r.Checks, DoctorCheck{Name: name, Status: status, Detail: detail, Fix: fix})
+}
+
+func (r DoctorReport) count(status string) int {
+	n := 0
+	for _, c := range r.Checks {
+		if c.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+func (r DoctorReport) renderTable(w io.Writer) {
+	fmt.Fprintln(w, "CHECK\tSTATUS\tDETAIL")
+	for _, c := range r.Checks {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", c.Name, strings.ToUpper(c.Status), c.Detail)
+	}
+	var fixes []DoctorCheck
+	for _, c := range r.Checks {
+		if c.Fix != "" && c.Status != checkPass {
+			fixes = append(fixes, c)
+		}
+	}
+	if len(fixes) > 0 {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "FIXES")
+		for _, c := range fixes {
+			fmt.Fprintf(w, "%s:\t%s\n", c.Name, c.Fix)
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "%d passed, %d warnings, %d failed\n", r.count(checkPass), r.count(checkWarn), r.count(checkFail))
+}
+
+// doctorOptions point doctor at the services it uses for outside checks.
+type doctorOptions struct {
+	NTPServer  string
+	EchoServer string
+}
+
+func doctorCmd() *cobra.Command {
+	var mynode string
+	var opts doctorOptions
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check that this machine and the node configuration can run a validator",
+		Long: `Runs pre-flight checks and prints a pass/warn/fail report with fixes:
+the container runtime, the ethermintd binary, disk space and inodes, CPU and
+memory against the network minimums, clock skew, open file limits, the node
+ports and the reachability of the boot node and the persistent peers.
+
+With --echo-server the P2P port is also checked from outside: doctor asks
+<echo-server>/check?port=<port> to connect back and expects a JSON answer
+such as {"ip": "203.0.113.7", "reachable": true}.
+
+Exits non-zero when a check fails.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return doctorCmdLogic(mynode, opts)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&opts.NTPServer, "ntp-server", "pool.ntp.org", "NTP server to measure the clock skew against")
+	cmd.Flags().StringVar(&opts.EchoServer, "echo-server", "", "URL of an echo server that checks the P2P port from outside")
+	return cmd
+}
+
+func doctorCmdLogic(mynode string, opts doctorOptions) error {
+	if activeNode.Remote != nil {
+		log.Warnf("⚠️ %s runs on %s; the system checks below describe this machine.", mynode, activeNode.Remote.Host)
+	}
+	report := DoctorReport{Node: mynode}
+
+	reqs := defaultSystemRequirements
+	if err := loadConfigCliParams(); err != nil {
+		log.Warnf("⚠️ Could not load the chain config, using the default requirements: %v", err)
+	} else if configCliParams.SystemRequirements != nil {
+		reqs = *configCliParams.SystemRequirements
+	}
+
+	envPath := filepath.Join(mynode, ".env")
+	env, err := godotenv.Read(envPath)
+	if err != nil {
+		report.add(".env", checkFail, err.Error(), "run 'port-set --mynode "+mynode+"'")
+	}
+
+	checkRuntime(&report)
+	checkBinary(&report, env["BOOT_NODE_RPC"])
+	checkSystem(&report, mynode, reqs)
+	checkClock(&report, opts.NTPServer, reqs)
+	if env != nil {
+		checkPorts(&report, mynode, env, opts.EchoServer)
+		checkBootNode(&report, env["BOOT_NODE_RPC"])
+		checkPeers(&report, env["PERSISTENT_PEERS"])
+	}
+
+	if err := render(report); err != nil {
+		return err
+	}
+	if failed := report.count(checkFail); failed > 0 {
+		return &RuntimeError{Msg: fmt.Sprintf("%d doctor checks failed", failed), Hint: "apply the fixes listed in the report and run doctor again"}
+	}
+	return nil
+}
+
+// quietOutput runs a command without the "Running:" echo, for probes whose
+// failure is part of the report.
+func quietOutput(name string, args ...string) (string, error) {
+	var output syncBuffer
+	err := transport.Run(nil, &output, &output, name, args...)
+	return strings.TrimSpace(output.String()), err
+}
+
+func checkRuntime(r *DoctorReport) {
+	if activeNode.Runtime == runtimeNative {
+		r.add("runtime", checkPass, "native, no container runtime needed", "")
+		return
+	}
+	client, err := quietOutput("docker", "version", "--format", "{{.Client.Version}}")
+	if err != nil {
+		r.add("docker", checkFail, "docker is not installed", "install Docker: https://docs.docker.com/engine/install/")
+		return
+	}
+	server, err := quietOutput("docker", "version", "--format", "{{.Server.Version}}")
+	if err != nil {
+		r.add("docker", checkFail, "docker "+client+" is installed but the daemon is not reachable", "start the Docker daemon and make sure your user may use it (docker group)")
+		return
+	}
+	r.add("docker", checkPass, "client "+client+", server "+server, "")
+}
+
+func checkBinary(r *DoctorReport, bootRpc string) {
+	version, err := quietOutput(Mrmintd, "version")
+	if err != nil {
+		r.add("ethermintd", checkFail, Mrmintd+" cannot run: "+err.Error(), "install it with 'binary install --version <version>' and pin it with 'binary use'")
+		return
+	}
+	recommended := recommendedBinaryVersion(bootRpc)
+	if recommended != "" && strings.TrimPrefix(version, "v") != strings.TrimPrefix(recommended, "v") {
+		r.add("ethermintd", checkWarn, fmt.Sprintf("%s is %s, the network recommends %s", Mrmintd, version, recommended), "run 'binary install --version "+recommended+"' and 'binary use "+recommended+"'")
+		return
+	}
+	r.add("ethermintd", checkPass, Mrmintd+" "+version, "")
+}
+
+func checkSystem(r *DoctorReport, mynode string, reqs SystemRequirements) {
+	if cpus := runtime.NumCPU(); cpus < reqs.CPUs {
+		r.add("cpu", checkWarn, fmt.Sprintf("%d CPUs, the network recommends %d", cpus, reqs.CPUs), "run the validator on a machine with more CPUs")
+	} else {
+		r.add("cpu", checkPass, fmt.Sprintf("%d CPUs", cpus), "")
+	}
+
+	if mem, err := totalMemory(); err != nil {
+		r.add("memory", checkWarn, "could not read the memory size: "+err.Error(), "")
+	} else if mem < reqs.MemoryGB<<30 {
+		r.add("memory", checkWarn, fmt.Sprintf("%s of RAM, the network recommends %d GiB", formatBytes(int64(mem)), reqs.MemoryGB), "add memory or move the validator to a larger machine")
+	} else {
+		r.add("memory", checkPass, formatBytes(int64(mem))+" of RAM", "")
+	}
+
+	// The node directory may not exist yet before init-node.
+	dir := mynode
+	if !exists(dir) {
+		dir = "."
+	}
+	disk, err := diskUsage(dir)
+	if err != nil {
+		r.add("disk", checkWarn, "could not read the free disk space: "+err.Error(), "")
+	} else {
+		if disk.FreeBytes < reqs.DiskGB<<30 {
+			r.add("disk", checkFail, fmt.Sprintf("%s free, the network needs %d GiB", formatBytes(int64(disk.FreeBytes)), reqs.DiskGB), "free up space or move the node to a larger disk; 'snapshot restore' starts from pruned data")
+		} else {
+			r.add("disk", checkPass, formatBytes(int64(disk.FreeBytes))+" free", "")
+		}
+		if disk.TotalInodes > 0 && disk.FreeInodes < reqs.Inodes {
+			r.add("inodes", checkFail, fmt.Sprintf("%d free inodes, the node needs %d", disk.FreeInodes, reqs.Inodes), "move the node to a file system with more inodes")
+		} else if disk.TotalInodes > 0 {
+			r.add("inodes", checkPass, fmt.Sprintf("%d free", disk.FreeInodes), "")
+		}
+	}
+
+	if limit, err := openFileLimit(); err != nil {
+		r.add("open files", checkWarn, "could not read the open file limit: "+err.Error(), "")
+	} else if limit < reqs.OpenFiles {
+		r.add("open files", checkWarn, fmt.Sprintf("limit is %d, the node needs %d", limit, reqs.OpenFiles), fmt.Sprintf("raise it with 'ulimit -n %d' or LimitNOFILE in the service unit", reqs.OpenFiles))
+	} else {
+		r.add("open files", checkPass, fmt.Sprintf("limit is %d", limit), "")
+	}
+}
+
+func checkClock(r *DoctorReport, server string, reqs SystemRequirements) {
+	if server == "" {
+		return
+	}
+	maxSkew, err := time.ParseDuration(reqs.MaxClockSkew)
+	if err != nil {
+		maxSkew = 500 * time.Millisecond
+	}
+	skew, err := ntpClockSkew(server)
+	if err != nil {
+		r.add("clock", checkWarn, "could not reach NTP server "+server+": "+err.Error(), "allow outgoing UDP port 123 or pass --ntp-server")
+		return
+	}
+	detail := fmt.Sprintf("%s off %s", skew.Round(time.Millisecond), server)
+	if skew < 0 {
+		skew = -skew
+	}
+	switch {
+	case skew > 10*maxSkew:
+		r.add("clock", checkFail, detail, "enable time synchronisation (timedatectl set-ntp true, chrony or ntpd)")
+	case skew > maxSkew:
+		r.add("clock", checkWarn, detail, "enable time synchronisation (timedatectl set-ntp true, chrony or ntpd)")
+	default:
+		r.add("clock", checkPass, detail, "")
+	}
+}
+
+// ntpClockSkew asks an NTP server for the time with a single SNTP request
+// and returns how far the local clock is ahead of it.
+func ntpClockSkew(server string) (time.Duration, error) {
+	conn, err := net.DialTimeout("udp", net.JoinHostPort(server, "123"), 5*time.Second)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	req := make([]byte, 48)
+	req[0] = 0x1b // version 3, client mode
+	sent := time.Now()
+	if _, err := conn.Write(req); err != nil {
+		return 0, err
+	}
+	resp := make([]byte, 48)
+	if _, err := io.ReadFull(conn, resp); err != nil {
+		return 0, err
+	}
+	received := time.Now()
+
+	// Transmit timestamp: seconds and fraction since 1900.
+	secs := binary.BigEndian.Uint32(resp[40:44])
+	frac := binary.BigEndian.Uint32(resp[44:48])
+	if secs == 0 {
+		return 0, fmt.Errorf("empty answer")
+	}
+	const ntpEpochOffset = 2208988800
+	serverTime := time.Unix(int64(secs)-ntpEpochOffset, int64(frac)*1e9>>32)
+	local := sent.Add(received.Sub(sent) / 2)
+	return local.Sub(serverTime), nil
+}
+
+func checkPorts(r *DoctorReport, mynode string, env map[string]string, echoServer string) {
+	running := nodeContainerRunning(mynode)
+	for _, key := range nodePortKeys {
+		port := env[key]
+		if port == "" {
+			r.add(key, checkFail, "not set in "+filepath.Join(mynode, ".env"), "run 'port-set --mynode "+mynode+"'")
+			continue
+		}
+		if err := checkPort(port); err != nil {
+			if running {
+				r.add(key, checkPass, port+" is in use by the running node", "")
+			} else {
+				r.add(key, checkFail, port+" is taken by another process: "+err.Error(), "stop the other process or pick new ports with 'port-set --mynode "+mynode+"'")
+			}
+			continue
+		}
+		r.add(key, checkPass, port+" is free", "")
+	}
+	if echoServer != "" && env["P2P_PORT"] != "" {
+		checkPortFromOutside(r, env["P2P_PORT"], running, echoServer)
+	}
+}
+
+// checkPortFromOutside asks the echo server to connect back to port. When the
+// node is not running, doctor listens on the port itself for the check.
+func checkPortFromOutside(r *DoctorReport, port string, running bool, echoServer string) {
+	name := "P2P_PORT reachability"
+	if !running {
+		ln, err := net.Listen("tcp", ":"+port)
+		if err != nil {
+			r.add(name, checkWarn, "could not listen on "+port+": "+err.Error(), "")
+			return
+		}
+		defer ln.Close()
+		go func() {
+			for {
+				conn, err := ln.Accept()
+				if err != nil {
+					return
+				}
+				conn.Close()
+			}
+		}()
+	}
+
+	var resp struct {
+		IP        string `json:"ip"`
+		Reachable bool   `json:"reachable"`
+		Error     string `json:"error"`
+	}
+	client := &http.Client{Timeout: 20 * time.Second}
+	httpResp, err := client.Get(strings.TrimRight(echoServer, "/") + "/check?port=" + url.QueryEscape(port))
+	if err == nil {
+		defer httpResp.Body.Close()
+		err = json.NewDecoder(httpResp.Body).Decode(&resp)
+	}
+	if err != nil {
+		r.add(name, checkWarn, "echo server failed: "+err.Error(), "")
+		return
+	}
+	if !resp.Reachable {
+		detail := "port " + port + " is not reachable from outside"
+		if resp.IP != "" {
+			detail += " at " + resp.IP
+		}
+		if resp.Error != "" {
+			detail += ": " + resp.Error
+		}
+		r.add(name, checkFail, detail, "open TCP port "+port+" in the firewall and forward it to this machine")
+		return
+	}
+	r.add(name, checkPass, "reachable at "+net.JoinHostPort(resp.IP, port), "")
+}
+
+func checkBootNode(r *DoctorReport, bootRpc string) {
+	if bootRpc == "" {
+		r.add("boot node", checkFail, "BOOT_NODE_RPC is not set", "run 'port-set' to regenerate the node .env")
+		return
+	}
+	height, err := fetchBlockHeight(bootRpc)
+	if err != nil {
+		r.add("boot node", checkFail, bootRpc+" is not reachable: "+err.Error(), "check the network connection and outgoing firewall rules")
+		return
+	}
+	r.add("boot node", checkPass, fmt.Sprintf("%s at height %d", bootRpc, height), "")
+}
+
+func checkPeers(r *DoctorReport, peers string) {
+	var reachable, total int
+	var failed []string
+	for _, peer := range strings.Split(peers, ",") {
+		peer = strings.TrimSpace(peer)
+		if peer == "" {
+			continue
+		}
+		total++
+		addr := peer
+		if _, hostPort, ok := strings.Cut(peer, "@"); ok {
+			addr = hostPort
+		}
+		conn, err := net.DialTimeout("tcp", addr, 5*time.Second)
+		if err != nil {
+			failed = append(failed, addr)
+			continue
+		}
+		conn.Close()
+		reachable++
+	}
+	switch {
+	case total == 0:
+		r.add("peers", checkWarn, "PERSISTENT_PEERS is empty", "set persistent peers so the node finds the network")
+	case reachable == 0:
+		r.add("peers", checkFail, "none of the persistent peers is reachable: "+strings.Join(failed, ", "), "check outgoing TCP connections to the peers' P2P ports")
+	case len(failed) > 0:
+		r.add("peers", checkWarn, fmt.Sprintf("%d of %d reachable; unreachable: %s", reachable, total, strings.Join(failed, ", ")), "remove stale peers from PERSISTENT_PEERS")
+	default:
+		r.add("peers", checkPass, fmt.Sprintf("all %d reachable", total), "")
+	}
+}
@@ -0,0 +1,61 @@
+//go:build !windows
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+type diskStats struct {
+	FreeBytes   uint64
+	TotalInodes uint64
+	FreeInodes  uint64
+}
+
+func diskUsage(dir string) (diskStats, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return diskStats{}, err
+	}
+	return diskStats{
+		FreeBytes:   uint64(st.Bavail) * uint64(st.Bsize),
+		TotalInodes: uint64(st.Files),
+		FreeInodes:  uint64(st.Ffree),
+	}, nil
+}
+
+func openFileLimit() (uint64, error) {
+	var rl syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &rl); err != nil {
+		return 0, err
+	}
+	return uint64(rl.Cur), nil
+}
+
+// totalMemory reads MemTotal from /proc/meminfo on Linux and asks sysctl
+// elsewhere.
+func totalMemory() (uint64, error) {
+	if f, err := os.Open("/proc/meminfo"); err == nil {
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) >= 2 && fields[0] == "MemTotal:" {
+				kb, err := strconv.ParseUint(fields[1], 10, 64)
+				return kb * 1024, err
+			}
+		}
+		return 0, fmt.Errorf("no MemTotal in /proc/meminfo")
+	}
+	out, err := exec.Command("sysctl", "-n", "hw.memsize").Output()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(out)), 10, 64)
+}
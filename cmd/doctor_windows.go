@@ -0,0 +1,25 @@
+//go:build windows
+
+package main
+
+import "errors"
+
+type diskStats struct {
+	FreeBytes   uint64
+	TotalInodes uint64
+	FreeInodes  uint64
+}
+
+var errUnsupportedCheck = errors.New("not supported on Windows")
+
+func diskUsage(dir string) (diskStats, error) {
+	return diskStats{}, errUnsupportedCheck
+}
+
+func openFileLimit() (uint64, error) {
+	return 0, errUnsupportedCheck
+}
+
+func totalMemory() (uint64, error) {
+	return 0, errUnsupportedCheck
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// Process exit codes, one per error category, so scripts and the fleet
+// runner can tell a bad configuration from a rejected transaction.
+const (
+	exitGeneric    = 1
+	exitUsage      = 2
+	exitConfig     = 3
+	exitChainQuery = 4
+	exitTxFailed   = 5
+	exitRuntime    = 6
+	exitUserAbort  = 130
+)
+
+// ConfigError is a missing or invalid .env, flag or configuration file.
+type ConfigError struct {
+	Msg  string
+	Err  error
+	Hint string
+}
+
+func (e *ConfigError) Error() string { return joinErr(e.Msg, e.Err) }
+func (e *ConfigError) Unwrap() error { return e.Err }
+
+// ChainQueryError is a query to the node or the boot node that failed or
+// returned something that could not be parsed.
+type ChainQueryError struct {
+	Query string
+	Err   error
+	Hint  string
+}
+
+func (e *ChainQueryError) Error() string { return joinErr("failed to "+e.Query, e.Err) }
+func (e *ChainQueryError) Unwrap() error { return e.Err }
+
+// TxFailedError is a transaction that could not be broadcast or that the
+// chain rejected with a non-zero code.
+type TxFailedError struct {
+	Action string
+	TxHash string
+	Code   uint32
+	RawLog string
+	Err    error
+}
+
+func (e *TxFailedError) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("%s failed: tx %s rejected with code %d: %s", e.Action, e.TxHash, e.Code, e.RawLog)
+	}
+	return joinErr(e.Action+" failed", e.Err)
+}
+
+func (e *TxFailedError) Unwrap() error { return e.Err }
+
+// RuntimeError is a failure of docker, ethermintd or the file system.
+type RuntimeError struct {
+	Msg  string
+	Err  error
+	Hint string
+}
+
+func (e *RuntimeError) Error() string { return joinErr(e.Msg, e.Err) }
+func (e *RuntimeError) Unwrap() error { return e.Err }
+
+// UserAbort is the user declining a prompt.
+type UserAbort struct {
+	Msg string
+}
+
+func (e *UserAbort) Error() string { return e.Msg }
+
+func joinErr(msg string, err error) string {
+	if err == nil {
+		return msg
+	}
+	return msg + ": " + err.Error()
+}
+
+// envFileError reports a .env file that could not be loaded.
+func envFileError(path string, err error) error {
+	return &ConfigError{
+		Msg:  "failed to load " + path,
+		Err:  err,
+		Hint: "run 'port-set --mynode <node>' to generate the node .env, and make sure the global .env exists in the working directory",
+	}
+}
+
+// txError turns the output of a broadcast into a TxFailedError when the
+// chain rejected the transaction.
+func txError(action, output string, err error) error {
+	if err != nil {
+		return &TxFailedError{Action: action, Err: err}
+	}
+	if tx, perr := parseTxResult(output); perr == nil && tx.Code != 0 {
+		return &TxFailedError{Action: action, TxHash: tx.TxHash, Code: tx.Code, RawLog: tx.RawLog}
+	}
+	return nil
+}
+
+// exitCode maps an error returned by a command to the process exit code.
+func exitCode(err error) int {
+	var (
+		configErr *ConfigError
+		queryErr  *ChainQueryError
+		txErr     *TxFailedError
+		rtErr     *RuntimeError
+		abort     *UserAbort
+	)
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &abort):
+		return exitUserAbort
+	case errors.As(err, &configErr):
+		return exitConfig
+	case errors.As(err, &txErr):
+		return exitTxFailed
+	case errors.As(err, &queryErr):
+		return exitChainQuery
+	case errors.As(err, &rtErr):
+		return exitRuntime
+	case isUsageError(err):
+		return exitUsage
+	}
+	return exitGeneric
+}
+
+// errorHint returns the remediation hint of the first typed error in err's
+// chain that has one.
+func errorHint(err error) string {
+	var (
+		configErr *ConfigError
+		queryErr  *ChainQueryError
+		txErr     *TxFailedError
+		rtErr     *RuntimeError
+	)
+	switch {
+	case errors.As(err, &configErr) && configErr.Hint != "":
+		return configErr.Hint
+	case errors.As(err, &txErr):
+		if txErr.Code != 0 {
+			return "check the raw log above, then inspect the transaction with 'query-tx --tx-hash " + txErr.TxHash + "'"
+		}
+		return "make sure the node is running and synced ('health --mynode <node>') and the key has funds for fees"
+	case errors.As(err, &queryErr):
+		if queryErr.Hint != "" {
+			return queryErr.Hint
+		}
+		return "make sure the node is running and its RPC port is reachable ('health --mynode <node>')"
+	case errors.As(err, &rtErr) && rtErr.Hint != "":
+		return rtErr.Hint
+	}
+	return ""
+}
+
+// isUsageError reports whether cobra rejected the command line itself.
+func isUsageError(err error) bool {
+	var usage *usageError
+	return errors.As(err, &usage)
+}
+
+// usageError wraps the flag and argument errors of cobra.
+type usageError struct {
+	Err error
+}
+
+func (e *usageError) Error() string { return e.Err.Error() }
+func (e *usageError) Unwrap() error { return e.Err }
+
+// wrapArgsErrors makes the positional argument checks (cobra.ExactArgs and
+// friends) of cmd and its subcommands return usage errors, like flag errors.
+func wrapArgsErrors(cmd *cobra.Command) {
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(c *cobra.Command, args []string) error {
+			if err := validate(c, args); err != nil {
+				return &usageError{Err: err}
+			}
+			return nil
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		wrapArgsErrors(sub)
+	}
+}
+
+// reportError prints err and its hint once, at the top level.
+func reportError(w io.Writer, err error) {
+	fmt.Fprintln(w, "Error:", err)
+	if hint := errorHint(err); hint != "" {
+		fmt.Fprintln(w, "Hint:", hint)
+	}
+}
@@ -0,0 +1,255 @@
+package main
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+// stakeFeeReserve is the amount kept aside for transaction fees when a
+// validator pays for its own create-validator transaction.
+const stakeFeeReserve = "1MNT"
+
+func feegrantCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "feegrant",
+		Short: "Let a sponsor account pay transaction fees for new validators",
+		Long: `A platform sponsor account can grant a basic or periodic fee allowance to a new
+validator address. The validator then passes --fee-granter <sponsor-address> to any
+tx command and the fees are paid from the sponsor allowance.`,
+	}
+	cmd.AddCommand(feegrantGrantCmd(), feegrantRevokeCmd(), feegrantListCmd())
+	return cmd
+}
+
+func feegrantGrantCmd() *cobra.Command {
+	var mynode string
+	var grantee string
+	var spendLimit string
+	var expireIn time.Duration
+	var period time.Duration
+	var periodLimit string
+	var allowedMessages []string
+	var txOpts TxOptions
+
+	cmd := &cobra.Command{
+		Use:   "grant",
+		Short: "Grant a basic or periodic fee allowance from the sponsor key",
+		Long: `Grants a fee allowance from the sponsor key (--mynode) to a validator address.
+Without --period a basic allowance is created, limited by --spend-limit and --expire-in.
+With --period and --period-limit a periodic allowance is created that resets every period.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return feegrantGrantCmdLogic(mynode, grantee, spendLimit, expireIn, period, periodLimit, allowedMessages, txOpts)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (key name of the sponsor account)")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&grantee, "grantee", "", "Validator address (ethm1...) receiving the allowance")
+	cmd.MarkFlagRequired("grantee")
+	cmd.Flags().StringVar(&spendLimit, "spend-limit", "", "Total amount the grantee may spend on fees ("+amountHelp+"), unlimited when empty")
+	cmd.Flags().DurationVar(&expireIn, "expire-in", 0, "How long the allowance stays valid (e.g. 720h), no expiry when 0")
+	cmd.Flags().DurationVar(&period, "period", 0, "Reset period of a periodic allowance (e.g. 24h)")
+	cmd.Flags().StringVar(&periodLimit, "period-limit", "", "Amount the grantee may spend per period, "+amountHelp+" (required with --period)")
+	cmd.Flags().StringSliceVar(&allowedMessages, "allowed-messages", nil, "Restrict the allowance to these message type URLs")
+	addTxFlags(cmd, &txOpts)
+	return cmd
+}
+
+func feegrantGrantCmdLogic(mynode, grantee, spendLimit string, expireIn, period time.Duration, periodLimit string, allowedMessages []string, txOpts TxOptions) error {
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
+
+	err := godotenv.Load(filepath.Join(mynode, ".env"))
+	if err != nil {
+		return envFileError(filepath.Join(mynode, ".env"), err)
+	}
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
+
+	if periodLimit != "" {
+		periodLimit, err = parseAmountListFlag("period-limit", periodLimit, "tcp://localhost:"+rpcPort)
+		if err != nil {
+			return err
+		}
+	}
+
+	if spendLimit != "" {
+		spendLimit, err = parseAmountListFlag("spend-limit", spendLimit, "tcp://localhost:"+rpcPort)
+		if err != nil {
+			return err
+		}
+	}
+	rpcNode := "tcp://localhost:" + rpcPort
+
+	if (period == 0) != (periodLimit == "") {
+		return &ConfigError{Msg: "--period and --period-limit must be used together"}
+	}
+
+	granter, err := txSignerAddress(mynode, txOpts)
+	if err != nil {
+		return err
+	}
+
+	txArgs := []string{"tx", "feegrant", "grant", granter, grantee}
+	if spendLimit != "" {
+		txArgs = append(txArgs, "--spend-limit", spendLimit)
+	}
+	if expireIn > 0 {
+		txArgs = append(txArgs, "--expiration", time.Now().Add(expireIn).UTC().Format(time.RFC3339))
+	}
+	if period > 0 {
+		txArgs = append(txArgs, "--period", strconv.FormatInt(int64(period.Seconds()), 10), "--period-limit", periodLimit)
+	}
+	if len(allowedMessages) > 0 {
+		txArgs = append(txArgs, "--allowed-messages", strings.Join(allowedMessages, ","))
+	}
+	txArgs = append(txArgs,
+		"--from", nodeKeyName(mynode),
+		"--home", mynode,
+		"--keyring-backend", "test",
+		"--chain-id", configCliParams.ChaindId,
+		"--gas", "auto",
+		"--gas-prices", "7mnt",
+		"--gas-adjustment", "1.3",
+		"--node", rpcNode,
+	)
+
+	allowance := "basic"
+	if period > 0 {
+		allowance = "periodic"
+	}
+	log.Infof("Granting a %s fee allowance from %s to %s", allowance, granter, grantee)
+
+	if txOpts.GenerateOnly {
+		return generateUnsignedTx(mynode, txOpts, "feegrant-grant", rpcNode, Mrmintd, txArgs...)
+	}
+
+	output, err := runCmdCaptureOutput(Mrmintd, append(txOpts.apply(txArgs), "--yes")...)
+	if err != nil {
+		log.Errorf("❌ Failed to grant fee allowance to %s: %s\nOutput: %s", grantee, err, output)
+		return txError("feegrant grant", output, err)
+	}
+
+	log.Infof("✅ Fee allowance granted successfully! Transaction output:\n%s", output)
+	log.Infof("The validator can now use --fee-granter %s on its tx commands.", granter)
+	return renderTxOutput("feegrant grant", output)
+}
+
+func feegrantRevokeCmd() *cobra.Command {
+	var mynode string
+	var grantee string
+	var txOpts TxOptions
+
+	cmd := &cobra.Command{
+		Use:   "revoke",
+		Short: "Revoke a fee allowance given by the sponsor key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return feegrantRevokeCmdLogic(mynode, grantee, txOpts)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (key name of the sponsor account)")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&grantee, "grantee", "", "Validator address (ethm1...) losing the allowance")
+	cmd.MarkFlagRequired("grantee")
+	addTxFlags(cmd, &txOpts)
+	return cmd
+}
+
+func feegrantRevokeCmdLogic(mynode, grantee string, txOpts TxOptions) error {
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
+
+	err := godotenv.Load(filepath.Join(mynode, ".env"))
+	if err != nil {
+		return envFileError(filepath.Join(mynode, ".env"), err)
+	}
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
+	rpcNode := "tcp://localhost:" + rpcPort
+
+	granter, err := txSignerAddress(mynode, txOpts)
+	if err != nil {
+		return err
+	}
+
+	txArgs := []string{
+		"tx", "feegrant", "revoke", granter, grantee,
+		"--from", nodeKeyName(mynode),
+		"--home", mynode,
+		"--keyring-backend", "test",
+		"--chain-id", configCliParams.ChaindId,
+		"--gas", "auto",
+		"--gas-prices", "7mnt",
+		"--gas-adjustment", "1.3",
+		"--node", rpcNode,
+	}
+	if txOpts.GenerateOnly {
+		return generateUnsignedTx(mynode, txOpts, "feegrant-revoke", rpcNode, Mrmintd, txArgs...)
+	}
+
+	output, err := runCmdCaptureOutput(Mrmintd, append(txOpts.apply(txArgs), "--yes")...)
+	if err != nil {
+		log.Errorf("❌ Failed to revoke fee allowance from %s: %s\nOutput: %s", grantee, err, output)
+		return txError("feegrant revoke", output, err)
+	}
+
+	log.Infof("✅ Fee allowance revoked successfully! Transaction output:\n%s", output)
+	return renderTxOutput("feegrant revoke", output)
+}
+
+func feegrantListCmd() *cobra.Command {
+	var mynode string
+	var received bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List fee allowances given by (or, with --received, given to) the node key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return feegrantListCmdLogic(mynode, received)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	cmd.Flags().BoolVar(&received, "received", false, "List allowances received by the node key instead of given by it")
+	return cmd
+}
+
+func feegrantListCmdLogic(mynode string, received bool) error {
+	err := godotenv.Load(filepath.Join(mynode, ".env"))
+	if err != nil {
+		return envFileError(filepath.Join(mynode, ".env"), err)
+	}
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
+
+	address, err := keyAddress(mynode)
+	if err != nil {
+		return err
+	}
+
+	query := "grants-by-granter"
+	if received {
+		query = "grants-by-grantee"
+	}
+
+	output, err := runCmdCaptureOutput(Mrmintd, "query", "feegrant", query, address, "--node", "tcp://localhost:"+rpcPort, "--output", "json")
+	if err != nil {
+		log.Errorf("❌ Failed to query fee allowances for %s: %s\nOutput: %s", address, err, output)
+		return &ChainQueryError{Query: "query fee allowances of " + address, Err: err}
+	}
+
+	return renderRaw(output)
+}
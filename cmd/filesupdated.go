@@ -6,46 +6,35 @@ import (
 	"fmt"
 	"io"
 	"io/fs"
-	"log"
 	"net/http"
 	"os"
 
 	"github.com/manifoldco/promptui"
 )
 
-func updateGenesis(mynode string) {
+func updateGenesis(mynode string) error {
 
 	genesisURL := configCliParams.GenesisUrl //"https://web3sports.s3.ap-south-1.amazonaws.com/blockchain/genesis.json"
 
-	resp, err := http.Get(genesisURL)
+	body, err := fetchURL(genesisURL)
 	if err != nil {
-		log.Fatalln(err)
-	}
-	//We Read the response body on the line below.
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatalln(err)
+		return &ConfigError{Msg: "failed to download genesis.json", Err: err, Hint: "check the network connection and the genesisUrl of the chain config"}
 	}
 	// Create the file
-	err1 := os.WriteFile(mynode+"/config/genesis.json", body, os.ModePerm)
-	if err1 != nil {
-		fmt.Println(err1)
+	if err := os.WriteFile(mynode+"/config/genesis.json", body, os.ModePerm); err != nil {
+		return &RuntimeError{Msg: "failed to write genesis.json", Err: err}
 	}
-	fmt.Println("Genesis updated.")
+	fmt.Fprintln(os.Stderr, "Genesis updated.")
+	return nil
 }
 
-func updateConfigToml(mynode string) {
+func updateConfigToml(mynode string) error {
 
 	confiToml := configCliParams.ConfigTomlUrl //"https://web3sports.s3.ap-south-1.amazonaws.com/blockchain/config.toml"
 
-	resp, err := http.Get(confiToml)
+	body, err := fetchURL(confiToml)
 	if err != nil {
-		log.Fatalln(err)
-	}
-	//We Read the response body on the line below.
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Fatalln(err)
+		return &ConfigError{Msg: "failed to download config.toml", Err: err, Hint: "check the network connection and the configToml url of the chain config"}
 	}
 	// sb := string(body)
 	// log.Printf(sb)
@@ -56,12 +45,25 @@ func updateConfigToml(mynode string) {
 	// update := strings.Replace(sb, "persistent_peers = \"\"", "persistent_peers = \""+newgetPeerId+"\"", 1)
 
 	// Create the file
-	err1 := os.WriteFile(mynode+"/config/config.toml", []byte(body), os.ModePerm)
-	if err1 != nil {
-		fmt.Println(err1)
+	if err := os.WriteFile(mynode+"/config/config.toml", body, os.ModePerm); err != nil {
+		return &RuntimeError{Msg: "failed to write config.toml", Err: err}
 	}
 
-	fmt.Println("Config.toml updated.")
+	fmt.Fprintln(os.Stderr, "Config.toml updated.")
+	return nil
+}
+
+// fetchURL returns the body of a successful GET of url.
+func fetchURL(url string) ([]byte, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
 }
 
 func exists(path string) bool {
@@ -75,33 +77,33 @@ func exists(path string) bool {
 	return false
 }
 
-func yesNo(msg string) bool {
+// yesNo asks a yes/no question; interrupting the prompt is a UserAbort.
+func yesNo(msg string) (bool, error) {
 	prompt := promptui.Select{
 		Label: msg + "[Yes/No]",
 		Items: []string{"Yes", "No"},
 	}
 	_, result, err := prompt.Run()
+	if errors.Is(err, promptui.ErrInterrupt) || errors.Is(err, promptui.ErrEOF) {
+		return false, &UserAbort{Msg: "prompt cancelled"}
+	}
 	if err != nil {
-		log.Fatalf("Prompt failed %v\n", err)
+		return false, &RuntimeError{Msg: "prompt failed", Err: err}
 	}
-	return result == "Yes"
+	return result == "Yes", nil
 }
 
 /** TEMP - UNUSED FUNCTION */
 
-func getConfigCliParams() ConfigCliParams {
+func getConfigCliParams() (ConfigCliParams, error) {
 
-	fmt.Println("Config parameters fetching...")
+	fmt.Fprintln(os.Stderr, "Config parameters fetching...")
 	configParams := "https://web3sports.s3.ap-south-1.amazonaws.com/blockchain/mrmintChainCLIconfig.json"
 
-	resp, err := http.Get(configParams)
-	if err != nil {
-		log.Fatalln(err)
-	}
-
-	body, err := io.ReadAll(resp.Body)
+	var cResp ConfigCliParams
+	body, err := fetchURL(configParams)
 	if err != nil {
-		log.Fatalln(err)
+		return cResp, &ConfigError{Msg: "failed to fetch the chain config", Err: err, Hint: "check the network connection; the chain config is downloaded from " + configParams}
 	}
 	// body := []byte(`{
 	//     "persistent_peers": "bc54163107a8bc2ee48568cd537596037dd8fb3a@3.110.16.39:26656",
@@ -113,11 +115,18 @@ func getConfigCliParams() ConfigCliParams {
 	// }`)
 
 	// //Create a variable of the same type as our model
-	var cResp ConfigCliParams
+	if err := json.Unmarshal(body, &cResp); err != nil {
+		return cResp, &ConfigError{Msg: "invalid chain config", Err: err}
+	}
+	return cResp, nil
+}
 
-	err1 := json.Unmarshal(body, &cResp)
-	if err1 != nil {
-		log.Fatal(err1)
+// loadConfigCliParams fetches the chain config into configCliParams.
+func loadConfigCliParams() error {
+	params, err := getConfigCliParams()
+	if err != nil {
+		return err
 	}
-	return cResp
+	configCliParams = params
+	return nil
 }
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+// FleetNodeResult is the outcome of one node in a fleet run.
+type FleetNodeResult struct {
+	Node    string          `json:"node" yaml:"node"`
+	Success bool            `json:"success" yaml:"success"`
+	Detail  string          `json:"detail" yaml:"detail"`
+	Result  json.RawMessage `json:"result,omitempty" yaml:"-"`
+}
+
+// FleetResult is the result of a fleet command.
+type FleetResult struct {
+	Command string            `json:"command" yaml:"command"`
+	Nodes   []FleetNodeResult `json:"nodes" yaml:"nodes"`
+}
+
+func (r FleetResult) renderTable(w io.Writer) {
+	fmt.Fprintln(w, "NODE\tRESULT\tDETAIL")
+	for _, n := range r.Nodes {
+		status := "✅ ok"
+		if !n.Success {
+			status = "❌ failed"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", n.Node, status, n.Detail)
+	}
+}
+
+// fleetOptions selects the nodes of a fleet run and how many run at once.
+type fleetOptions struct {
+	Nodes    []string
+	Parallel int
+}
+
+func addFleetFlags(cmd *cobra.Command, opts *fleetOptions) {
+	cmd.Flags().StringSliceVar(&opts.Nodes, "nodes", nil, "Comma separated node names (default: every registered node)")
+	cmd.Flags().IntVar(&opts.Parallel, "parallel", 4, "Maximum number of nodes processed at the same time")
+}
+
+func fleetCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fleet",
+		Short: "Run a command across many nodes in parallel",
+		Long: `Runs a command for every registered node (or --nodes) with a bounded worker
+pool and prints one row per node. A failing node does not stop the others; the
+command exits non-zero if any node failed.`,
+	}
+	cmd.AddCommand(fleetStatusCmd(), fleetWithdrawRewardsCmd(), fleetVoteCmd(), fleetRestartCmd())
+	return cmd
+}
+
+func fleetStatusCmd() *cobra.Command {
+	var opts fleetOptions
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the health of every node",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFleet("status", opts, func(node string) []string {
+				return []string{"health", "--mynode", node}
+			}, healthDetail)
+		},
+	}
+	addFleetFlags(cmd, &opts)
+	return cmd
+}
+
+func fleetWithdrawRewardsCmd() *cobra.Command {
+	var opts fleetOptions
+	cmd := &cobra.Command{
+		Use:   "withdraw-rewards",
+		Short: "Withdraw the rewards of every node",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFleet("withdraw-rewards", opts, func(node string) []string {
+				return []string{"withdraw-rewards", "--mynode", node}
+			}, txDetail)
+		},
+	}
+	addFleetFlags(cmd, &opts)
+	return cmd
+}
+
+func fleetVoteCmd() *cobra.Command {
+	var opts fleetOptions
+	var proposalID uint64
+	var voteOption string
+
+	cmd := &cobra.Command{
+		Use:   "vote",
+		Short: "Vote on a governance proposal with every node",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFleet("vote", opts, func(node string) []string {
+				return []string{"vote-proposal", "--mynode", node, "--proposal-id", fmt.Sprintf("%d", proposalID), "--option", voteOption}
+			}, txDetail)
+		},
+	}
+	cmd.Flags().Uint64Var(&proposalID, "proposal-id", 0, "The ID of the proposal to vote on")
+	cmd.MarkFlagRequired("proposal-id")
+	cmd.Flags().StringVar(&voteOption, "option", "", "Your vote option: yes, no, abstain, no_with_veto")
+	cmd.MarkFlagRequired("option")
+	addFleetFlags(cmd, &opts)
+	return cmd
+}
+
+func fleetRestartCmd() *cobra.Command {
+	var opts fleetOptions
+	cmd := &cobra.Command{
+		Use:   "restart",
+		Short: "Restart the container of every node",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runFleet("restart", opts, func(node string) []string {
+				return []string{"restart-node", "--mynode", node}
+			}, nil)
+		},
+	}
+	addFleetFlags(cmd, &opts)
+	return cmd
+}
+
+// runFleet runs the per-node command of every selected node in a child
+// process of this binary. Each child loads its own .env and runs in its own
+// workspace, so nodes cannot see each other's environment and an error
+// only ends that node's run.
+func runFleet(name string, opts fleetOptions, nodeArgs func(node string) []string, detail func(out []byte) (string, bool)) error {
+	nodes, err := fleetNodes(opts.Nodes)
+	if err != nil {
+		return err
+	}
+	if opts.Parallel < 1 {
+		return fmt.Errorf("--parallel must be at least 1")
+	}
+	self, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the mrmintchain binary: %w", err)
+	}
+
+	log.Infof("🚀 Running %s on %d nodes (%d at a time)", name, len(nodes), opts.Parallel)
+
+	results := make([]FleetNodeResult, len(nodes))
+	sem := make(chan struct{}, opts.Parallel)
+	var wg sync.WaitGroup
+	for i, node := range nodes {
+		wg.Add(1)
+		go func(i int, node NodeEntry) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = runFleetNode(self, node, append(nodeArgs(node.Name), "--output", outputJSON), detail)
+		}(i, node)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if !r.Success {
+			failed++
+		}
+	}
+	if err := render(FleetResult{Command: name, Nodes: results}); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%s failed on %d of %d nodes", name, failed, len(nodes))
+	}
+	return nil
+}
+
+func runFleetNode(self string, node NodeEntry, args []string, detail func(out []byte) (string, bool)) FleetNodeResult {
+	result := FleetNodeResult{Node: node.Name}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(self, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if node.Home != "" {
+		cmd.Dir = node.Workspace()
+	}
+	err := cmd.Run()
+
+	if data, jerr := extractJSON(stdout.String()); jerr == nil {
+		result.Result = data
+	}
+	result.Success = err == nil
+	if detail != nil {
+		var ok bool
+		result.Detail, ok = detail(result.Result)
+		result.Success = result.Success && ok
+	}
+	if !result.Success && result.Detail == "" {
+		result.Detail = lastErrorLine(stderr.String(), err)
+	}
+	if result.Success && result.Detail == "" {
+		result.Detail = "done"
+	}
+	if result.Success {
+		log.Infof("✅ %s: %s", node.Name, result.Detail)
+	} else {
+		log.Errorf("❌ %s: %s", node.Name, result.Detail)
+	}
+	return result
+}
+
+// fleetNodes returns the registry entries of the selected nodes; names that
+// are not registered are run from the current directory.
+func fleetNodes(names []string) ([]NodeEntry, error) {
+	registry, err := loadNodeRegistry()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		if len(registry.Nodes) == 0 {
+			return nil, fmt.Errorf("no nodes registered; add them with 'nodes add' or pass --nodes")
+		}
+		return registry.Nodes, nil
+	}
+
+	var nodes []NodeEntry
+	for _, name := range names {
+		entry, ok := registry.Find(name)
+		if !ok {
+			entry = NodeEntry{Name: name}
+		}
+		nodes = append(nodes, entry)
+	}
+	return nodes, nil
+}
+
+func healthDetail(out []byte) (string, bool) {
+	var h HealthResult
+	if err := json.Unmarshal(out, &h); err != nil {
+		return "", true
+	}
+	if !h.Healthy {
+		return strings.Join(h.Problems, "; "), false
+	}
+	return fmt.Sprintf("height %d, %s", h.LatestHeight, h.ValidatorStatus), true
+}
+
+// txDetail reports the transaction hash; a transaction rejected by the chain
+// counts as a failure even though the command itself succeeded.
+func txDetail(out []byte) (string, bool) {
+	var tx TxResult
+	if err := json.Unmarshal(out, &tx); err != nil || tx.TxHash == "" {
+		return "", true
+	}
+	if tx.Code != 0 {
+		return fmt.Sprintf("tx %s failed with code %d: %s", tx.TxHash, tx.Code, tx.RawLog), false
+	}
+	return "tx " + tx.TxHash, true
+}
+
+// lastErrorLine picks the most useful line of a failed child's stderr.
+func lastErrorLine(stderr string, err error) string {
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		line := strings.TrimSpace(lines[i])
+		if strings.HasPrefix(line, "Error:") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "Error:"))
+		}
+	}
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" && !strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "Usage:") {
+			return line
+		}
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return "failed"
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+// maxBlocksBehind is how far the node may lag behind the boot node and
+// still be reported healthy.
+const maxBlocksBehind = 5
+
+// HealthResult is the result of the health command.
+type HealthResult struct {
+	Node            string   `json:"node" yaml:"node"`
+	Reachable       bool     `json:"reachable" yaml:"reachable"`
+	LatestHeight    int64    `json:"latest_height" yaml:"latest_height"`
+	CatchingUp      bool     `json:"catching_up" yaml:"catching_up"`
+	BootNodeHeight  int64    `json:"boot_node_height" yaml:"boot_node_height"`
+	BlocksBehind    int64    `json:"blocks_behind" yaml:"blocks_behind"`
+	ValidatorStatus string   `json:"validator_status,omitempty" yaml:"validator_status,omitempty"`
+	Jailed          bool     `json:"jailed" yaml:"jailed"`
+	Healthy         bool     `json:"healthy" yaml:"healthy"`
+	Problems        []string `json:"problems" yaml:"problems"`
+}
+
+func (r HealthResult) renderTable(w io.Writer) {
+	fmt.Fprintf(w, "Node\t%s\n", r.Node)
+	fmt.Fprintf(w, "Reachable\t%t\n", r.Reachable)
+	fmt.Fprintf(w, "Latest height\t%d\n", r.LatestHeight)
+	fmt.Fprintf(w, "Catching up\t%t\n", r.CatchingUp)
+	fmt.Fprintf(w, "Boot node height\t%d\n", r.BootNodeHeight)
+	fmt.Fprintf(w, "Blocks behind\t%d\n", r.BlocksBehind)
+	fmt.Fprintf(w, "Validator status\t%s\n", r.ValidatorStatus)
+	fmt.Fprintf(w, "Jailed\t%t\n", r.Jailed)
+	fmt.Fprintf(w, "Healthy\t%t\n", r.Healthy)
+	for _, p := range r.Problems {
+		fmt.Fprintf(w, "Problem\t%s\n", p)
+	}
+}
+
+type NodeStatus struct {
+	SyncInfo struct {
+		LatestBlockHeight string `json:"latest_block_height"`
+		CatchingUp        bool   `json:"catching_up"`
+	} `json:"SyncInfo"`
+	SyncInfoLower struct {
+		LatestBlockHeight string `json:"latest_block_height"`
+		CatchingUp        bool   `json:"catching_up"`
+	} `json:"sync_info"`
+}
+
+// height returns the latest block height; newer versions print sync_info in
+// snake case.
+func (s NodeStatus) height() (int64, bool) {
+	h := s.SyncInfo.LatestBlockHeight
+	catchingUp := s.SyncInfo.CatchingUp
+	if h == "" {
+		h = s.SyncInfoLower.LatestBlockHeight
+		catchingUp = s.SyncInfoLower.CatchingUp
+	}
+	height, _ := strconv.ParseInt(h, 10, 64)
+	return height, catchingUp
+}
+
+func healthCmd() *cobra.Command {
+	var mynode string
+
+	cmd := &cobra.Command{
+		Use:   "health",
+		Short: "Check that the node is reachable, synced and validating",
+		Long: `Checks that the node RPC answers, that the node is not catching up and is
+within a few blocks of the boot node, and that the validator is bonded and not
+jailed. Exits non-zero when the node is unhealthy.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return healthCmdLogic(mynode)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	return cmd
+}
+
+func healthCmdLogic(mynode string) error {
+	env, err := godotenv.Read(filepath.Join(mynode, ".env"))
+	if err != nil {
+		return envFileError(filepath.Join(mynode, ".env"), err)
+	}
+	if env["RPC_PORT"] == "" {
+		return &ConfigError{Msg: "RPC_PORT is not set in the .env of " + mynode, Hint: "run 'port-set --mynode " + mynode + "' to regenerate the node .env"}
+	}
+
+	result := checkNodeHealth(mynode, "tcp://localhost:"+env["RPC_PORT"], env["BOOT_NODE_RPC"])
+	if err := render(result); err != nil {
+		return err
+	}
+	if !result.Healthy {
+		return &ChainQueryError{
+			Query: "check the health of " + mynode,
+			Err:   errors.New(strings.Join(result.Problems, "; ")),
+			Hint:  "start the node with 'start-node --mynode " + mynode + "' and wait for it to catch up with the boot node",
+		}
+	}
+	return nil
+}
+
+func checkNodeHealth(mynode, node, bootRpc string) HealthResult {
+	result := HealthResult{Node: mynode, Problems: []string{}}
+
+	status, err := queryNodeStatus(node)
+	if err != nil {
+		result.Problems = append(result.Problems, fmt.Sprintf("node RPC %s is not reachable: %v", node, err))
+		return result
+	}
+	result.Reachable = true
+	result.LatestHeight, result.CatchingUp = status.height()
+	if result.CatchingUp {
+		result.Problems = append(result.Problems, "node is still catching up")
+	}
+
+	if bootRpc == "" {
+		log.Warnf("⚠️ BOOT_NODE_RPC is not set, skipping the sync check against the boot node")
+	} else if bootStatus, err := queryNodeStatus(bootRpc); err != nil {
+		log.Warnf("⚠️ Could not query boot node %s: %v", bootRpc, err)
+	} else {
+		result.BootNodeHeight, _ = bootStatus.height()
+		result.BlocksBehind = result.BootNodeHeight - result.LatestHeight
+		if result.BlocksBehind > maxBlocksBehind {
+			result.Problems = append(result.Problems, fmt.Sprintf("node is %d blocks behind the boot node", result.BlocksBehind))
+		}
+	}
+
+	valoper, err := validatorOperatorAddress(mynode)
+	if err != nil {
+		result.Problems = append(result.Problems, err.Error())
+	} else if output, err := runCmdCaptureOutput(Mrmintd, "query", "staking", "validator", valoper, "--node", node, "--output", "json"); err != nil {
+		result.Problems = append(result.Problems, "validator not found on chain")
+	} else if info, err := parseValidatorInfo(output); err == nil {
+		result.ValidatorStatus = info.Status
+		result.Jailed = info.Jailed
+		if info.Jailed {
+			result.Problems = append(result.Problems, "validator is jailed")
+		} else if info.Status != "BOND_STATUS_BONDED" {
+			result.Problems = append(result.Problems, "validator is not bonded")
+		}
+	}
+
+	result.Healthy = len(result.Problems) == 0
+	return result
+}
+
+func queryNodeStatus(node string) (NodeStatus, error) {
+	var status NodeStatus
+	output, err := runCmdCaptureOutput(Mrmintd, "status", "--node", node)
+	if err != nil {
+		if output = strings.TrimSpace(output); output != "" {
+			return status, fmt.Errorf("%w: %s", err, output)
+		}
+		return status, err
+	}
+	data, err := extractJSON(output)
+	if err != nil {
+		return status, err
+	}
+	err = json.Unmarshal(data, &status)
+	return status, err
+}
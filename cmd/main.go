@@ -19,6 +19,25 @@ func main() {
 		Use:   "mrmintchain",
 		Short: "Full mrmint validator setup CLI tool",
 	}
+	addOutputFlag(rootCmd)
+	// Errors are reported once by main, together with their hint.
+	rootCmd.SilenceErrors = true
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &usageError{Err: err}
+	})
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if err := validateOutputFormat(); err != nil {
+			return &usageError{Err: err}
+		}
+		// The command line is valid; from here on a failure is not a usage
+		// problem, so cobra must not print the usage text.
+		cmd.SilenceUsage = true
+		if err := applyNodeFlag(cmd); err != nil {
+			return err
+		}
+		applyPinnedBinary()
+		return nil
+	}
 
 	rootCmd.AddCommand(
 		initNodeCmd(),
@@ -43,11 +62,38 @@ func main() {
 		submitParamChangeProposalCmd(),
 		queryTxCmd(),
 		createValidatorCmd(),
+		txCmd(),
+		multisigCmd(),
+		authzCmd(),
+		feegrantCmd(),
+		delegationsCmd(),
+		delegateCmd(),
+		redelegateCmd(),
+		unbondingCmd(),
+		cancelUnbondingCmd(),
+		accountCmd(),
+		healthCmd(),
+		addressCmd(),
+		nodesCmd(),
+		fleetCmd(),
+		logsCmd(),
+		setupCmd(),
+		onboardCmd(),
+		snapshotCmd(),
+		backupCmd(),
+		upgradeCmd(),
+		binaryCmd(),
+		doctorCmd(),
+		nodeGroupCmd(),
+		peersCmd(),
+		sentryCmd(),
 	)
 
+	wrapArgsErrors(rootCmd)
+
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println("Error:", err)
-		os.Exit(1)
+		reportError(os.Stderr, err)
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -58,31 +104,22 @@ func autoRunCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "auto-setup",
 		Short: "Automatically run the full validator setup process",
+		Long: `Runs init, key and ports in order. Progress is saved in <mynode>/` + onboardingStateFile + `,
+so running auto-setup again resumes from the first step that did not complete.
+Use 'setup status' to see the progress and 'setup reset --step <step>' to redo a step.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			fmt.Println("🚀 Starting full validator setup...")
-
-			if err := initNodeLogic(mynode); err != nil {
-				return fmt.Errorf("❌ init node failed: %w", err)
-			}
-
-			fmt.Println("🚀 Starting validator key generation...")
-			if err := addKeyCmdLogic(mynode); err != nil {
-				return fmt.Errorf("❌ add key failed: %w", err)
-			}
-
-			if err := addGenesisAccountLogic(mynode); err != nil {
-				return fmt.Errorf("❌ add genesis account failed: %w", err)
-			}
+			fmt.Fprintln(os.Stderr, "🚀 Starting full validator setup...")
 
-			if err := portsAndEnvGenerationLogic(mynode); err != nil {
-				return fmt.Errorf("❌ collect gentxs failed: %w", err)
+			// Completed steps are skipped, so a failed setup resumes where it stopped.
+			if err := runOnboarding(mynode, stepPorts, onboardOptions{}); err != nil {
+				return err
 			}
 
-			fmt.Println("✅ Validator setup completed successfully. Please run start-node command to start validator node.")
+			fmt.Fprintln(os.Stderr, "✅ Validator setup completed successfully. Please run start-node command to start validator node, or onboard to run the remaining steps.")
 			return nil
 		},
 	}
 	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
-	cmd.MarkFlagRequired("mynode")
+	requireNode(cmd)
 	return cmd
 }
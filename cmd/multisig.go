@@ -0,0 +1,290 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+func multisigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "multisig",
+		Short: "Multisig validator operator workflow (create, sign, combine)",
+		Long: `Operate a validator from a K-of-N multisig key instead of a single node key.
+
+1. Create the multisig key from the members' public keys with 'multisig create'.
+2. Generate an unsigned transaction with any tx command, e.g.
+   mrmintchain vote-proposal --mynode X --proposal-id 1 --option yes --generate-only --signer treasury
+3. Each member signs the file on their own machine with 'multisig sign'.
+4. Combine at least K partial signatures with 'multisig combine' and broadcast.`,
+	}
+	cmd.AddCommand(multisigCreateCmd(), multisigSignCmd(), multisigCombineCmd())
+	return cmd
+}
+
+func multisigCreateCmd() *cobra.Command {
+	var mynode string
+	var name string
+	var threshold int
+	var pubkeys []string
+	var memberKeys []string
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a K-of-N multisig key from member public keys",
+		Long: `Imports each member public key into the node keyring and creates a multisig key
+from them. Public keys are given in the JSON form printed by 'ethermintd keys show --pubkey',
+e.g. --pubkey '{"@type":"/ethermint.crypto.v1.ethsecp256k1.PubKey","key":"A..."}'.
+Keys that already exist in the keyring can be referenced with --member-key instead.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return multisigCreateCmdLogic(mynode, name, threshold, pubkeys, memberKeys)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (keyring home)")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&name, "name", "", "Name of the multisig key to create (e.g. treasury)")
+	cmd.MarkFlagRequired("name")
+	cmd.Flags().IntVar(&threshold, "threshold", 0, "Number of signatures (K) required to sign a transaction")
+	cmd.MarkFlagRequired("threshold")
+	cmd.Flags().StringArrayVar(&pubkeys, "pubkey", nil, "Member public key JSON (repeat for each member)")
+	cmd.Flags().StringArrayVar(&memberKeys, "member-key", nil, "Name of a member key already in the keyring (repeat for each member)")
+	return cmd
+}
+
+func multisigCreateCmdLogic(mynode, name string, threshold int, pubkeys, memberKeys []string) error {
+	members := len(pubkeys) + len(memberKeys)
+	if members < 2 {
+		return &ConfigError{Msg: fmt.Sprintf("a multisig key needs at least 2 members, got %d", members)}
+	}
+	if threshold < 1 || threshold > members {
+		return &ConfigError{Msg: fmt.Sprintf("threshold must be between 1 and %d, got %d", members, threshold)}
+	}
+
+	keyNames := append([]string{}, memberKeys...)
+	for i, pubkey := range pubkeys {
+		memberName := fmt.Sprintf("%s-member-%d", name, i+1)
+		output, err := runCmdCaptureOutput(Mrmintd, "keys", "add", memberName, "--pubkey", pubkey, "--home", mynode, "--keyring-backend", "test")
+		if err != nil {
+			log.Errorf("❌ Failed to import member public key %d: %s\nOutput: %s", i+1, err, output)
+			return &RuntimeError{Msg: fmt.Sprintf("failed to import member public key %d", i+1), Err: err}
+		}
+		log.Infof("✅ Imported member public key as '%s'", memberName)
+		keyNames = append(keyNames, memberName)
+	}
+
+	output, err := runCmdCaptureOutput(Mrmintd, "keys", "add", name,
+		"--multisig", strings.Join(keyNames, ","),
+		"--multisig-threshold", strconv.Itoa(threshold),
+		"--home", mynode,
+		"--keyring-backend", "test",
+	)
+	if err != nil {
+		log.Errorf("❌ Failed to create multisig key '%s': %s\nOutput: %s", name, err, output)
+		return &RuntimeError{Msg: "failed to create multisig key '" + name + "'", Err: err}
+	}
+
+	multisigAddress, err := keyringAddress(mynode, name)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("✅ Multisig key '%s' created (%d-of-%d)", name, threshold, members)
+	log.Infof("Multisig address : %s", multisigAddress)
+	if ethAddress, err := Bech32ToEthAddress(multisigAddress); err == nil {
+		log.Infof("Converted into Ethereum(0x) format : %s", ethAddress)
+	}
+	log.Infof("===> Generate transactions for it with: --generate-only --signer %s", name)
+	return nil
+}
+
+func multisigSignCmd() *cobra.Command {
+	var mynode string
+	var member string
+	var outputFile string
+
+	cmd := &cobra.Command{
+		Use:   "sign [unsigned_tx_file]",
+		Short: "Produce a partial signature for a multisig transaction",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return multisigSignCmdLogic(mynode, member, invocationPath(args[0]), optionalInvocationPath(outputFile))
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (keyring home holding the member key)")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&member, "member", "", "Name of your member key in the keyring")
+	cmd.MarkFlagRequired("member")
+	cmd.Flags().StringVar(&outputFile, "output-file", "", "Path of the partial signature file (default <file>-<member>.sig.json)")
+	return cmd
+}
+
+func multisigSignCmdLogic(mynode, member, path, outputFile string) error {
+	txFile, err := readOfflineTxFile(path)
+	if err != nil {
+		return err
+	}
+	if txFile.Signed {
+		return &ConfigError{Msg: "transaction file " + path + " is already signed"}
+	}
+
+	unsignedPath, err := writeTempTx(txFile.Tx)
+	if err != nil {
+		return err
+	}
+	defer transport.Remove(unsignedPath)
+
+	// The partial signature is written next to the node and copied back.
+	sigPath := unsignedPath + ".sig"
+	defer transport.Remove(sigPath)
+
+	if outputFile == "" {
+		outputFile = fmt.Sprintf("%s-%s.sig.json", strings.TrimSuffix(strings.TrimSuffix(path, ".json"), "-unsigned"), member)
+	}
+
+	log.Infof("Signing %s transaction as member '%s' of multisig %s", txFile.Kind, member, txFile.Signer)
+
+	output, err := runCmdCaptureOutput(Mrmintd,
+		"tx", "sign", unsignedPath,
+		"--multisig", txFile.Signer,
+		"--from", member,
+		"--home", mynode,
+		"--keyring-backend", "test",
+		"--chain-id", txFile.ChainID,
+		"--offline",
+		"--account-number", txFile.AccountNumber,
+		"--sequence", txFile.Sequence,
+		"--output-document", sigPath,
+	)
+	if err != nil {
+		log.Errorf("❌ Failed to sign multisig transaction: %s\nOutput: %s", err, output)
+		return &RuntimeError{Msg: "failed to sign multisig transaction as member '" + member + "'", Err: err}
+	}
+	signature, err := transport.ReadFile(sigPath)
+	if err != nil {
+		return &RuntimeError{Msg: "failed to read partial signature", Err: err}
+	}
+	if err := os.WriteFile(outputFile, signature, 0600); err != nil {
+		return &RuntimeError{Msg: "failed to write partial signature " + outputFile, Err: err}
+	}
+
+	log.Infof("✅ Partial signature written to %s", outputFile)
+	log.Info("===> Send it to the coordinator, who combines the signatures with 'mrmintchain multisig combine'.")
+	return nil
+}
+
+func multisigCombineCmd() *cobra.Command {
+	var mynode string
+	var keyName string
+	var outputFile string
+	var broadcast bool
+
+	cmd := &cobra.Command{
+		Use:   "combine [unsigned_tx_file] [signature_file]...",
+		Short: "Combine partial signatures into a signed multisig transaction",
+		Args:  cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			signatureFiles := make([]string, 0, len(args)-1)
+			for _, file := range args[1:] {
+				signatureFiles = append(signatureFiles, invocationPath(file))
+			}
+			return multisigCombineCmdLogic(mynode, keyName, invocationPath(args[0]), signatureFiles, optionalInvocationPath(outputFile), broadcast)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (keyring home holding the multisig key)")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&keyName, "key", "", "Multisig key name (default: the key recorded in the transaction file)")
+	cmd.Flags().StringVar(&outputFile, "output-file", "", "Path of the signed transaction file (default <file>-signed.json)")
+	cmd.Flags().BoolVar(&broadcast, "broadcast", false, "Broadcast the combined transaction through the node right away")
+	return cmd
+}
+
+func multisigCombineCmdLogic(mynode, keyName, path string, signatureFiles []string, outputFile string, broadcast bool) error {
+	txFile, err := readOfflineTxFile(path)
+	if err != nil {
+		return err
+	}
+	if txFile.Signed {
+		return &ConfigError{Msg: "transaction file " + path + " is already signed"}
+	}
+	if keyName == "" {
+		keyName = txFile.KeyName
+	}
+	if keyName == "" {
+		return &ConfigError{Msg: "transaction file " + path + " does not name the multisig key", Hint: "pass it with --key"}
+	}
+
+	// The signature files are copied to where ethermintd runs.
+	var signaturePaths []string
+	defer func() {
+		for _, p := range signaturePaths {
+			transport.Remove(p)
+		}
+	}()
+	for _, sigFile := range signatureFiles {
+		signature, err := os.ReadFile(sigFile)
+		if err != nil {
+			return &ConfigError{Msg: "failed to read signature file " + sigFile, Err: err}
+		}
+		sigPath, err := writeTempTx(signature)
+		if err != nil {
+			return err
+		}
+		signaturePaths = append(signaturePaths, sigPath)
+	}
+
+	unsignedPath, err := writeTempTx(txFile.Tx)
+	if err != nil {
+		return err
+	}
+	defer transport.Remove(unsignedPath)
+
+	signedPath := unsignedPath + ".signed"
+	defer transport.Remove(signedPath)
+
+	log.Infof("Combining %d partial signatures for multisig '%s'", len(signatureFiles), keyName)
+
+	args := []string{"tx", "multisign", unsignedPath, keyName}
+	args = append(args, signaturePaths...)
+	args = append(args,
+		"--home", mynode,
+		"--keyring-backend", "test",
+		"--chain-id", txFile.ChainID,
+		"--offline",
+		"--account-number", txFile.AccountNumber,
+		"--sequence", txFile.Sequence,
+		"--output-document", signedPath,
+	)
+	output, err := runCmdCaptureOutput(Mrmintd, args...)
+	if err != nil {
+		log.Errorf("❌ Failed to combine signatures: %s\nOutput: %s", err, output)
+		log.Warnf("Please ensure at least the threshold number of members have signed the same transaction file.")
+		return &RuntimeError{Msg: "failed to combine signatures", Err: err}
+	}
+
+	signedTx, err := transport.ReadFile(signedPath)
+	if err != nil {
+		return &RuntimeError{Msg: "failed to read combined transaction", Err: err}
+	}
+	txFile.Tx = json.RawMessage(strings.TrimSpace(string(signedTx)))
+	txFile.Signed = true
+
+	if outputFile == "" {
+		outputFile = strings.TrimSuffix(strings.TrimSuffix(path, ".json"), "-unsigned") + "-signed.json"
+	}
+	if err := writeOfflineTxFile(outputFile, txFile); err != nil {
+		return err
+	}
+	log.Infof("✅ Signed multisig transaction written to %s", outputFile)
+
+	if !broadcast {
+		log.Infof("===> Broadcast it with: mrmintchain tx broadcast --mynode %s %s", filepath.Base(mynode), outputFile)
+		return nil
+	}
+	return txBroadcastCmdLogic(mynode, outputFile)
+}
@@ -0,0 +1,494 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// nodeConfigFile holds the typed configuration of a node. The node .env
+	// is generated from it for docker compose and commands that read .env.
+	nodeConfigFile = "node.yaml"
+	// nodeConfigVersion is the schema version this CLI writes.
+	nodeConfigVersion = 1
+	// defaultNodeImage is used when neither node.yaml nor a .env names an image.
+	defaultNodeImage = "kamleshesp/mrmintchain:latest"
+)
+
+// nodeConfigMigrations[i] upgrades a node.yaml from schema version i+1 to
+// i+2. Version 0 is the legacy .env, which 'node config migrate' converts.
+var nodeConfigMigrations = []func(*NodeConfig){}
+
+// NodeConfig is the configuration of one node.
+type NodeConfig struct {
+	SchemaVersion int         `json:"schema_version" yaml:"schema_version"`
+	Image         string      `json:"image" yaml:"image"`
+	Ports         NodePorts   `json:"ports" yaml:"ports"`
+	Network       NodeNetwork `json:"network" yaml:"network"`
+	// DockerNetwork is the user-defined network the container joins, so a
+	// validator and its sentries reach each other by container name.
+	DockerNetwork string      `json:"docker_network,omitempty" yaml:"docker_network,omitempty"`
+	Sentry        *NodeSentry `json:"sentry,omitempty" yaml:"sentry,omitempty"`
+}
+
+// NodeSentry links a validator and its sentry nodes: a validator lists its
+// sentries in Nodes, a sentry names its validator.
+type NodeSentry struct {
+	Validator string   `json:"validator,omitempty" yaml:"validator,omitempty"`
+	Nodes     []string `json:"nodes,omitempty" yaml:"nodes,omitempty"`
+}
+
+// ServicePort is a port a node listens on and the host address it is
+// published on.
+type ServicePort struct {
+	Port int    `json:"port" yaml:"port"`
+	Bind string `json:"bind" yaml:"bind"`
+}
+
+type NodePorts struct {
+	P2P     ServicePort `json:"p2p" yaml:"p2p"`
+	RPC     ServicePort `json:"rpc" yaml:"rpc"`
+	GRPC    ServicePort `json:"grpc" yaml:"grpc"`
+	GRPCWeb ServicePort `json:"grpc_web" yaml:"grpc_web"`
+	JSONRPC ServicePort `json:"json_rpc" yaml:"json_rpc"`
+}
+
+type NodeNetwork struct {
+	PersistentPeers []string `json:"persistent_peers" yaml:"persistent_peers"`
+	Seeds           []string `json:"seeds,omitempty" yaml:"seeds,omitempty"`
+	BootNodeRpc     string   `json:"boot_node_rpc" yaml:"boot_node_rpc"`
+}
+
+// service returns the port of the portServices entry called name; ok is
+// false for a name NodePorts has no field for.
+func (p *NodePorts) service(name string) (*ServicePort, bool) {
+	switch name {
+	case "p2p":
+		return &p.P2P, true
+	case "rpc":
+		return &p.RPC, true
+	case "grpc":
+		return &p.GRPC, true
+	case "grpc-web":
+		return &p.GRPCWeb, true
+	case "json-rpc":
+		return &p.JSONRPC, true
+	}
+	return nil, false
+}
+
+// configKey is the node.yaml key of a port service.
+func configKey(s portService) string {
+	return "ports." + strings.ReplaceAll(s.Name, "-", "_")
+}
+
+// applyDefaults fills the settings a node.yaml may leave out.
+func (c *NodeConfig) applyDefaults() {
+	if c.Image == "" {
+		c.Image = defaultNodeImage
+	}
+	binds, _ := bindAddresses(nil)
+	for _, s := range portServices {
+		if sp, ok := c.Ports.service(s.Name); ok && sp.Bind == "" {
+			sp.Bind = binds[s.Name]
+		}
+	}
+}
+
+// Validate returns every problem of the configuration.
+func (c NodeConfig) Validate() []string {
+	var problems []string
+	if c.SchemaVersion != nodeConfigVersion {
+		problems = append(problems, fmt.Sprintf("schema_version is %d, expected %d", c.SchemaVersion, nodeConfigVersion))
+	}
+	if strings.TrimSpace(c.Image) == "" {
+		problems = append(problems, "image is not set")
+	}
+
+	used := map[int]string{}
+	for _, s := range portServices {
+		sp, ok := c.Ports.service(s.Name)
+		if !ok {
+			problems = append(problems, "ports has no "+configKey(s))
+			continue
+		}
+		if sp.Port < 1 || sp.Port > 65535 {
+			problems = append(problems, fmt.Sprintf("%s.port %d is outside 1-65535", configKey(s), sp.Port))
+		} else if other, ok := used[sp.Port]; ok {
+			problems = append(problems, fmt.Sprintf("%s.port %d is also used by %s", configKey(s), sp.Port, other))
+		} else {
+			used[sp.Port] = s.Name
+		}
+		if net.ParseIP(sp.Bind) == nil {
+			problems = append(problems, fmt.Sprintf("%s.bind %q is not an IP address", configKey(s), sp.Bind))
+		}
+	}
+
+	for _, peer := range c.Network.PersistentPeers {
+		if err := validatePeer(peer); err != nil {
+			problems = append(problems, fmt.Sprintf("network.persistent_peers: %v", err))
+		}
+	}
+	for _, seed := range c.Network.Seeds {
+		if err := validatePeer(seed); err != nil {
+			problems = append(problems, fmt.Sprintf("network.seeds: %v", err))
+		}
+	}
+	if c.Network.BootNodeRpc != "" {
+		u, err := url.Parse(c.Network.BootNodeRpc)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "tcp") {
+			problems = append(problems, fmt.Sprintf("network.boot_node_rpc %q is not an http(s) or tcp URL", c.Network.BootNodeRpc))
+		}
+	}
+	return problems
+}
+
+// validatePeer checks a persistent peer of the form <node id>@<host>:<port>.
+func validatePeer(peer string) error {
+	id, addr, ok := strings.Cut(peer, "@")
+	if !ok {
+		return fmt.Errorf("%q is not <node id>@<host>:<port>", peer)
+	}
+	if raw, err := hex.DecodeString(id); err != nil || len(raw) != 20 {
+		return fmt.Errorf("%q has an invalid node id, expected 40 hex characters", peer)
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || host == "" {
+		return fmt.Errorf("%q has an invalid address", peer)
+	}
+	if _, err := parsePort(port); err != nil {
+		return fmt.Errorf("%q: %v", peer, err)
+	}
+	return nil
+}
+
+// envContent renders the node .env generated from the configuration.
+func (c NodeConfig) envContent() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Generated from %s, edit that file instead.\n", nodeConfigFile)
+	for _, s := range portServices {
+		sp, ok := c.Ports.service(s.Name)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, "%s=%d\n%s=%s\n", s.Key, sp.Port, s.BindKey, sp.Bind)
+	}
+	fmt.Fprintf(&b, "PERSISTENT_PEERS=%s\nSEEDS=%s\nBOOT_NODE_RPC=%s\nIMAGE_NAME=%s\n",
+		strings.Join(c.Network.PersistentPeers, ","), strings.Join(c.Network.Seeds, ","), c.Network.BootNodeRpc, c.Image)
+	return b.String()
+}
+
+func nodeConfigError(mynode string, problems []string) error {
+	return &ConfigError{
+		Msg:  filepath.Join(mynode, nodeConfigFile) + " is invalid",
+		Err:  errors.New(strings.Join(problems, "; ")),
+		Hint: "fix the listed settings, then run 'node config validate --mynode " + mynode + "'",
+	}
+}
+
+// readNodeConfig reads node.yaml as written, upgrading older schema versions.
+func readNodeConfig(mynode string) (NodeConfig, error) {
+	path := filepath.Join(mynode, nodeConfigFile)
+	var cfg NodeConfig
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, err
+	}
+	if err != nil {
+		return cfg, &ConfigError{Msg: "failed to read " + path, Err: err}
+	}
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return cfg, &ConfigError{Msg: "failed to parse " + path, Err: err}
+	}
+	if cfg.SchemaVersion > nodeConfigVersion {
+		return cfg, &ConfigError{
+			Msg:  fmt.Sprintf("%s has schema version %d, this CLI supports up to %d", path, cfg.SchemaVersion, nodeConfigVersion),
+			Hint: "upgrade the CLI",
+		}
+	}
+	if cfg.SchemaVersion < 1 {
+		return cfg, &ConfigError{Msg: path + " has no schema_version", Hint: "set schema_version: " + strconv.Itoa(nodeConfigVersion)}
+	}
+	for cfg.SchemaVersion < nodeConfigVersion {
+		nodeConfigMigrations[cfg.SchemaVersion-1](&cfg)
+		cfg.SchemaVersion++
+	}
+	return cfg, nil
+}
+
+// loadNodeConfig returns the validated configuration of mynode. Nodes set up
+// before node.yaml existed are read from their .env files, see
+// legacyNodeConfig.
+func loadNodeConfig(mynode string) (NodeConfig, error) {
+	cfg, err := readNodeConfig(mynode)
+	if errors.Is(err, os.ErrNotExist) {
+		var notes []string
+		if cfg, notes, err = legacyNodeConfig(mynode); err != nil {
+			return cfg, err
+		}
+		for _, note := range notes {
+			log.Warnf("⚠️ %s", note)
+		}
+		log.Warnf("⚠️ %s has no %s, run 'node config migrate --mynode %s'", mynode, nodeConfigFile, mynode)
+	} else if err != nil {
+		return cfg, err
+	}
+	cfg.applyDefaults()
+	if problems := cfg.Validate(); len(problems) > 0 {
+		return cfg, nodeConfigError(mynode, problems)
+	}
+	return cfg, nil
+}
+
+// legacyNodeConfig builds a configuration from the node .env and the global
+// .env. A key set in both is taken from the node .env, which is the value
+// godotenv.Load used since it loaded that file first; every such conflict is
+// reported in notes. Binds missing from an old .env stay on 0.0.0.0, where
+// those nodes have always been published.
+func legacyNodeConfig(mynode string) (NodeConfig, []string, error) {
+	cfg := NodeConfig{SchemaVersion: nodeConfigVersion}
+	envPath := filepath.Join(mynode, ".env")
+	env, err := godotenv.Read(envPath)
+	if err != nil {
+		return cfg, nil, envFileError(envPath, err)
+	}
+
+	var notes []string
+	if global, err := godotenv.Read(".env"); err == nil {
+		keys := make([]string, 0, len(global))
+		for key := range global {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			own, ok := env[key]
+			switch {
+			case !ok || own == "":
+				env[key] = global[key]
+			case own != global[key]:
+				notes = append(notes, fmt.Sprintf("%s is %q in %s and %q in the global .env, using %q", key, own, envPath, global[key], own))
+			}
+		}
+	}
+
+	for _, s := range portServices {
+		sp, ok := cfg.Ports.service(s.Name)
+		if !ok {
+			continue
+		}
+		if env[s.Key] == "" {
+			return cfg, notes, &ConfigError{Msg: s.Key + " is not set in " + envPath, Hint: "run 'port-set --mynode " + mynode + "' to choose the node ports"}
+		}
+		port, err := parsePort(env[s.Key])
+		if err != nil {
+			return cfg, notes, &ConfigError{Msg: "invalid " + s.Key + " in " + envPath, Err: err}
+		}
+		sp.Port = port
+		sp.Bind = env[s.BindKey]
+		if sp.Bind == "" {
+			sp.Bind = bindPublic
+		}
+	}
+	cfg.Network.PersistentPeers = splitPeers(env["PERSISTENT_PEERS"])
+	cfg.Network.Seeds = splitPeers(env["SEEDS"])
+	cfg.Network.BootNodeRpc = env["BOOT_NODE_RPC"]
+	cfg.Image = env["IMAGE_NAME"]
+	return cfg, notes, nil
+}
+
+// splitPeers splits a comma separated peer list, dropping empty entries.
+func splitPeers(list string) []string {
+	var peers []string
+	for _, peer := range strings.Split(list, ",") {
+		if peer = strings.TrimSpace(peer); peer != "" {
+			peers = append(peers, peer)
+		}
+	}
+	return peers
+}
+
+// saveNodeConfig writes node.yaml and regenerates the node .env from it.
+func saveNodeConfig(mynode string, cfg NodeConfig) error {
+	cfg.SchemaVersion = nodeConfigVersion
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return &RuntimeError{Msg: "failed to encode " + nodeConfigFile, Err: err}
+	}
+	if err := os.MkdirAll(mynode, os.ModePerm); err != nil {
+		return &RuntimeError{Msg: "failed to create node directory", Err: err}
+	}
+	configPath := filepath.Join(mynode, nodeConfigFile)
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		return &RuntimeError{Msg: "failed to write " + configPath, Err: err}
+	}
+	envPath := filepath.Join(mynode, ".env")
+	if err := os.WriteFile(envPath, []byte(cfg.envContent()), 0644); err != nil {
+		return &RuntimeError{Msg: "failed to write " + envPath, Err: err}
+	}
+	log.Infof("✅ %s and %s written", configPath, envPath)
+	return nil
+}
+
+// nodeImage is the image already configured for mynode, if any.
+func nodeImage(mynode string) string {
+	if cfg, err := readNodeConfig(mynode); err == nil && cfg.Image != "" {
+		return cfg.Image
+	}
+	if cfg, _, err := legacyNodeConfig(mynode); err == nil && cfg.Image != "" {
+		return cfg.Image
+	}
+	if global, err := godotenv.Read(".env"); err == nil {
+		return global["IMAGE_NAME"]
+	}
+	return ""
+}
+
+func nodeGroupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "node",
+		Short: "Inspect the configuration of a node",
+	}
+	cmd.AddCommand(nodeConfigCmd())
+	return cmd
+}
+
+func nodeConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Show, validate and migrate node.yaml",
+		Long: `Each node keeps its settings in <node>/node.yaml: the Docker image, the ports
+and bind addresses of its services and the network peers. The node .env is
+generated from it, so edit node.yaml and run 'node config migrate' to
+regenerate the .env.`,
+	}
+	cmd.AddCommand(nodeConfigShowCmd(), nodeConfigValidateCmd(), nodeConfigMigrateCmd())
+	return cmd
+}
+
+func nodeConfigShowCmd() *cobra.Command {
+	var mynode string
+
+	cmd := &cobra.Command{
+		Use:   "show",
+		Short: "Print the effective node configuration",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadNodeConfig(mynode)
+			if err != nil {
+				return err
+			}
+			return render(cfg)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	return cmd
+}
+
+func nodeConfigValidateCmd() *cobra.Command {
+	var mynode string
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check node.yaml, its generated .env and port conflicts with other nodes",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nodeConfigValidateLogic(mynode)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	return cmd
+}
+
+func nodeConfigValidateLogic(mynode string) error {
+	cfg, err := readNodeConfig(mynode)
+	if errors.Is(err, os.ErrNotExist) {
+		return &ConfigError{
+			Msg:  filepath.Join(mynode, nodeConfigFile) + " does not exist",
+			Hint: "run 'node config migrate --mynode " + mynode + "' to create it from the node .env",
+		}
+	}
+	if err != nil {
+		return err
+	}
+	cfg.applyDefaults()
+	problems := cfg.Validate()
+
+	reserved, _ := reservedPorts(mynode)
+	for _, s := range portServices {
+		sp, ok := cfg.Ports.service(s.Name)
+		if !ok {
+			continue
+		}
+		if owner, ok := reserved[sp.Port]; ok {
+			problems = append(problems, fmt.Sprintf("%s.port %d is also used by %s", configKey(s), sp.Port, owner))
+		}
+	}
+
+	envPath := filepath.Join(mynode, ".env")
+	if current, err := os.ReadFile(envPath); err != nil || !bytes.Equal(current, []byte(cfg.envContent())) {
+		problems = append(problems, envPath+" is out of date, run 'node config migrate --mynode "+mynode+"' to regenerate it")
+	}
+
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			log.Errorf("❌ %s", problem)
+		}
+		return nodeConfigError(mynode, problems)
+	}
+	log.Infof("✅ %s is valid", filepath.Join(mynode, nodeConfigFile))
+	return nil
+}
+
+func nodeConfigMigrateCmd() *cobra.Command {
+	var mynode string
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Create node.yaml from the node .env, or upgrade it to the current schema",
+		Long: `Nodes set up by older versions only have a .env. migrate reads it together
+with IMAGE_NAME from the global .env, reports keys the two files disagree on,
+and writes node.yaml. For nodes that already have a node.yaml it upgrades the
+schema and regenerates the .env.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nodeConfigMigrateLogic(mynode)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	return cmd
+}
+
+func nodeConfigMigrateLogic(mynode string) error {
+	cfg, err := readNodeConfig(mynode)
+	if errors.Is(err, os.ErrNotExist) {
+		var notes []string
+		if cfg, notes, err = legacyNodeConfig(mynode); err != nil {
+			return err
+		}
+		for _, note := range notes {
+			log.Warnf("⚠️ %s", note)
+		}
+		log.Infof("🔄 Migrating %s to %s", filepath.Join(mynode, ".env"), nodeConfigFile)
+	} else if err != nil {
+		return err
+	}
+	cfg.applyDefaults()
+	if problems := cfg.Validate(); len(problems) > 0 {
+		return nodeConfigError(mynode, problems)
+	}
+	return saveNodeConfig(mynode, cfg)
+}
@@ -0,0 +1,508 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+
+	"github.com/charmbracelet/log"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+const nodesFileName = "nodes.json"
+
+const (
+	runtimeDocker = "docker"
+	runtimeNative = "native"
+)
+
+// NodeEntry is one node known to the registry.
+type NodeEntry struct {
+	Name      string            `json:"name" yaml:"name"`
+	Home      string            `json:"home" yaml:"home"`
+	KeyName   string            `json:"key_name" yaml:"key_name"`
+	Network   string            `json:"network,omitempty" yaml:"network,omitempty"`
+	Runtime   string            `json:"runtime" yaml:"runtime"`
+	Container string            `json:"container,omitempty" yaml:"container,omitempty"`
+	Ports     map[string]string `json:"ports,omitempty" yaml:"ports,omitempty"`
+	Remote    *RemoteHost       `json:"remote,omitempty" yaml:"remote,omitempty"`
+	// BinaryVersion pins the managed ethermintd the node runs.
+	BinaryVersion string `json:"binary_version,omitempty" yaml:"binary_version,omitempty"`
+}
+
+// Workspace is the directory holding the node home, the ethermintd binary
+// and the global .env; commands run from there.
+func (n NodeEntry) Workspace() string {
+	return filepath.Dir(n.Home)
+}
+
+// NodeRegistry is the content of ~/.mrmintchain/nodes.json.
+type NodeRegistry struct {
+	Current string      `json:"current,omitempty" yaml:"current,omitempty"`
+	Nodes   []NodeEntry `json:"nodes" yaml:"nodes"`
+	// BinaryVersion is the managed ethermintd of nodes without a pin.
+	BinaryVersion string `json:"binary_version,omitempty" yaml:"binary_version,omitempty"`
+}
+
+func (r NodeRegistry) renderTable(w io.Writer) {
+	fmt.Fprintln(w, "\tNAME\tHOME\tKEY\tNETWORK\tRUNTIME\tCONTAINER\tRPC PORT\tHOST")
+	for _, n := range r.Nodes {
+		marker := ""
+		if n.Name == r.Current {
+			marker = "*"
+		}
+		host := "local"
+		if n.Remote != nil {
+			host = n.Remote.User + "@" + n.Remote.address() + ":" + n.Remote.Dir
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", marker, n.Name, n.Home, n.KeyName, n.Network, n.Runtime, n.Container, n.Ports["RPC_PORT"], host)
+	}
+}
+
+// Find returns the entry named name.
+func (r NodeRegistry) Find(name string) (NodeEntry, bool) {
+	for _, n := range r.Nodes {
+		if n.Name == name {
+			return n, true
+		}
+	}
+	return NodeEntry{}, false
+}
+
+// Put adds or replaces the entry with the same name.
+func (r *NodeRegistry) Put(entry NodeEntry) {
+	for i, n := range r.Nodes {
+		if n.Name == entry.Name {
+			r.Nodes[i] = entry
+			return
+		}
+	}
+	r.Nodes = append(r.Nodes, entry)
+	sort.Slice(r.Nodes, func(i, j int) bool { return r.Nodes[i].Name < r.Nodes[j].Name })
+}
+
+// Remove deletes the entry named name and reports whether it existed.
+func (r *NodeRegistry) Remove(name string) bool {
+	for i, n := range r.Nodes {
+		if n.Name == name {
+			r.Nodes = append(r.Nodes[:i], r.Nodes[i+1:]...)
+			if r.Current == name {
+				r.Current = ""
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func getNodesFilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("could not get user home directory: %w", err)
+	}
+	return filepath.Join(homeDir, configDirName, nodesFileName), nil
+}
+
+// loadNodeRegistry reads the registry; a missing file is an empty registry.
+func loadNodeRegistry() (NodeRegistry, error) {
+	var registry NodeRegistry
+	path, err := getNodesFilePath()
+	if err != nil {
+		return registry, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return registry, nil
+	}
+	if err != nil {
+		return registry, fmt.Errorf("failed to read node registry %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return registry, fmt.Errorf("failed to parse node registry %s: %w", path, err)
+	}
+	return registry, nil
+}
+
+func saveNodeRegistry(registry NodeRegistry) error {
+	path, err := getNodesFilePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory %s: %w", filepath.Dir(path), err)
+	}
+	data, err := json.MarshalIndent(registry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal node registry: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write node registry %s: %w", path, err)
+	}
+	return nil
+}
+
+// readNodePorts returns the ports recorded in the node's .env, without
+// touching the process environment.
+func readNodePorts(home string) map[string]string {
+	env, err := godotenv.Read(filepath.Join(home, ".env"))
+	if err != nil {
+		return nil
+	}
+	ports := map[string]string{}
+	for _, key := range nodePortKeys {
+		if v := env[key]; v != "" {
+			ports[key] = v
+		}
+	}
+	return ports
+}
+
+// registerNode records a node created in the current directory, keeping any
+// settings already in the registry. Failures are only logged: the registry
+// is a convenience and must not break setup.
+func registerNode(mynode string) {
+	registry, err := loadNodeRegistry()
+	if err != nil {
+		log.Warnf("⚠️ Could not update node registry: %v", err)
+		return
+	}
+	home, err := filepath.Abs(mynode)
+	if err != nil {
+		log.Warnf("⚠️ Could not update node registry: %v", err)
+		return
+	}
+
+	entry, ok := registry.Find(filepath.Base(home))
+	if !ok {
+		entry = NodeEntry{
+			Name:      filepath.Base(home),
+			KeyName:   mynode,
+			Runtime:   runtimeDocker,
+			Container: mynode,
+		}
+	}
+	entry.Home = home
+	if configCliParams.ChaindId != "" {
+		entry.Network = configCliParams.ChaindId
+	}
+	if ports := readNodePorts(home); len(ports) > 0 {
+		entry.Ports = ports
+	}
+	registry.Put(entry)
+	if registry.Current == "" {
+		registry.Current = entry.Name
+	}
+	if err := saveNodeRegistry(registry); err != nil {
+		log.Warnf("⚠️ Could not update node registry: %v", err)
+	}
+}
+
+// resolveNode turns the --mynode value into a registry entry. An empty value
+// selects the current node. Names that are not registered are returned as a
+// node in the current directory so unregistered setups keep working.
+func resolveNode(mynode string) (NodeEntry, bool, error) {
+	registry, err := loadNodeRegistry()
+	if err != nil {
+		return NodeEntry{}, false, err
+	}
+	if mynode == "" {
+		if registry.Current == "" {
+			return NodeEntry{}, false, fmt.Errorf("--mynode is required (or select a node with 'nodes use <name>')")
+		}
+		mynode = registry.Current
+	}
+	if entry, ok := registry.Find(mynode); ok {
+		return entry, true, nil
+	}
+	return NodeEntry{Name: mynode, KeyName: mynode, Container: mynode, Runtime: runtimeDocker}, false, nil
+}
+
+// nodeEntry returns the registry entry of the node in directory mynode of
+// the workspace, or the defaults of an unregistered node.
+func nodeEntry(mynode string) NodeEntry {
+	if activeNode.Name != "" && (activeNode.Name == mynode || activeNode.Home != "" && filepath.Base(activeNode.Home) == mynode) {
+		return activeNode
+	}
+	if home, err := filepath.Abs(mynode); err == nil {
+		if registry, err := loadNodeRegistry(); err == nil {
+			for _, n := range registry.Nodes {
+				if n.Home == home {
+					return n
+				}
+			}
+		}
+	}
+	return NodeEntry{Name: mynode, KeyName: mynode, Container: mynode, Runtime: runtimeDocker}
+}
+
+// nodeKeyName is the name of the node's key in its keyring.
+func nodeKeyName(mynode string) string {
+	if key := nodeEntry(mynode).KeyName; key != "" {
+		return key
+	}
+	return mynode
+}
+
+// nodeContainer is the name of the node's Docker container.
+func nodeContainer(mynode string) string {
+	if container := nodeEntry(mynode).Container; container != "" {
+		return container
+	}
+	return mynode
+}
+
+// nodeRuntime is how the node runs, runtimeDocker or runtimeNative.
+func nodeRuntime(mynode string) string {
+	if runtime := nodeEntry(mynode).Runtime; runtime != "" {
+		return runtime
+	}
+	return runtimeDocker
+}
+
+// requireNode replaces MarkFlagRequired("mynode"): the flag may be omitted
+// once a node is selected with 'nodes use'.
+func requireNode(cmd *cobra.Command) {
+	cmd.Flags().SetAnnotation("mynode", nodeRequiredAnnotation, []string{"true"})
+}
+
+const nodeRequiredAnnotation = "mrmintchain_node_required"
+
+// invocationDir is the directory the CLI was started from, set before
+// applyNodeFlag moves into a registered node's workspace.
+var invocationDir string
+
+// invocationPath resolves a relative path given on the command line against
+// the directory the CLI was started from.
+func invocationPath(p string) string {
+	if invocationDir == "" || filepath.IsAbs(p) {
+		return p
+	}
+	return filepath.Join(invocationDir, p)
+}
+
+// optionalInvocationPath is invocationPath for optional flags, keeping ""
+// so the command can pick its default.
+func optionalInvocationPath(p string) string {
+	if p == "" {
+		return ""
+	}
+	return invocationPath(p)
+}
+
+// applyNodeFlag resolves --mynode through the registry before a command runs.
+// For registered nodes the process moves to the node's workspace and the
+// flag is rewritten to the node directory name, so the relative paths used
+// by every command (node home, .env, ./ethermintd) resolve from anywhere.
+func applyNodeFlag(cmd *cobra.Command) error {
+	flag := cmd.Flags().Lookup("mynode")
+	if flag == nil {
+		return nil
+	}
+	_, required := flag.Annotations[nodeRequiredAnnotation]
+	if flag.Value.String() == "" && !required {
+		return nil
+	}
+
+	entry, registered, err := resolveNode(flag.Value.String())
+	if err != nil {
+		return err
+	}
+	activeNode = entry
+	if !registered {
+		return cmd.Flags().Set("mynode", entry.Name)
+	}
+
+	invocationDir, _ = os.Getwd()
+	if err := os.Chdir(entry.Workspace()); err != nil {
+		return fmt.Errorf("failed to enter workspace of node %s: %w", entry.Name, err)
+	}
+	if entry.Remote != nil {
+		transport = newSSHTransport(*entry.Remote)
+		log.Debugf("Using node %s on %s:%s", entry.Name, entry.Remote.Host, entry.Remote.Dir)
+	}
+	log.Debugf("Using node %s from %s", entry.Name, entry.Home)
+	return cmd.Flags().Set("mynode", filepath.Base(entry.Home))
+}
+
+func nodesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "nodes",
+		Short: "Manage the registry of nodes on this workstation",
+		Long: `The node registry (~/.mrmintchain/nodes.json) records where each node lives,
+so commands work from any directory. After 'nodes use <name>' the --mynode
+flag can be omitted.`,
+	}
+	cmd.AddCommand(nodesListCmd(), nodesAddCmd(), nodesRemoveCmd(), nodesUseCmd(), nodesSyncCmd())
+	return cmd
+}
+
+func nodesListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List registered nodes (* marks the selected node)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registry, err := loadNodeRegistry()
+			if err != nil {
+				return err
+			}
+			if registry.Nodes == nil {
+				registry.Nodes = []NodeEntry{}
+			}
+			return render(registry)
+		},
+	}
+}
+
+func nodesAddCmd() *cobra.Command {
+	var entry NodeEntry
+	var home string
+	var remote RemoteHost
+
+	cmd := &cobra.Command{
+		Use:   "add [name]",
+		Short: "Register a node (or update a registered one)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entry.Name = args[0]
+			if remote.Host != "" {
+				entry.Remote = &remote
+			}
+			return nodesAddCmdLogic(entry, home)
+		},
+	}
+	cmd.Flags().StringVar(&home, "home", "", "Node home directory (default: ./<name>)")
+	cmd.Flags().StringVar(&entry.KeyName, "key", "", "Key name of the validator (default: the node directory name)")
+	cmd.Flags().StringVar(&entry.Network, "network", "", "Chain id of the network")
+	cmd.Flags().StringVar(&entry.Runtime, "runtime", runtimeDocker, "How the node runs: docker or native")
+	cmd.Flags().StringVar(&entry.Container, "container", "", "Docker container name (default: the node directory name)")
+	cmd.Flags().StringVar(&remote.Host, "ssh-host", "", "Remote host running the node (commands run over SSH)")
+	cmd.Flags().IntVar(&remote.Port, "ssh-port", 22, "SSH port of the remote host")
+	cmd.Flags().StringVar(&remote.User, "ssh-user", "", "SSH user on the remote host (default: the current user)")
+	cmd.Flags().StringVar(&remote.KeyFile, "ssh-key", "", "SSH private key file (default: use ssh-agent)")
+	cmd.Flags().StringVar(&remote.KnownHosts, "ssh-known-hosts", "", "known_hosts file used to verify the host (default: ~/.ssh/known_hosts)")
+	cmd.Flags().StringVar(&remote.Dir, "remote-dir", "", "Workspace directory on the remote host holding the node home and ethermintd")
+	return cmd
+}
+
+func nodesAddCmdLogic(entry NodeEntry, home string) error {
+	if entry.Runtime != runtimeDocker && entry.Runtime != runtimeNative {
+		return fmt.Errorf("invalid --runtime %q: must be %s or %s", entry.Runtime, runtimeDocker, runtimeNative)
+	}
+	if home == "" {
+		home = entry.Name
+	}
+	abs, err := filepath.Abs(home)
+	if err != nil {
+		return fmt.Errorf("invalid --home: %w", err)
+	}
+	if entry.Remote != nil {
+		if err := completeRemoteHost(entry.Remote); err != nil {
+			return err
+		}
+		// The local home is a copy kept in step with 'nodes sync'.
+		if err := os.MkdirAll(abs, 0700); err != nil {
+			return fmt.Errorf("failed to create local node home %s: %w", abs, err)
+		}
+	} else if !exists(abs) {
+		return fmt.Errorf("node home %s does not exist", abs)
+	}
+	if filepath.Base(abs) != entry.Name {
+		log.Warnf("⚠️ Node name %s differs from its directory name %s; commands address the node by its directory", entry.Name, filepath.Base(abs))
+	}
+	entry.Home = abs
+	if entry.KeyName == "" {
+		entry.KeyName = filepath.Base(abs)
+	}
+	if entry.Runtime == runtimeDocker && entry.Container == "" {
+		entry.Container = filepath.Base(abs)
+	}
+	entry.Ports = readNodePorts(abs)
+
+	registry, err := loadNodeRegistry()
+	if err != nil {
+		return err
+	}
+	registry.Put(entry)
+	if registry.Current == "" {
+		registry.Current = entry.Name
+	}
+	if err := saveNodeRegistry(registry); err != nil {
+		return err
+	}
+	log.Infof("✅ Node %s registered (%s)", entry.Name, entry.Home)
+	return nil
+}
+
+func nodesRemoveCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remove [name]",
+		Short: "Remove a node from the registry (its files are kept)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registry, err := loadNodeRegistry()
+			if err != nil {
+				return err
+			}
+			if !registry.Remove(args[0]) {
+				return fmt.Errorf("node %s is not registered", args[0])
+			}
+			if err := saveNodeRegistry(registry); err != nil {
+				return err
+			}
+			log.Infof("✅ Node %s removed from the registry", args[0])
+			return nil
+		},
+	}
+}
+
+func nodesUseCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "use [name]",
+		Short: "Select the node used when --mynode is omitted",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			registry, err := loadNodeRegistry()
+			if err != nil {
+				return err
+			}
+			if _, ok := registry.Find(args[0]); !ok {
+				return fmt.Errorf("node %s is not registered (see 'nodes add')", args[0])
+			}
+			registry.Current = args[0]
+			if err := saveNodeRegistry(registry); err != nil {
+				return err
+			}
+			log.Infof("✅ Now using node %s", args[0])
+			return nil
+		},
+	}
+}
+
+func completeRemoteHost(remote *RemoteHost) error {
+	if remote.Dir == "" {
+		return fmt.Errorf("--remote-dir is required for remote nodes")
+	}
+	if remote.User == "" {
+		u, err := user.Current()
+		if err != nil {
+			return fmt.Errorf("--ssh-user is required: %w", err)
+		}
+		remote.User = u.Username
+	}
+	for _, p := range []*string{&remote.KeyFile, &remote.KnownHosts} {
+		if *p == "" {
+			continue
+		}
+		abs, err := filepath.Abs(*p)
+		if err != nil {
+			return err
+		}
+		*p = abs
+	}
+	return nil
+}
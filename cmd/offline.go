@@ -0,0 +1,506 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+	"github.com/yourname/ethermint-validator-cli/address"
+)
+
+// TxOptions holds the flags shared by every transaction command.
+type TxOptions struct {
+	GenerateOnly bool
+	OutputFile   string
+	Signer       string
+	AsGrantee    string
+	Granter      string
+	FeeGranter   string
+}
+
+// OfflineTxFile is the transfer file moved between the online node and the
+// offline signing machine. The online side fills in the account number and
+// sequence so the offline side can sign without any network access.
+type OfflineTxFile struct {
+	Kind          string          `json:"kind"`
+	ChainID       string          `json:"chain_id"`
+	KeyName       string          `json:"key_name"`
+	Signer        string          `json:"signer"`
+	AccountNumber string          `json:"account_number"`
+	Sequence      string          `json:"sequence"`
+	Signed        bool            `json:"signed"`
+	CreatedAt     string          `json:"created_at"`
+	Tx            json.RawMessage `json:"tx"`
+}
+
+type baseAccount struct {
+	Address       string `json:"address"`
+	AccountNumber string `json:"account_number"`
+	Sequence      string `json:"sequence"`
+}
+
+// accountResponse covers both the plain BaseAccount layout and the
+// EthAccount layout (which nests the base account) returned by ethermintd.
+type accountResponse struct {
+	baseAccount
+	BaseAccount *baseAccount `json:"base_account"`
+	Account     *struct {
+		baseAccount
+		BaseAccount *baseAccount `json:"base_account"`
+	} `json:"account"`
+}
+
+func addTxFlags(cmd *cobra.Command, opts *TxOptions) {
+	cmd.Flags().BoolVar(&opts.GenerateOnly, "generate-only", false, "Write an unsigned transaction file for offline signing instead of broadcasting")
+	cmd.Flags().StringVar(&opts.OutputFile, "output-file", "", "Path of the file written by --generate-only (default <mynode>-<tx>-unsigned.json)")
+	cmd.Flags().StringVar(&opts.Signer, "signer", "", "Key name or ethm1 address that will sign the generated transaction instead of the node key, e.g. a multisig key (requires --generate-only)")
+	cmd.Flags().StringVar(&opts.FeeGranter, "fee-granter", "", "Address of a sponsor account that pays the fees through a fee allowance")
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		return opts.validate()
+	}
+}
+
+func (o TxOptions) validate() error {
+	if o.Signer != "" && !o.GenerateOnly {
+		return &ConfigError{Msg: "--signer can only be used together with --generate-only"}
+	}
+	if o.Signer != "" && o.AsGrantee != "" {
+		return &ConfigError{Msg: "--signer and --as-grantee cannot be used together"}
+	}
+	if o.AsGrantee != "" && o.Granter == "" {
+		return &ConfigError{Msg: "--as-grantee requires --granter, the ethm1 address of the validator owner key"}
+	}
+	if o.Granter != "" {
+		if o.AsGrantee == "" {
+			return &ConfigError{Msg: "--granter can only be used together with --as-grantee"}
+		}
+		if addr, err := address.Parse(o.Granter); err != nil || addr.Kind != address.KindAccount {
+			return &ConfigError{Msg: fmt.Sprintf("invalid --granter %q: must be an ethm1 address", o.Granter)}
+		}
+	}
+	return validateFeeGranter(o.FeeGranter)
+}
+
+// validateFeeGranter checks that a --fee-granter, when given, is an account
+// address.
+func validateFeeGranter(granter string) error {
+	if granter == "" {
+		return nil
+	}
+	if addr, err := address.Parse(granter); err != nil || addr.Kind != address.KindAccount {
+		return &ConfigError{Msg: fmt.Sprintf("invalid --fee-granter %q: must be an ethm1 address", granter)}
+	}
+	return nil
+}
+
+// apply appends the flags that every broadcast or generated transaction
+// shares to the given tx args.
+func (o TxOptions) apply(args []string) []string {
+	if o.FeeGranter != "" {
+		args = append(args, "--fee-granter", o.FeeGranter)
+	}
+	return args
+}
+
+// replaceFlagValue returns a copy of args with the value following flag
+// replaced, or the flag appended when it is not present.
+func replaceFlagValue(args []string, flag, value string) []string {
+	out := append([]string{}, args...)
+	for i := 0; i < len(out)-1; i++ {
+		if out[i] == flag {
+			out[i+1] = value
+			return out
+		}
+	}
+	return append(out, flag, value)
+}
+
+// signerAccount returns the --signer of a generated transaction when it is
+// an account address rather than a key name. Such a signer needs no key on
+// this machine.
+func (o TxOptions) signerAccount() (string, bool) {
+	if !o.GenerateOnly || o.Signer == "" {
+		return "", false
+	}
+	addr, err := address.Parse(o.Signer)
+	if err != nil || addr.Kind != address.KindAccount {
+		return "", false
+	}
+	return addr.Account(), true
+}
+
+// keyAddress returns the ethm1... address of the node key.
+func keyAddress(mynode string) (string, error) {
+	return keyringAddress(mynode, nodeKeyName(mynode))
+}
+
+// keyringAddress returns the ethm1 address of a key in the node's keyring.
+func keyringAddress(mynode, keyName string) (string, error) {
+	output, err := nodeCommand(Mrmintd, "keys", "show", keyName, "-a", "--home", mynode, "--keyring-backend", "test").Output()
+	addr := strings.TrimSpace(string(output))
+	if err != nil || addr == "" {
+		return "", &RuntimeError{
+			Msg:  fmt.Sprintf("failed to get the address of key '%s'", keyName),
+			Err:  err,
+			Hint: "for --generate-only on a machine without the key, pass its ethm1 address with --signer",
+		}
+	}
+	return addr, nil
+}
+
+// txSignerAddress returns the account a node transaction is sent from: the
+// --signer of a generated transaction, or else the node key.
+func txSignerAddress(mynode string, txOpts TxOptions) (string, error) {
+	if account, ok := txOpts.signerAccount(); ok {
+		return account, nil
+	}
+	keyName := nodeKeyName(mynode)
+	if txOpts.GenerateOnly && txOpts.Signer != "" {
+		keyName = txOpts.Signer
+	}
+	return keyringAddress(mynode, keyName)
+}
+
+// txOperatorAddress returns the ethmvaloper1... form of txSignerAddress.
+func txOperatorAddress(mynode string, txOpts TxOptions) (string, error) {
+	account, err := txSignerAddress(mynode, txOpts)
+	if err != nil {
+		return "", err
+	}
+	addr, err := address.Parse(account)
+	if err != nil {
+		return "", &RuntimeError{Msg: fmt.Sprintf("invalid account address %q", account), Err: err}
+	}
+	return addr.Valoper(), nil
+}
+
+// queryAccountNumberAndSequence fetches the on-chain account number and
+// sequence for an address from the given node.
+func queryAccountNumberAndSequence(address, node string) (string, string, error) {
+	output, err := runCmdCaptureOutput(Mrmintd, "query", "auth", "account", address, "--node", node, "--output", "json")
+	if err != nil {
+		return "", "", &ChainQueryError{Query: "query account " + address, Err: fmt.Errorf("%w: %s", err, strings.TrimSpace(output))}
+	}
+
+	raw, err := extractJSON(output)
+	if err != nil {
+		return "", "", &ChainQueryError{Query: "parse account " + address, Err: err}
+	}
+
+	var resp accountResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return "", "", &ChainQueryError{Query: "parse account " + address, Err: err}
+	}
+
+	acc := resp.baseAccount
+	switch {
+	case resp.BaseAccount != nil:
+		acc = *resp.BaseAccount
+	case resp.Account != nil && resp.Account.BaseAccount != nil:
+		acc = *resp.Account.BaseAccount
+	case resp.Account != nil:
+		acc = resp.Account.baseAccount
+	}
+
+	if acc.AccountNumber == "" {
+		acc.AccountNumber = "0"
+	}
+	if acc.Sequence == "" {
+		acc.Sequence = "0"
+	}
+	return acc.AccountNumber, acc.Sequence, nil
+}
+
+// extractJSON returns the first JSON object found in command output. The
+// ethermintd tx commands print gas estimates on stderr, which ends up mixed
+// into the captured output.
+func extractJSON(output string) (json.RawMessage, error) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "{") && json.Valid([]byte(line)) {
+			return json.RawMessage(line), nil
+		}
+	}
+
+	trimmed := strings.TrimSpace(output)
+	if start := strings.Index(trimmed, "{"); start >= 0 && json.Valid([]byte(trimmed[start:])) {
+		return json.RawMessage(trimmed[start:]), nil
+	}
+	return nil, fmt.Errorf("no JSON found in command output: %s", output)
+}
+
+// generateUnsignedTx runs a tx command with --generate-only and writes the
+// result, together with the signer's account number and sequence, to a
+// transfer file for the offline machine.
+func generateUnsignedTx(mynode string, opts TxOptions, kind, rpcNode string, command string, args ...string) error {
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
+
+	keyName := nodeKeyName(mynode)
+	if opts.Signer != "" {
+		keyName = opts.Signer
+	}
+	signer, err := txSignerAddress(mynode, opts)
+	if err != nil {
+		return err
+	}
+	if opts.Signer != "" {
+		from := opts.Signer
+		// The offline side picks the key of an address with 'tx sign --key'.
+		if account, ok := opts.signerAccount(); ok {
+			from, keyName = account, ""
+		}
+		args = replaceFlagValue(args, "--from", from)
+	}
+
+	output, err := runCmdCaptureOutput(command, append(opts.apply(args), "--generate-only")...)
+	if err != nil {
+		log.Errorf("❌ Failed to generate unsigned %s transaction: %s\nOutput: %s", kind, err, output)
+		return &RuntimeError{Msg: "failed to generate the unsigned " + kind + " transaction", Err: err}
+	}
+	unsignedTx, err := extractJSON(output)
+	if err != nil {
+		return &RuntimeError{Msg: "failed to read the unsigned " + kind + " transaction", Err: err}
+	}
+
+	accountNumber, sequence, err := queryAccountNumberAndSequence(signer, rpcNode)
+	if err != nil {
+		return &ChainQueryError{
+			Query: "fetch the account number and sequence of " + signer,
+			Err:   err,
+			Hint:  "the signer account must exist on chain; fund it before generating transactions",
+		}
+	}
+
+	txFile := OfflineTxFile{
+		Kind:          kind,
+		ChainID:       configCliParams.ChaindId,
+		KeyName:       keyName,
+		Signer:        signer,
+		AccountNumber: accountNumber,
+		Sequence:      sequence,
+		CreatedAt:     time.Now().Format(time.RFC3339),
+		Tx:            unsignedTx,
+	}
+
+	outputFile := opts.OutputFile
+	if outputFile == "" {
+		outputFile = fmt.Sprintf("%s-%s-unsigned.json", filepath.Base(mynode), kind)
+	}
+	outputFile = invocationPath(outputFile)
+	if err := writeOfflineTxFile(outputFile, txFile); err != nil {
+		return &RuntimeError{Msg: "failed to save the unsigned transaction", Err: err}
+	}
+
+	log.Infof("✅ Unsigned %s transaction written to %s", kind, outputFile)
+	log.Infof("Account number: %s, sequence: %s", accountNumber, sequence)
+	if opts.Signer != "" {
+		if keyName == "" {
+			log.Infof("===> Sign it with the key of %s: mrmintchain tx sign --mynode <home> --key <key> %s", signer, outputFile)
+		} else {
+			log.Infof("===> Sign it with the '%s' key: mrmintchain tx sign --mynode <home> %s", keyName, outputFile)
+		}
+		log.Infof("===> For a multisig key each member signs with: mrmintchain multisig sign --mynode <home> --member <key> %s", outputFile)
+		return nil
+	}
+	log.Infof("===> Copy it to the offline machine and run: mrmintchain tx sign --mynode %s %s", mynode, outputFile)
+	return nil
+}
+
+func readOfflineTxFile(path string) (OfflineTxFile, error) {
+	var txFile OfflineTxFile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return txFile, &ConfigError{Msg: "failed to read transaction file " + path, Err: err}
+	}
+	if err := json.Unmarshal(data, &txFile); err != nil {
+		return txFile, &ConfigError{Msg: "failed to parse transaction file " + path, Err: err}
+	}
+	if len(txFile.Tx) == 0 {
+		return txFile, &ConfigError{Msg: "transaction file " + path + " does not contain a transaction"}
+	}
+	return txFile, nil
+}
+
+func writeOfflineTxFile(path string, txFile OfflineTxFile) error {
+	data, err := json.MarshalIndent(txFile, "", "  ")
+	if err != nil {
+		return &RuntimeError{Msg: "failed to marshal transaction file", Err: err}
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return &RuntimeError{Msg: "failed to write transaction file " + path, Err: err}
+	}
+	return nil
+}
+
+// writeTempTx writes a raw transaction to a temporary file where the node's
+// commands run, so it can be passed to ethermintd, which only accepts
+// transactions from files. Remove it with transport.Remove.
+func writeTempTx(tx json.RawMessage) (string, error) {
+	path, err := transport.WriteTemp("mrmintchain-tx-*.json", tx)
+	if err != nil {
+		return "", &RuntimeError{Msg: "failed to write temporary transaction file", Err: err}
+	}
+	return path, nil
+}
+
+func txCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tx",
+		Short: "Offline signing workflow (sign and broadcast generated transactions)",
+		Long: `Transactions created with --generate-only are written to a transfer file that
+carries the signer's account number and sequence. Sign the file on the offline
+machine with 'tx sign', then broadcast it from the online node with 'tx broadcast'.`,
+	}
+	cmd.AddCommand(txSignCmd(), txBroadcastCmd())
+	return cmd
+}
+
+func txSignCmd() *cobra.Command {
+	var mynode string
+	var keyName string
+	var outputFile string
+
+	cmd := &cobra.Command{
+		Use:   "sign [unsigned_tx_file]",
+		Short: "Sign a generated transaction file on the offline machine",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return txSignCmdLogic(mynode, keyName, invocationPath(args[0]), optionalInvocationPath(outputFile))
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (keyring home)")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&keyName, "key", "", "Key used for signing (default: the key recorded in the transaction file)")
+	cmd.Flags().StringVar(&outputFile, "output-file", "", "Path of the signed transaction file (default <file>-signed.json)")
+	return cmd
+}
+
+func txSignCmdLogic(mynode, keyName, path, outputFile string) error {
+	txFile, err := readOfflineTxFile(path)
+	if err != nil {
+		return err
+	}
+	if txFile.Signed {
+		return &ConfigError{Msg: "transaction file " + path + " is already signed"}
+	}
+	if keyName == "" {
+		keyName = txFile.KeyName
+	}
+	if keyName == "" {
+		keyName = nodeKeyName(mynode)
+	}
+
+	unsignedPath, err := writeTempTx(txFile.Tx)
+	if err != nil {
+		return err
+	}
+	defer transport.Remove(unsignedPath)
+
+	signedPath := unsignedPath + ".signed"
+	defer transport.Remove(signedPath)
+
+	log.Infof("Signing %s transaction for %s (account number %s, sequence %s)", txFile.Kind, txFile.Signer, txFile.AccountNumber, txFile.Sequence)
+
+	output, err := runCmdCaptureOutput(Mrmintd,
+		"tx", "sign", unsignedPath,
+		"--from", keyName,
+		"--home", mynode,
+		"--keyring-backend", "test",
+		"--chain-id", txFile.ChainID,
+		"--offline",
+		"--account-number", txFile.AccountNumber,
+		"--sequence", txFile.Sequence,
+		"--output-document", signedPath,
+	)
+	if err != nil {
+		log.Errorf("❌ Failed to sign transaction: %s\nOutput: %s", err, output)
+		return &RuntimeError{Msg: "failed to sign transaction " + path, Err: err}
+	}
+
+	signedTx, err := transport.ReadFile(signedPath)
+	if err != nil {
+		return &RuntimeError{Msg: "failed to read signed transaction", Err: err}
+	}
+	txFile.Tx = json.RawMessage(strings.TrimSpace(string(signedTx)))
+	txFile.Signed = true
+
+	if outputFile == "" {
+		outputFile = strings.TrimSuffix(strings.TrimSuffix(path, ".json"), "-unsigned") + "-signed.json"
+	}
+	if err := writeOfflineTxFile(outputFile, txFile); err != nil {
+		return err
+	}
+
+	log.Infof("✅ Signed transaction written to %s", outputFile)
+	log.Infof("===> Copy it to the online node and run: mrmintchain tx broadcast --mynode <node> %s", outputFile)
+	return nil
+}
+
+func txBroadcastCmd() *cobra.Command {
+	var mynode string
+
+	cmd := &cobra.Command{
+		Use:   "broadcast [signed_tx_file]",
+		Short: "Broadcast a signed transaction file from the online node",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return txBroadcastCmdLogic(mynode, invocationPath(args[0]))
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name (directory where .env is located)")
+	requireNode(cmd)
+	return cmd
+}
+
+func txBroadcastCmdLogic(mynode, path string) error {
+	txFile, err := readOfflineTxFile(path)
+	if err != nil {
+		return err
+	}
+	if !txFile.Signed {
+		return &ConfigError{Msg: "transaction file " + path + " is not signed yet", Hint: "run 'mrmintchain tx sign' on the offline machine first"}
+	}
+
+	err = godotenv.Load(filepath.Join(mynode, ".env"))
+	if err != nil {
+		return envFileError(filepath.Join(mynode, ".env"), err)
+	}
+	rpcPort, err := requireEnv("RPC_PORT")
+	if err != nil {
+		return err
+	}
+	rpcNode := "tcp://localhost:" + rpcPort
+
+	// A transaction signed for an old sequence will be rejected; warn early.
+	if _, sequence, err := queryAccountNumberAndSequence(txFile.Signer, rpcNode); err == nil && sequence != txFile.Sequence {
+		log.Warnf("⚠️ Account sequence changed since the transaction was generated (file: %s, chain: %s). The broadcast will likely fail.", txFile.Sequence, sequence)
+	}
+
+	signedPath, err := writeTempTx(txFile.Tx)
+	if err != nil {
+		return err
+	}
+	defer transport.Remove(signedPath)
+
+	log.Infof("Broadcasting signed %s transaction to %s", txFile.Kind, rpcNode)
+
+	output, err := runCmdCaptureOutput(Mrmintd, "tx", "broadcast", signedPath, "--node", rpcNode)
+	if err != nil {
+		log.Errorf("❌ Failed to broadcast transaction: %s\nOutput: %s", err, output)
+		return txError("broadcast", output, err)
+	}
+
+	log.Infof("✅ Transaction broadcast successfully! Transaction output:\n%s", output)
+	return renderTxOutput("broadcast", output)
+}
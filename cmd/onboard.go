@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/joho/godotenv"
+	"github.com/mdp/qrterminal"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// onboardOptions tune the waiting steps of the onboarding.
+type onboardOptions struct {
+	SyncTimeout    time.Duration
+	DepositTimeout time.Duration
+	// VerifyTimeout bounds the wait for the new validator to show up as bonded.
+	VerifyTimeout time.Duration
+	PollInterval  time.Duration
+	// FeeGranter pays the fees of the create-validator transaction.
+	FeeGranter string
+}
+
+func onboardCmd() *cobra.Command {
+	var mynode string
+	var opts onboardOptions
+
+	cmd := &cobra.Command{
+		Use:   "onboard",
+		Short: "Run the whole validator onboarding, from init to a bonded validator",
+		Long: `Runs every onboarding step in order: init, key, ports, start, sync, register,
+fund, stake and verify. It waits for the node to catch up with the boot node,
+registers the validator with the platform, waits for the staking deposit,
+submits create-validator and confirms the validator is bonded.
+
+Progress is saved in <mynode>/` + onboardingStateFile + `; running onboard again
+resumes from the first step that did not complete.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.PollInterval <= 0 {
+				return &usageError{Err: fmt.Errorf("--poll-interval must be positive")}
+			}
+			if opts.VerifyTimeout <= 0 {
+				return &usageError{Err: fmt.Errorf("--verify-timeout must be positive")}
+			}
+			if err := validateFeeGranter(opts.FeeGranter); err != nil {
+				return err
+			}
+			if err := runOnboarding(mynode, stepVerify, opts); err != nil {
+				return err
+			}
+			state, err := loadOnboardingState(mynode)
+			if err != nil {
+				return err
+			}
+			log.Info("🎉 Onboarding completed. Your validator is bonded.")
+			return render(onboardingStatus(mynode, state))
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	cmd.Flags().DurationVar(&opts.SyncTimeout, "sync-timeout", 0, "Give up waiting for the node to sync after this long (0 waits forever)")
+	cmd.Flags().DurationVar(&opts.DepositTimeout, "deposit-timeout", 0, "Give up waiting for the staking deposit after this long (0 waits forever)")
+	cmd.Flags().DurationVar(&opts.VerifyTimeout, "verify-timeout", 2*time.Minute, "Give up waiting for the validator to be bonded after this long")
+	cmd.Flags().DurationVar(&opts.PollInterval, "poll-interval", 10*time.Second, "How often to check the sync progress and the wallet balance")
+	cmd.Flags().StringVar(&opts.FeeGranter, "fee-granter", "", "Address of a sponsor account that pays the staking fees through a fee allowance")
+	return cmd
+}
+
+// runSyncStep waits until the node is within maxBlocksBehind of the boot
+// node, drawing a progress bar of the local against the boot height.
+func runSyncStep(run *onboardingRun) error {
+	env, err := godotenv.Read(filepath.Join(run.Node, ".env"))
+	if err != nil {
+		return envFileError(filepath.Join(run.Node, ".env"), err)
+	}
+	if env["RPC_PORT"] == "" || env["BOOT_NODE_RPC"] == "" {
+		return &ConfigError{Msg: "RPC_PORT and BOOT_NODE_RPC must be set in the .env of " + run.Node, Hint: "run 'port-set --mynode " + run.Node + "' to regenerate the node .env"}
+	}
+	node := "tcp://localhost:" + env["RPC_PORT"]
+	bootRpc := env["BOOT_NODE_RPC"]
+
+	interactive := term.IsTerminal(int(os.Stderr.Fd()))
+	started := time.Now()
+	for {
+		local, catchingUp, lerr := pollNodeHeight(node)
+		boot, _, berr := pollNodeHeight(bootRpc)
+		switch {
+		case lerr != nil:
+			log.Infof("⏳ Waiting for the node RPC %s to come up...", node)
+		case berr != nil:
+			log.Warnf("⚠️ Could not query boot node %s: %v", bootRpc, berr)
+		default:
+			if interactive {
+				fmt.Fprintf(os.Stderr, "\r⏳ Syncing %s ", syncProgressBar(local, boot, 30))
+			} else {
+				log.Infof("⏳ Syncing: local height %d, boot node height %d", local, boot)
+			}
+			if !catchingUp && boot-local <= maxBlocksBehind {
+				if interactive {
+					fmt.Fprintln(os.Stderr)
+				}
+				log.Infof("✔ The node is synced with the boot node at height %d.", local)
+				return nil
+			}
+		}
+
+		if run.Options.SyncTimeout > 0 && time.Since(started) > run.Options.SyncTimeout {
+			if interactive {
+				fmt.Fprintln(os.Stderr)
+			}
+			return &ChainQueryError{
+				Query: "wait for the node to sync",
+				Err:   fmt.Errorf("still syncing after %s", run.Options.SyncTimeout),
+				Hint:  "run onboard again to keep waiting; 'logs --mynode " + run.Node + "' shows what the node is doing",
+			}
+		}
+		time.Sleep(run.Options.PollInterval)
+	}
+}
+
+// pollNodeHeight is queryNodeStatus without the command echo, for loops
+// that redraw a progress line.
+func pollNodeHeight(node string) (int64, bool, error) {
+	var output syncBuffer
+	if err := transport.Run(nil, &output, &output, Mrmintd, "status", "--node", node); err != nil {
+		return 0, false, err
+	}
+	data, err := extractJSON(output.String())
+	if err != nil {
+		return 0, false, err
+	}
+	var status NodeStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return 0, false, err
+	}
+	height, catchingUp := status.height()
+	return height, catchingUp, nil
+}
+
+func syncProgressBar(local, boot int64, width int) string {
+	fraction := 1.0
+	if boot > 0 {
+		fraction = float64(local) / float64(boot)
+	}
+	fraction = min(max(fraction, 0), 1)
+	filled := int(fraction * float64(width))
+	return fmt.Sprintf("[%s%s] %5.1f%%  %d / %d (%d behind)",
+		strings.Repeat("█", filled), strings.Repeat("░", width-filled), fraction*100, local, boot, max(boot-local, 0))
+}
+
+// runRegisterStep registers the validator with the platform, unless the
+// create-validator command already did.
+func runRegisterStep(run *onboardingRun) error {
+	if exists(filepath.Join(run.Node, ".validator-registered")) {
+		log.Info("✅ The validator is already registered with the platform.")
+		if run.State.Email == "" {
+			email, err := promptEmail()
+			if err != nil {
+				return err
+			}
+			run.State.Email = email
+		}
+		return nil
+	}
+	email, err := registerValidatorLogic(run.Node)
+	if err != nil {
+		return err
+	}
+	run.State.Email = email
+	return nil
+}
+
+// runFundStep shows the deposit QR code and watches the wallet until it
+// holds the minimum stake plus the fee reserve.
+func runFundStep(run *onboardingRun) error {
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
+	ethm1Address, ethAddress, err := validatorWallet(run.Node, TxOptions{})
+	if err != nil {
+		return err
+	}
+	bootRpc := configCliParams.BootNodeRpc
+	if bootRpc == "" {
+		env, _ := godotenv.Read(filepath.Join(run.Node, ".env"))
+		bootRpc = env["BOOT_NODE_RPC"]
+	}
+	md := denomMetadata(bootRpc)
+	required, err := stakeDepositAmount(md, TxOptions{FeeGranter: run.Options.FeeGranter})
+	if err != nil {
+		return err
+	}
+
+	shownQR := false
+	started := time.Now()
+	for {
+		balance, err := walletBalance(ethm1Address, bootRpc, md.Base)
+		if err != nil {
+			log.Warnf("⚠️ Could not query the wallet balance: %v", err)
+		} else if balance.Cmp(required) >= 0 {
+			log.Infof("✅ Deposit received: %s", md.Format(balance))
+			return nil
+		} else {
+			if !shownQR {
+				qrterminal.GenerateHalfBlock(ethAddress, qrterminal.L, os.Stderr)
+				log.Infof("📲 QR Code (scan it securely): Please send %s to your validator wallet %s for validator staking.", md.Format(required), ethAddress)
+				shownQR = true
+			}
+			log.Infof("⏳ Waiting for the deposit: %s of %s", md.Format(balance), md.Format(required))
+		}
+
+		if run.Options.DepositTimeout > 0 && time.Since(started) > run.Options.DepositTimeout {
+			return &ChainQueryError{
+				Query: "wait for the staking deposit",
+				Err:   fmt.Errorf("no sufficient deposit after %s", run.Options.DepositTimeout),
+				Hint:  "send " + md.Format(required) + " to " + ethAddress + " and run onboard again",
+			}
+		}
+		time.Sleep(run.Options.PollInterval)
+	}
+}
+
+// walletBalance returns the balance of one denom held by address.
+func walletBalance(address, node, base string) (*big.Int, error) {
+	balances, err := queryBalances(address, node, "balances")
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range balances {
+		if b.Denom != base {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(b.Amount, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s balance: %s", b.Denom, b.Amount)
+		}
+		return amount, nil
+	}
+	return big.NewInt(0), nil
+}
+
+func runStakeStep(run *onboardingRun) error {
+	if err := loadConfigCliParams(); err != nil {
+		return err
+	}
+	if err := godotenv.Load(filepath.Join(run.Node, ".env")); err != nil {
+		return envFileError(filepath.Join(run.Node, ".env"), err)
+	}
+	if run.State.Email == "" {
+		email, err := promptEmail()
+		if err != nil {
+			return err
+		}
+		run.State.Email = email
+	}
+
+	result, err := createValidatorTx(run.Node, run.State.Email, TxOptions{FeeGranter: run.Options.FeeGranter})
+	if err != nil {
+		return err
+	}
+	if result != nil {
+		run.State.StakeTx = result.Tx.TxHash
+	}
+	return nil
+}
+
+// runVerifyStep waits for the new validator to be bonded.
+func runVerifyStep(run *onboardingRun) error {
+	env, err := godotenv.Read(filepath.Join(run.Node, ".env"))
+	if err != nil {
+		return envFileError(filepath.Join(run.Node, ".env"), err)
+	}
+	node := "tcp://localhost:" + env["RPC_PORT"]
+	valoper, err := validatorOperatorAddress(run.Node)
+	if err != nil {
+		return &ConfigError{Msg: "failed to read the validator operator address", Err: err}
+	}
+
+	deadline := time.Now().Add(run.Options.VerifyTimeout)
+	for {
+		status := "not found"
+		output, err := runCmdCaptureOutput(Mrmintd, "query", "staking", "validator", valoper, "--node", node, "--output", "json")
+		if err == nil {
+			if info, perr := parseValidatorInfo(output); perr == nil {
+				if info.Jailed {
+					return &ChainQueryError{Query: "verify the validator", Err: fmt.Errorf("validator %s is jailed", valoper), Hint: "run 'unjail --mynode " + run.Node + "'"}
+				}
+				if info.Status == "BOND_STATUS_BONDED" {
+					log.Infof("✅ Validator %s is bonded.", valoper)
+					return nil
+				}
+				status = info.Status
+			}
+		}
+		if time.Now().After(deadline) {
+			return &ChainQueryError{
+				Query: "verify the validator",
+				Err:   fmt.Errorf("validator %s is %s", valoper, status),
+				Hint:  "check the stake transaction with 'query-tx --tx-hash " + run.State.StakeTx + "'; the validator may need more stake to enter the active set",
+			}
+		}
+		log.Infof("⏳ Validator status: %s, waiting for it to be bonded...", status)
+		time.Sleep(run.Options.PollInterval)
+	}
+}
+
+func promptEmail() (string, error) {
+	reader := bufio.NewReader(os.Stdin)
+	fmt.Fprint(os.Stderr, "Enter your registered platform email address: ")
+	email, err := reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read email: %w", err)
+	}
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return "", fmt.Errorf("email cannot be empty")
+	}
+	return email, nil
+}
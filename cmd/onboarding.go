@@ -0,0 +1,424 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+// onboardingStateFile is the name of the onboarding progress file inside the
+// node directory.
+const onboardingStateFile = ".onboarding-state.json"
+
+// onboardingStep names one step of the validator onboarding.
+type onboardingStep string
+
+const (
+	stepInit     onboardingStep = "init"
+	stepKey      onboardingStep = "key"
+	stepPorts    onboardingStep = "ports"
+	stepStart    onboardingStep = "start"
+	stepSync     onboardingStep = "sync"
+	stepRegister onboardingStep = "register"
+	stepFund     onboardingStep = "fund"
+	stepStake    onboardingStep = "stake"
+	stepVerify   onboardingStep = "verify"
+)
+
+// onboardingStepDef describes a step: how to run it and how to undo its
+// local side effects. Steps that only happen on chain have no rollback.
+type onboardingStepDef struct {
+	Name        onboardingStep
+	Description string
+	Run         func(run *onboardingRun) error
+	Rollback    func(mynode string) error
+}
+
+// onboardingRun is what a step gets to work with: the node, its persisted
+// state and the options of the command that runs the pipeline.
+type onboardingRun struct {
+	Node    string
+	State   *OnboardingState
+	Options onboardOptions
+}
+
+// onboardingSteps is the onboarding pipeline, in order.
+var onboardingSteps = []onboardingStepDef{
+	{Name: stepInit, Description: "Initialize the node home, genesis and config", Run: nodeStep(initNodeLogic), Rollback: rollbackInit},
+	{Name: stepKey, Description: "Generate the validator key", Run: nodeStep(runKeyStep), Rollback: rollbackKey},
+	{Name: stepPorts, Description: "Choose the ports and write the node .env", Run: nodeStep(portsAndEnvGenerationLogic), Rollback: rollbackPorts},
+	{Name: stepStart, Description: "Start the node container", Run: nodeStep(startNodeCmdLogic), Rollback: rollbackStart},
+	{Name: stepSync, Description: "Wait for the node to catch up with the boot node", Run: runSyncStep},
+	{Name: stepRegister, Description: "Register the validator with the platform", Run: runRegisterStep, Rollback: rollbackRegister},
+	{Name: stepFund, Description: "Wait for the staking deposit", Run: runFundStep},
+	{Name: stepStake, Description: "Submit the create-validator transaction", Run: runStakeStep},
+	{Name: stepVerify, Description: "Confirm the validator is bonded", Run: runVerifyStep},
+}
+
+// nodeStep adapts a command logic function to a step.
+func nodeStep(logic func(mynode string) error) func(run *onboardingRun) error {
+	return func(run *onboardingRun) error {
+		return logic(run.Node)
+	}
+}
+
+func findOnboardingStep(name string) (onboardingStepDef, bool) {
+	for _, step := range onboardingSteps {
+		if string(step.Name) == name {
+			return step, true
+		}
+	}
+	return onboardingStepDef{}, false
+}
+
+func onboardingStepNames() []string {
+	names := make([]string, len(onboardingSteps))
+	for i, step := range onboardingSteps {
+		names[i] = string(step.Name)
+	}
+	return names
+}
+
+// OnboardingState is the persisted progress of a node's onboarding.
+type OnboardingState struct {
+	Node       string                       `json:"node"`
+	Completed  map[onboardingStep]time.Time `json:"completed"`
+	FailedStep onboardingStep               `json:"failed_step,omitempty"`
+	Email      string                       `json:"email,omitempty"`
+	StakeTx    string                       `json:"stake_tx,omitempty"`
+	LastError  string                       `json:"last_error,omitempty"`
+	UpdatedAt  time.Time                    `json:"updated_at"`
+}
+
+func (s *OnboardingState) done(step onboardingStep) bool {
+	_, ok := s.Completed[step]
+	return ok
+}
+
+func onboardingStatePath(mynode string) string {
+	return filepath.Join(mynode, onboardingStateFile)
+}
+
+// loadOnboardingState reads the node's onboarding progress; a node without a
+// state file has not completed any step.
+func loadOnboardingState(mynode string) (*OnboardingState, error) {
+	state := &OnboardingState{Node: mynode, Completed: map[onboardingStep]time.Time{}}
+	data, err := os.ReadFile(onboardingStatePath(mynode))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, &RuntimeError{Msg: "failed to read onboarding state", Err: err}
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, &ConfigError{
+			Msg:  "invalid onboarding state " + onboardingStatePath(mynode),
+			Err:  err,
+			Hint: "fix or delete the file; completed steps are then detected again by running them",
+		}
+	}
+	if state.Completed == nil {
+		state.Completed = map[onboardingStep]time.Time{}
+	}
+	return state, nil
+}
+
+func saveOnboardingState(mynode string, state *OnboardingState) error {
+	state.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal onboarding state: %w", err)
+	}
+	if err := os.MkdirAll(mynode, os.ModePerm); err != nil {
+		return &RuntimeError{Msg: "failed to create node directory", Err: err}
+	}
+	if err := os.WriteFile(onboardingStatePath(mynode), data, 0644); err != nil {
+		return &RuntimeError{Msg: "failed to write onboarding state", Err: err}
+	}
+	return nil
+}
+
+// runOnboarding runs the pipeline up to and including last, skipping the
+// steps that are already completed, and records the progress after every
+// step so a failed run resumes where it stopped.
+func runOnboarding(mynode string, last onboardingStep, opts onboardOptions) error {
+	state, err := loadOnboardingState(mynode)
+	if err != nil {
+		return err
+	}
+	run := &onboardingRun{Node: mynode, State: state, Options: opts}
+
+	for _, step := range onboardingSteps {
+		if state.done(step.Name) {
+			log.Infof("⏭️  %s: already completed", step.Name)
+		} else {
+			log.Infof("▶️  %s: %s", step.Name, step.Description)
+			if err := step.Run(run); err != nil {
+				state.FailedStep = step.Name
+				state.LastError = err.Error()
+				if serr := saveOnboardingState(mynode, state); serr != nil {
+					log.Warnf("⚠️ Could not record the onboarding failure: %v", serr)
+				}
+				return fmt.Errorf("onboarding step %s failed: %w", step.Name, err)
+			}
+			state.Completed[step.Name] = time.Now().UTC()
+			state.FailedStep = ""
+			state.LastError = ""
+			if err := saveOnboardingState(mynode, state); err != nil {
+				return err
+			}
+			log.Infof("✅ %s: done", step.Name)
+		}
+		if step.Name == last {
+			return nil
+		}
+	}
+	return nil
+}
+
+// runKeyStep generates the validator key and shows its addresses.
+func runKeyStep(mynode string) error {
+	if err := addKeyCmdLogic(mynode); err != nil {
+		return err
+	}
+	return addGenesisAccountLogic(mynode)
+}
+
+// rollbackInit removes the downloaded genesis so that init runs again
+// without asking to delete the node directory.
+func rollbackInit(mynode string) error {
+	err := os.Remove(filepath.Join(mynode, "config", "genesis.json"))
+	if err != nil && !os.IsNotExist(err) {
+		return &RuntimeError{Msg: "failed to remove genesis.json", Err: err}
+	}
+	return nil
+}
+
+// rollbackKey deletes the validator key from the keyring after asking, since
+// a key that already holds funds cannot be recovered without its mnemonic.
+func rollbackKey(mynode string) error {
+	keyName := nodeKeyName(mynode)
+	ok, err := yesNo(fmt.Sprintf("Delete key '%s' from the keyring? Make sure you saved its mnemonic. ", keyName))
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &UserAbort{Msg: "key rollback cancelled"}
+	}
+	if output, err := runCmdCaptureOutput(Mrmintd, "keys", "delete", keyName, "--yes", "--keyring-backend", "test", "--home", mynode); err != nil {
+		log.Errorf("keys delete command failed: %s", output)
+		return &RuntimeError{Msg: "failed to delete key " + keyName, Err: err}
+	}
+	return nil
+}
+
+func rollbackPorts(mynode string) error {
+	err := os.Remove(filepath.Join(mynode, ".env"))
+	if err != nil && !os.IsNotExist(err) {
+		return &RuntimeError{Msg: "failed to remove the node .env", Err: err}
+	}
+	return nil
+}
+
+func rollbackStart(mynode string) error {
+	if err := removeNode(mynode); err != nil {
+		return &RuntimeError{Msg: "failed to remove node " + mynode, Err: err}
+	}
+	return nil
+}
+
+func rollbackRegister(mynode string) error {
+	err := os.Remove(filepath.Join(mynode, ".validator-registered"))
+	if err != nil && !os.IsNotExist(err) {
+		return &RuntimeError{Msg: "failed to remove the registration receipt", Err: err}
+	}
+	return nil
+}
+
+// OnboardingStepStatus is one row of the setup status command.
+type OnboardingStepStatus struct {
+	Step        string     `json:"step" yaml:"step"`
+	Description string     `json:"description" yaml:"description"`
+	Status      string     `json:"status" yaml:"status"`
+	CompletedAt *time.Time `json:"completed_at,omitempty" yaml:"completed_at,omitempty"`
+	Error       string     `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// OnboardingStatus is the result of the setup status command.
+type OnboardingStatus struct {
+	Node     string                 `json:"node" yaml:"node"`
+	NextStep string                 `json:"next_step,omitempty" yaml:"next_step,omitempty"`
+	Steps    []OnboardingStepStatus `json:"steps" yaml:"steps"`
+}
+
+func (s OnboardingStatus) renderTable(w io.Writer) {
+	fmt.Fprintln(w, "STEP\tSTATUS\tCOMPLETED\tDESCRIPTION")
+	for _, step := range s.Steps {
+		completed := "-"
+		if step.CompletedAt != nil {
+			completed = step.CompletedAt.Local().Format(time.DateTime)
+		}
+		status := step.Status
+		switch step.Status {
+		case "done":
+			status = "✅ done"
+		case "failed":
+			status = "❌ failed: " + step.Error
+		case "next":
+			status = "▶️  next"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", step.Step, status, completed, step.Description)
+	}
+}
+
+func onboardingStatus(mynode string, state *OnboardingState) OnboardingStatus {
+	status := OnboardingStatus{Node: mynode}
+	for _, step := range onboardingSteps {
+		row := OnboardingStepStatus{Step: string(step.Name), Description: step.Description, Status: "pending"}
+		if at, ok := state.Completed[step.Name]; ok {
+			row.Status = "done"
+			row.CompletedAt = &at
+		} else if state.FailedStep == step.Name {
+			row.Status = "failed"
+			row.Error = state.LastError
+		} else if status.NextStep == "" {
+			row.Status = "next"
+		}
+		if row.Status != "done" && status.NextStep == "" {
+			status.NextStep = string(step.Name)
+		}
+		status.Steps = append(status.Steps, row)
+	}
+	return status
+}
+
+func setupCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "setup",
+		Short: "Inspect or roll back the onboarding progress of a node",
+		Long: `The onboarding progress of a node is kept in <mynode>/` + onboardingStateFile + `.
+auto-setup resumes from the first step that is not completed.`,
+	}
+	cmd.AddCommand(setupStatusCmd(), setupResetCmd())
+	return cmd
+}
+
+func setupStatusCmd() *cobra.Command {
+	var mynode string
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the onboarding progress of a node",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			state, err := loadOnboardingState(mynode)
+			if err != nil {
+				return err
+			}
+			return render(onboardingStatus(mynode, state))
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	return cmd
+}
+
+func setupResetCmd() *cobra.Command {
+	var mynode string
+	var step string
+
+	cmd := &cobra.Command{
+		Use:   "reset",
+		Short: "Roll back one onboarding step so that it runs again",
+		Long: `Marks the step as not completed and undoes its local side effects:
+init removes genesis.json, key deletes the key, ports removes the node .env,
+start removes the container and register removes the registration receipt.
+The on-chain steps only have their progress cleared. Steps are rolled back
+from the last completed one: a step cannot be reset while a later step is
+still completed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setupResetCmdLogic(mynode, step)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&step, "step", "", "Step to roll back: "+strings.Join(onboardingStepNames(), ", "))
+	cmd.MarkFlagRequired("step")
+	cmd.RegisterFlagCompletionFunc("step", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return onboardingStepNames(), cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+func setupResetCmdLogic(mynode, name string) error {
+	step, ok := findOnboardingStep(name)
+	if !ok {
+		return &usageError{Err: fmt.Errorf("unknown step %q: must be one of %s", name, strings.Join(onboardingStepNames(), ", "))}
+	}
+	state, err := loadOnboardingState(mynode)
+	if err != nil {
+		return err
+	}
+	if !state.done(step.Name) && state.FailedStep != step.Name {
+		log.Infof("Step %s is not completed; nothing to roll back.", step.Name)
+		return nil
+	}
+	// Later steps build on this one, e.g. the container of start uses the
+	// ports, so they have to be rolled back first.
+	if later := laterCompletedSteps(state, step.Name); len(later) > 0 {
+		var hints []string
+		for i := len(later) - 1; i >= 0; i-- {
+			hints = append(hints, "setup reset --mynode "+mynode+" --step "+string(later[i]))
+		}
+		return &ConfigError{
+			Msg:  fmt.Sprintf("cannot roll back %s while later steps are completed: %s", step.Name, joinSteps(later)),
+			Hint: "roll them back first, from the last one: " + strings.Join(hints, "; "),
+		}
+	}
+
+	if step.Rollback != nil {
+		if err := step.Rollback(mynode); err != nil {
+			return err
+		}
+	}
+	delete(state.Completed, step.Name)
+	if state.FailedStep == step.Name {
+		state.FailedStep = ""
+		state.LastError = ""
+	}
+	if err := saveOnboardingState(mynode, state); err != nil {
+		return err
+	}
+	log.Infof("↩️  Step %s rolled back; auto-setup will run it again.", step.Name)
+	return nil
+}
+
+// laterCompletedSteps returns the completed steps that come after step in
+// the pipeline, in order.
+func laterCompletedSteps(state *OnboardingState, step onboardingStep) []onboardingStep {
+	var later []onboardingStep
+	after := false
+	for _, s := range onboardingSteps {
+		if after && state.done(s.Name) {
+			later = append(later, s.Name)
+		}
+		if s.Name == step {
+			after = true
+		}
+	}
+	return later
+}
+
+func joinSteps(steps []onboardingStep) string {
+	names := make([]string, len(steps))
+	for i, s := range steps {
+		names[i] = string(s)
+	}
+	return strings.Join(names, ", ")
+}
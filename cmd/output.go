@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// outputFormat is the value of the global --output flag. Only command
+// results are written to stdout; logs, prompts and the commands being run go
+// to stderr so scripts can consume stdout directly.
+var outputFormat = outputTable
+
+// tableRenderer is implemented by results that have a human friendly table
+// form. Results without it fall back to YAML in table mode.
+type tableRenderer interface {
+	renderTable(w io.Writer)
+}
+
+func addOutputFlag(root *cobra.Command) {
+	root.PersistentFlags().StringVar(&outputFormat, "output", outputTable, "Output format: table, json or yaml")
+}
+
+func validateOutputFormat() error {
+	switch outputFormat {
+	case outputTable, outputJSON, outputYAML:
+		return nil
+	default:
+		return fmt.Errorf("invalid --output %q: must be one of table, json, yaml", outputFormat)
+	}
+}
+
+// render writes a command result to stdout in the selected output format.
+func render(result any) error {
+	switch outputFormat {
+	case outputJSON:
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(result)
+	case outputYAML:
+		return renderYAML(result)
+	default:
+		t, ok := result.(tableRenderer)
+		if !ok {
+			return renderYAML(result)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		t.renderTable(w)
+		return w.Flush()
+	}
+}
+
+func renderYAML(result any) error {
+	data, err := yaml.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
+
+// rawJSON renders ethermintd JSON output that has no typed result, keeping
+// the field names of the chain in every format.
+type rawJSON json.RawMessage
+
+func (r rawJSON) MarshalJSON() ([]byte, error) {
+	return json.RawMessage(r), nil
+}
+
+func (r rawJSON) MarshalYAML() (interface{}, error) {
+	var v yaml.MapSlice
+	if err := yaml.Unmarshal(r, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// renderRaw renders the JSON printed by an ethermintd query.
+func renderRaw(output string) error {
+	data, err := extractJSON(output)
+	if err != nil {
+		return err
+	}
+	return render(rawJSON(data))
+}
+
+// TxResult is the outcome of a broadcast or queried transaction.
+type TxResult struct {
+	TxHash    string `json:"txhash" yaml:"txhash"`
+	Height    string `json:"height" yaml:"height"`
+	Code      uint32 `json:"code" yaml:"code"`
+	Codespace string `json:"codespace,omitempty" yaml:"codespace,omitempty"`
+	RawLog    string `json:"raw_log,omitempty" yaml:"raw_log,omitempty"`
+	GasWanted string `json:"gas_wanted" yaml:"gas_wanted"`
+	GasUsed   string `json:"gas_used" yaml:"gas_used"`
+	Timestamp string `json:"timestamp,omitempty" yaml:"timestamp,omitempty"`
+}
+
+func (r TxResult) renderTable(w io.Writer) {
+	fmt.Fprintf(w, "Tx hash\t%s\n", r.TxHash)
+	fmt.Fprintf(w, "Height\t%s\n", r.Height)
+	fmt.Fprintf(w, "Code\t%d\n", r.Code)
+	if r.Code != 0 {
+		fmt.Fprintf(w, "Codespace\t%s\n", r.Codespace)
+		fmt.Fprintf(w, "Raw log\t%s\n", r.RawLog)
+	}
+	fmt.Fprintf(w, "Gas (used/wanted)\t%s/%s\n", r.GasUsed, r.GasWanted)
+	if r.Timestamp != "" {
+		fmt.Fprintf(w, "Timestamp\t%s\n", r.Timestamp)
+	}
+}
+
+// TxResults are the transactions one command sent, such as one grant per
+// message type.
+type TxResults []TxResult
+
+func (r TxResults) renderTable(w io.Writer) {
+	for i, result := range r {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		result.renderTable(w)
+	}
+}
+
+// parseTxResult reads the JSON printed by "ethermintd tx ..." and
+// "ethermintd query tx".
+func parseTxResult(output string) (TxResult, error) {
+	var result TxResult
+	data, err := extractJSON(output)
+	if err != nil {
+		return result, err
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, fmt.Errorf("failed to parse transaction result: %w", err)
+	}
+	return result, nil
+}
+
+// renderTxOutput renders the result of a broadcast transaction. Output that
+// is not a transaction response has already been logged and is skipped.
+func renderTxOutput(action, output string) error {
+	result, err := parseTxResult(output)
+	if err != nil {
+		log.Debugf("Could not parse transaction result: %v", err)
+		return nil
+	}
+	if err := render(result); err != nil {
+		return err
+	}
+	return txError(action, output, nil)
+}
+
+// renderTxOutputs renders the results of several broadcast transactions and
+// fails if any of them was rejected.
+func renderTxOutputs(action string, outputs []string) error {
+	var results TxResults
+	for _, output := range outputs {
+		result, err := parseTxResult(output)
+		if err != nil {
+			log.Debugf("Could not parse transaction result: %v", err)
+			continue
+		}
+		results = append(results, result)
+	}
+	if len(results) > 0 {
+		if err := render(results); err != nil {
+			return err
+		}
+	}
+	for _, output := range outputs {
+		if err := txError(action, output, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,681 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+const (
+	peerRolePersistent = "persistent"
+	peerRoleSeed       = "seed"
+	peerRoleDiscovered = "discovered"
+
+	// peerDialTimeout bounds the TCP dial of peers test and refresh.
+	peerDialTimeout = 5 * time.Second
+)
+
+// PeerInfo is a peer of the node. Reachable, LatencyMs and Score are only
+// set once the peer has been tested.
+type PeerInfo struct {
+	ID        string `json:"id" yaml:"id"`
+	Address   string `json:"address" yaml:"address"`
+	Moniker   string `json:"moniker,omitempty" yaml:"moniker,omitempty"`
+	Role      string `json:"role" yaml:"role"`
+	Source    string `json:"source" yaml:"source"`
+	Connected bool   `json:"connected" yaml:"connected"`
+	Reachable bool   `json:"reachable,omitempty" yaml:"reachable,omitempty"`
+	LatencyMs int64  `json:"latency_ms,omitempty" yaml:"latency_ms,omitempty"`
+	Score     int    `json:"score,omitempty" yaml:"score,omitempty"`
+	Error     string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// String formats the peer as ethermintd expects it in persistent_peers.
+func (p PeerInfo) String() string {
+	return p.ID + "@" + p.Address
+}
+
+// PeerReport is the result of the peers commands.
+type PeerReport struct {
+	Node   string     `json:"node" yaml:"node"`
+	Peers  []PeerInfo `json:"peers" yaml:"peers"`
+	tested bool
+}
+
+func (r PeerReport) renderTable(w io.Writer) {
+	if r.tested {
+		fmt.Fprintln(w, "ID\tADDRESS\tROLE\tSOURCE\tCONNECTED\tREACHABLE\tLATENCY\tSCORE")
+	} else {
+		fmt.Fprintln(w, "ID\tADDRESS\tROLE\tSOURCE\tCONNECTED")
+	}
+	for _, p := range r.Peers {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t", p.ID, p.Address, p.Role, p.Source, p.Connected)
+		if r.tested {
+			latency := "-"
+			if p.Reachable {
+				latency = fmt.Sprintf("%dms", p.LatencyMs)
+			}
+			fmt.Fprintf(w, "\t%t\t%s\t%d", p.Reachable, latency, p.Score)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+// parsePeer splits a <node id>@<host>:<port> peer.
+func parsePeer(s, role, source string) (PeerInfo, error) {
+	s = strings.TrimSpace(s)
+	if err := validatePeer(s); err != nil {
+		return PeerInfo{}, err
+	}
+	id, addr, _ := strings.Cut(s, "@")
+	return PeerInfo{ID: strings.ToLower(id), Address: addr, Role: role, Source: source}, nil
+}
+
+type netInfoResponse struct {
+	Result struct {
+		Peers []struct {
+			NodeInfo struct {
+				ID         string `json:"id"`
+				ListenAddr string `json:"listen_addr"`
+				Moniker    string `json:"moniker"`
+				Other      struct {
+					RPCAddress string `json:"rpc_address"`
+				} `json:"other"`
+			} `json:"node_info"`
+			RemoteIP string `json:"remote_ip"`
+		} `json:"peers"`
+	} `json:"result"`
+}
+
+type statusNodeInfoResponse struct {
+	Result struct {
+		NodeInfo struct {
+			ID         string `json:"id"`
+			ListenAddr string `json:"listen_addr"`
+			Moniker    string `json:"moniker"`
+		} `json:"node_info"`
+	} `json:"result"`
+}
+
+func rpcGetJSON(server, path string, v any) error {
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Get(rpcHTTPURL(server) + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s%s returned %s", rpcHTTPURL(server), path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// listenPort returns the port of a listen_addr such as tcp://0.0.0.0:26656.
+func listenPort(listenAddr string) string {
+	_, port, err := net.SplitHostPort(strings.TrimPrefix(listenAddr, "tcp://"))
+	if err != nil {
+		return ""
+	}
+	return port
+}
+
+// fetchNetInfo returns the peers a node is connected to. Peers listen on
+// the port they advertise, at the address the node sees them from.
+func fetchNetInfo(server, source string) ([]PeerInfo, error) {
+	var info netInfoResponse
+	if err := rpcGetJSON(server, "/net_info", &info); err != nil {
+		return nil, err
+	}
+	var peers []PeerInfo
+	for _, p := range info.Result.Peers {
+		port := listenPort(p.NodeInfo.ListenAddr)
+		if p.NodeInfo.ID == "" || p.RemoteIP == "" || port == "" {
+			continue
+		}
+		peers = append(peers, PeerInfo{
+			ID:      strings.ToLower(p.NodeInfo.ID),
+			Address: net.JoinHostPort(p.RemoteIP, port),
+			Moniker: p.NodeInfo.Moniker,
+			Role:    peerRoleDiscovered,
+			Source:  source,
+		})
+	}
+	return peers, nil
+}
+
+// fetchNodePeer returns the node behind an RPC server as a peer, reached at
+// the RPC host on its P2P port.
+func fetchNodePeer(server, source string) (PeerInfo, error) {
+	var status statusNodeInfoResponse
+	if err := rpcGetJSON(server, "/status", &status); err != nil {
+		return PeerInfo{}, err
+	}
+	u, err := url.Parse(rpcHTTPURL(server))
+	if err != nil {
+		return PeerInfo{}, err
+	}
+	port := listenPort(status.Result.NodeInfo.ListenAddr)
+	if status.Result.NodeInfo.ID == "" || port == "" {
+		return PeerInfo{}, fmt.Errorf("no node id or listen address in the status of %s", server)
+	}
+	return PeerInfo{
+		ID:      strings.ToLower(status.Result.NodeInfo.ID),
+		Address: net.JoinHostPort(u.Hostname(), port),
+		Moniker: status.Result.NodeInfo.Moniker,
+		Role:    peerRoleDiscovered,
+		Source:  source,
+	}, nil
+}
+
+// nodeSeeds returns the seeds of the node: node.yaml, else the config.toml
+// the chain config shipped.
+func nodeSeeds(mynode string, cfg NodeConfig) []string {
+	if len(cfg.Network.Seeds) > 0 {
+		return cfg.Network.Seeds
+	}
+	seeds, _, err := tomlStringValue(filepath.Join(mynode, "config", "config.toml"), "p2p", "seeds")
+	if err != nil {
+		log.Debugf("Could not read seeds from config.toml: %v", err)
+	}
+	return splitPeers(seeds)
+}
+
+// configuredPeers returns the persistent peers and seeds of the node.
+func configuredPeers(mynode string, cfg NodeConfig) []PeerInfo {
+	var peers []PeerInfo
+	for _, s := range cfg.Network.PersistentPeers {
+		if p, err := parsePeer(s, peerRolePersistent, "config"); err == nil {
+			peers = append(peers, p)
+		}
+	}
+	for _, s := range nodeSeeds(mynode, cfg) {
+		if p, err := parsePeer(s, peerRoleSeed, "config"); err == nil {
+			peers = append(peers, p)
+		} else {
+			log.Warnf("⚠️ Ignoring seed: %v", err)
+		}
+	}
+	return peers
+}
+
+// mergePeers combines peer lists, keeping the first entry of every node id.
+func mergePeers(lists ...[]PeerInfo) []PeerInfo {
+	var merged []PeerInfo
+	index := map[string]int{}
+	for _, list := range lists {
+		for _, p := range list {
+			if i, ok := index[p.ID]; ok {
+				merged[i].Connected = merged[i].Connected || p.Connected
+				if merged[i].Moniker == "" {
+					merged[i].Moniker = p.Moniker
+				}
+				continue
+			}
+			index[p.ID] = len(merged)
+			merged = append(merged, p)
+		}
+	}
+	return merged
+}
+
+// markConnected flags the peers the local node is connected to. A node that
+// is not running simply has no connected peers.
+func markConnected(mynode string, peers []PeerInfo) ([]PeerInfo, []PeerInfo) {
+	node, err := nodeRPC(mynode)
+	if err != nil {
+		return peers, nil
+	}
+	local, err := fetchNetInfo(node, "local")
+	if err != nil {
+		log.Debugf("Could not query net_info of %s: %v", mynode, err)
+		return peers, nil
+	}
+	connected := map[string]bool{}
+	for i := range local {
+		local[i].Connected = true
+		connected[local[i].ID] = true
+	}
+	for i := range peers {
+		peers[i].Connected = connected[peers[i].ID]
+	}
+	return peers, local
+}
+
+// testPeers dials every peer and scores it: unreachable peers score 0,
+// reachable ones 50, up to 40 more for a latency below 50ms falling to none
+// at 1s, and 10 more when the node is already connected to them.
+func testPeers(peers []PeerInfo) {
+	var wg sync.WaitGroup
+	for i := range peers {
+		wg.Add(1)
+		go func(p *PeerInfo) {
+			defer wg.Done()
+			start := time.Now()
+			conn, err := net.DialTimeout("tcp", p.Address, peerDialTimeout)
+			if err != nil {
+				p.Error = err.Error()
+				return
+			}
+			conn.Close()
+			latency := time.Since(start)
+			p.Reachable = true
+			p.LatencyMs = latency.Milliseconds()
+			p.Score = 50 + latencyScore(latency)
+			if p.Connected {
+				p.Score += 10
+			}
+		}(&peers[i])
+	}
+	wg.Wait()
+	sort.SliceStable(peers, func(i, j int) bool {
+		if peers[i].Score != peers[j].Score {
+			return peers[i].Score > peers[j].Score
+		}
+		return peers[i].LatencyMs < peers[j].LatencyMs
+	})
+}
+
+func latencyScore(latency time.Duration) int {
+	const best, worst = 50 * time.Millisecond, time.Second
+	switch {
+	case latency <= best:
+		return 40
+	case latency >= worst:
+		return 0
+	}
+	return int(40 * (worst - latency) / (worst - best))
+}
+
+// writePeers stores the peers in node.yaml, the generated .env and the
+// [p2p] section of config.toml.
+func writePeers(mynode string, cfg NodeConfig, persistent, seeds []string) error {
+	// A sentry always keeps its validator, which is addressed by container
+	// name and so can neither be dialed nor discovered from the host.
+	if validatorPeer, err := sentryValidatorPeer(cfg); err != nil {
+		return err
+	} else if validatorPeer != "" {
+		id, _, _ := strings.Cut(validatorPeer, "@")
+		persistent = append([]string{validatorPeer}, removePeer(persistent, id)...)
+		seeds = removePeer(seeds, id)
+	}
+	cfg.Network.PersistentPeers = persistent
+	cfg.Network.Seeds = seeds
+	if problems := cfg.Validate(); len(problems) > 0 {
+		return nodeConfigError(mynode, problems)
+	}
+	if err := saveNodeConfig(mynode, cfg); err != nil {
+		return err
+	}
+	configPath := filepath.Join(mynode, "config", "config.toml")
+	if _, err := os.Stat(configPath); err != nil {
+		log.Warnf("⚠️ %s does not exist yet, only %s was updated", configPath, nodeConfigFile)
+		return nil
+	}
+	values := map[string]string{
+		"persistent_peers": tomlString(strings.Join(persistent, ",")),
+		"seeds":            tomlString(strings.Join(seeds, ",")),
+	}
+	if err := setTomlValues(configPath, "p2p", values, "seeds", "persistent_peers"); err != nil {
+		return err
+	}
+	log.Infof("✅ Updated persistent_peers and seeds in %s", configPath)
+	return nil
+}
+
+// sentryValidatorPeer returns the validator peer of a sentry, or "" for
+// other nodes.
+func sentryValidatorPeer(cfg NodeConfig) (string, error) {
+	if cfg.Sentry == nil || cfg.Sentry.Validator == "" {
+		return "", nil
+	}
+	vcfg, err := loadNodeConfig(cfg.Sentry.Validator)
+	if err != nil {
+		return "", err
+	}
+	return containerPeer(cfg.Sentry.Validator, vcfg)
+}
+
+// guardSentryValidator refuses to change the peers of a validator behind
+// sentries, which must only ever peer with its sentries.
+func guardSentryValidator(mynode string, cfg NodeConfig) error {
+	if cfg.Sentry != nil && len(cfg.Sentry.Nodes) > 0 {
+		return &ConfigError{
+			Msg:  mynode + " is a validator behind sentries and only peers with them",
+			Hint: "manage the public peers on the sentries, e.g. 'peers refresh --mynode " + cfg.Sentry.Nodes[0] + "'",
+		}
+	}
+	return nil
+}
+
+// recreateNodeContainer replaces the node container so that start-node
+// flags taken from node.yaml, such as the peers, take effect; docker start
+// would reuse the flags of the old container.
+func recreateNodeContainer(mynode string) error {
+	if err := removeNode(mynode); err != nil {
+		log.Warnf("⚠️ Could not remove the old container %s: %v", nodeContainer(mynode), err)
+	}
+	return startNodeCmdLogic(mynode)
+}
+
+func applyPeerChange(mynode string, restart bool) error {
+	if restart {
+		return recreateNodeContainer(mynode)
+	}
+	log.Infof("💡 Run 'peers refresh --mynode %s --restart' or recreate the container with 'start-node' to apply the new peers.", mynode)
+	return nil
+}
+
+func peersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "peers",
+		Short: "Discover, test and rotate the node's persistent peers and seeds",
+		Long: `Peers are kept in node.yaml and written to the [p2p] section of config.toml.
+'peers refresh' discovers peers through net_info of the local node and the
+boot node, dials them and keeps the best reachable ones, so a dead boot node
+or stale peer list does not strand the node.`,
+	}
+	cmd.AddCommand(peersListCmd(), peersAddCmd(), peersRemoveCmd(), peersTestCmd(), peersRefreshCmd())
+	return cmd
+}
+
+func peersListCmd() *cobra.Command {
+	var mynode string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List configured peers and the peers the node is connected to",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadNodeConfig(mynode)
+			if err != nil {
+				return err
+			}
+			peers, local := markConnected(mynode, configuredPeers(mynode, cfg))
+			return render(PeerReport{Node: mynode, Peers: mergePeers(peers, local)})
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	return cmd
+}
+
+func peersAddCmd() *cobra.Command {
+	var mynode string
+	var seed, restart bool
+
+	cmd := &cobra.Command{
+		Use:   "add <id@host:port>...",
+		Short: "Add persistent peers, or seeds with --seed",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return peersAddLogic(mynode, args, seed, restart)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	cmd.Flags().BoolVar(&seed, "seed", false, "Add the peers as seeds instead of persistent peers")
+	cmd.Flags().BoolVar(&restart, "restart", false, "Recreate the node container so the change takes effect")
+	requireNode(cmd)
+	return cmd
+}
+
+func peersAddLogic(mynode string, args []string, seed, restart bool) error {
+	cfg, err := loadNodeConfig(mynode)
+	if err != nil {
+		return err
+	}
+	if err := guardSentryValidator(mynode, cfg); err != nil {
+		return err
+	}
+	persistent := cfg.Network.PersistentPeers
+	seeds := nodeSeeds(mynode, cfg)
+	for _, arg := range args {
+		p, err := parsePeer(arg, "", "")
+		if err != nil {
+			return &usageError{Err: err}
+		}
+		persistent = removePeer(persistent, p.ID)
+		seeds = removePeer(seeds, p.ID)
+		if seed {
+			seeds = append(seeds, p.String())
+		} else {
+			persistent = append(persistent, p.String())
+		}
+		log.Infof("➕ Added %s", p)
+	}
+	if err := writePeers(mynode, cfg, persistent, seeds); err != nil {
+		return err
+	}
+	return applyPeerChange(mynode, restart)
+}
+
+func peersRemoveCmd() *cobra.Command {
+	var mynode string
+	var restart bool
+
+	cmd := &cobra.Command{
+		Use:   "remove <id|id@host:port>...",
+		Short: "Remove persistent peers or seeds",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return peersRemoveLogic(mynode, args, restart)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	cmd.Flags().BoolVar(&restart, "restart", false, "Recreate the node container so the change takes effect")
+	requireNode(cmd)
+	return cmd
+}
+
+func peersRemoveLogic(mynode string, args []string, restart bool) error {
+	cfg, err := loadNodeConfig(mynode)
+	if err != nil {
+		return err
+	}
+	if err := guardSentryValidator(mynode, cfg); err != nil {
+		return err
+	}
+	validatorPeer, err := sentryValidatorPeer(cfg)
+	if err != nil {
+		return err
+	}
+	validatorID, _, _ := strings.Cut(validatorPeer, "@")
+	persistent := cfg.Network.PersistentPeers
+	seeds := nodeSeeds(mynode, cfg)
+	for _, arg := range args {
+		id, _, _ := strings.Cut(strings.TrimSpace(arg), "@")
+		if validatorID != "" && strings.EqualFold(id, validatorID) {
+			return &ConfigError{Msg: id + " is the validator guarded by sentry " + mynode, Hint: "run 'sentry remove --mynode " + cfg.Sentry.Validator + " --name " + mynode + "' to take the sentry out of the layout"}
+		}
+		before := len(persistent) + len(seeds)
+		persistent = removePeer(persistent, id)
+		seeds = removePeer(seeds, id)
+		if len(persistent)+len(seeds) == before {
+			return &ConfigError{Msg: "peer " + id + " is not configured", Hint: "run 'peers list --mynode " + mynode + "'"}
+		}
+		log.Infof("➖ Removed %s", id)
+	}
+	if err := writePeers(mynode, cfg, persistent, seeds); err != nil {
+		return err
+	}
+	return applyPeerChange(mynode, restart)
+}
+
+// selectRefreshPeers picks the peers refresh keeps out of the tested peers,
+// best first. Reachable configured seeds stay seeds and the best other peers
+// become persistent peers. Full nodes are never promoted to seeds, since they
+// do not crawl the network for addresses.
+func selectRefreshPeers(peers, configured []PeerInfo, maxPeers, maxSeeds int) (persistent, seeds []string) {
+	wasSeed := map[string]bool{}
+	for _, p := range configured {
+		wasSeed[p.ID] = p.Role == peerRoleSeed
+	}
+	for _, p := range peers {
+		switch {
+		case !p.Reachable:
+			continue
+		case wasSeed[p.ID]:
+			if len(seeds) < maxSeeds {
+				seeds = append(seeds, p.String())
+			}
+		case len(persistent) < maxPeers:
+			persistent = append(persistent, p.String())
+		}
+	}
+	return persistent, seeds
+}
+
+// removePeer drops the entries of node id from a peer list.
+func removePeer(peers []string, id string) []string {
+	var kept []string
+	for _, p := range peers {
+		if pid, _, _ := strings.Cut(p, "@"); !strings.EqualFold(pid, id) {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+func peersTestCmd() *cobra.Command {
+	var mynode string
+
+	cmd := &cobra.Command{
+		Use:   "test",
+		Short: "Dial the configured peers and rank them by reachability and latency",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadNodeConfig(mynode)
+			if err != nil {
+				return err
+			}
+			peers, _ := markConnected(mynode, configuredPeers(mynode, cfg))
+			if len(peers) == 0 {
+				return &ConfigError{Msg: "the node has no persistent peers or seeds", Hint: "run 'peers refresh --mynode " + mynode + "' to discover peers"}
+			}
+			testPeers(peers)
+			report := PeerReport{Node: mynode, Peers: peers, tested: true}
+			if err := render(report); err != nil {
+				return err
+			}
+			for _, p := range peers {
+				if p.Reachable {
+					return nil
+				}
+			}
+			return &RuntimeError{Msg: "none of the configured peers is reachable", Hint: "run 'peers refresh --mynode " + mynode + "' to find new peers"}
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	return cmd
+}
+
+// peersRefreshOptions configure peers refresh.
+type peersRefreshOptions struct {
+	MaxPeers int
+	MaxSeeds int
+	BootRpc  string
+	DryRun   bool
+	Restart  bool
+}
+
+func peersRefreshCmd() *cobra.Command {
+	var mynode string
+	var opts peersRefreshOptions
+
+	cmd := &cobra.Command{
+		Use:   "refresh",
+		Short: "Discover peers via net_info and rewrite persistent_peers and seeds with the best reachable ones",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return peersRefreshLogic(mynode, opts)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	cmd.Flags().IntVar(&opts.MaxPeers, "max-peers", 10, "Number of persistent peers to keep")
+	cmd.Flags().IntVar(&opts.MaxSeeds, "max-seeds", 3, "Number of seeds to keep")
+	cmd.Flags().StringVar(&opts.BootRpc, "boot-rpc", "", "RPC of the node to discover peers from (default: boot_node_rpc of node.yaml)")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Only show the ranking, do not change the configuration")
+	cmd.Flags().BoolVar(&opts.Restart, "restart", false, "Recreate the node container so the new peers take effect")
+	requireNode(cmd)
+	return cmd
+}
+
+func peersRefreshLogic(mynode string, opts peersRefreshOptions) error {
+	if opts.MaxPeers < 1 || opts.MaxSeeds < 0 {
+		return &usageError{Err: fmt.Errorf("--max-peers must be at least 1 and --max-seeds at least 0")}
+	}
+	cfg, err := loadNodeConfig(mynode)
+	if err != nil {
+		return err
+	}
+	if err := guardSentryValidator(mynode, cfg); err != nil {
+		return err
+	}
+	bootRpc := opts.BootRpc
+	if bootRpc == "" {
+		bootRpc = cfg.Network.BootNodeRpc
+	}
+
+	validatorPeer, err := sentryValidatorPeer(cfg)
+	if err != nil {
+		return err
+	}
+	validatorID, _, _ := strings.Cut(validatorPeer, "@")
+	var candidates []PeerInfo
+	for _, p := range configuredPeers(mynode, cfg) {
+		if p.ID != validatorID {
+			candidates = append(candidates, p)
+		}
+	}
+	configured, local := markConnected(mynode, candidates)
+	if validatorID != "" {
+		// The sentry's own connection to its validator is not a candidate.
+		local = slices.DeleteFunc(local, func(p PeerInfo) bool { return p.ID == validatorID })
+	}
+	lists := [][]PeerInfo{configured, local}
+	if bootRpc != "" {
+		if boot, err := fetchNodePeer(bootRpc, "boot"); err == nil {
+			lists = append(lists, []PeerInfo{boot})
+		} else {
+			log.Warnf("⚠️ Could not query the boot node %s: %v", bootRpc, err)
+		}
+		if discovered, err := fetchNetInfo(bootRpc, "boot"); err == nil {
+			lists = append(lists, discovered)
+		} else {
+			log.Warnf("⚠️ Could not query net_info of the boot node %s: %v", bootRpc, err)
+		}
+	}
+	peers := mergePeers(lists...)
+	if len(peers) == 0 {
+		return &ChainQueryError{Query: "net_info", Err: fmt.Errorf("no peers found"), Hint: "pass --boot-rpc with the RPC of a reachable node"}
+	}
+	log.Infof("🔎 Testing %d peers", len(peers))
+	testPeers(peers)
+
+	persistent, seeds := selectRefreshPeers(peers, configured, opts.MaxPeers, opts.MaxSeeds)
+
+	if err := render(PeerReport{Node: mynode, Peers: peers, tested: true}); err != nil {
+		return err
+	}
+	if len(persistent) == 0 {
+		return &RuntimeError{Msg: "none of the discovered peers is reachable, keeping the current peers", Hint: "check outgoing TCP connections to the peers' P2P ports"}
+	}
+	log.Infof("✅ %d persistent peers and %d seeds selected", len(persistent), len(seeds))
+	if opts.DryRun {
+		return nil
+	}
+	if err := writePeers(mynode, cfg, persistent, seeds); err != nil {
+		return err
+	}
+	return applyPeerChange(mynode, opts.Restart)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLatencyScore(t *testing.T) {
+	tests := []struct {
+		latency time.Duration
+		want    int
+	}{
+		{latency: 0, want: 40},
+		{latency: 10 * time.Millisecond, want: 40},
+		{latency: 50 * time.Millisecond, want: 40},
+		{latency: 525 * time.Millisecond, want: 20},
+		{latency: 905 * time.Millisecond, want: 4},
+		{latency: time.Second, want: 0},
+		{latency: 5 * time.Second, want: 0},
+	}
+	for _, tt := range tests {
+		if got := latencyScore(tt.latency); got != tt.want {
+			t.Errorf("latencyScore(%s) = %d, want %d", tt.latency, got, tt.want)
+		}
+	}
+	for l := 50 * time.Millisecond; l < time.Second; l += 10 * time.Millisecond {
+		if latencyScore(l+10*time.Millisecond) > latencyScore(l) {
+			t.Fatalf("latencyScore rises between %s and %s", l, l+10*time.Millisecond)
+		}
+	}
+}
+
+func TestMergePeers(t *testing.T) {
+	configured := []PeerInfo{
+		{ID: "aa", Address: "10.0.0.1:26656", Role: peerRolePersistent, Source: "node.yaml"},
+		{ID: "bb", Address: "10.0.0.2:26656", Role: peerRoleSeed, Source: "node.yaml"},
+	}
+	local := []PeerInfo{
+		{ID: "bb", Address: "192.168.1.2:26656", Moniker: "seed-b", Role: peerRoleDiscovered, Source: "local", Connected: true},
+		{ID: "cc", Address: "10.0.0.3:26656", Moniker: "node-c", Role: peerRoleDiscovered, Source: "local", Connected: true},
+	}
+	boot := []PeerInfo{
+		{ID: "aa", Address: "172.16.0.1:26656", Moniker: "node-a", Role: peerRoleDiscovered, Source: "boot"},
+		{ID: "cc", Address: "10.0.0.3:26656", Moniker: "other", Role: peerRoleDiscovered, Source: "boot"},
+		{ID: "dd", Address: "10.0.0.4:26656", Role: peerRoleDiscovered, Source: "boot"},
+	}
+
+	got := mergePeers(configured, local, boot)
+	want := []PeerInfo{
+		{ID: "aa", Address: "10.0.0.1:26656", Moniker: "node-a", Role: peerRolePersistent, Source: "node.yaml"},
+		{ID: "bb", Address: "10.0.0.2:26656", Moniker: "seed-b", Role: peerRoleSeed, Source: "node.yaml", Connected: true},
+		{ID: "cc", Address: "10.0.0.3:26656", Moniker: "node-c", Role: peerRoleDiscovered, Source: "local", Connected: true},
+		{ID: "dd", Address: "10.0.0.4:26656", Role: peerRoleDiscovered, Source: "boot"},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("mergePeers =\n%+v\nwant\n%+v", got, want)
+	}
+	if got := mergePeers(); len(got) != 0 {
+		t.Errorf("mergePeers() = %v, want none", got)
+	}
+}
+
+func TestRemovePeer(t *testing.T) {
+	peers := []string{"aa@10.0.0.1:26656", "BB@10.0.0.2:26656", "cc@10.0.0.3:26656", "bb@10.0.0.4:26656"}
+	tests := []struct {
+		name string
+		id   string
+		want []string
+	}{
+		{name: "single entry", id: "aa", want: []string{"BB@10.0.0.2:26656", "cc@10.0.0.3:26656", "bb@10.0.0.4:26656"}},
+		{name: "every address, any case", id: "bb", want: []string{"aa@10.0.0.1:26656", "cc@10.0.0.3:26656"}},
+		{name: "unknown id", id: "ee", want: peers},
+		{name: "prefix of an id", id: "a", want: peers},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := removePeer(peers, tt.id); !slices.Equal(got, tt.want) {
+				t.Errorf("removePeer(%s) = %v, want %v", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectRefreshPeers(t *testing.T) {
+	peer := func(id string, reachable bool) PeerInfo {
+		return PeerInfo{ID: id, Address: "10.0.0.1:26656", Reachable: reachable}
+	}
+	configured := []PeerInfo{
+		{ID: "s1", Role: peerRoleSeed},
+		{ID: "s2", Role: peerRoleSeed},
+		{ID: "s3", Role: peerRoleSeed},
+		{ID: "p1", Role: peerRolePersistent},
+	}
+	// Tested peers, best first.
+	tested := []PeerInfo{peer("s1", true), peer("p1", true), peer("d1", true), peer("s2", false), peer("s3", true), peer("d2", true), peer("d3", false)}
+
+	tests := []struct {
+		name           string
+		maxPeers       int
+		maxSeeds       int
+		wantPersistent []string
+		wantSeeds      []string
+	}{
+		{
+			name:     "room for everything",
+			maxPeers: 10, maxSeeds: 3,
+			wantPersistent: []string{"p1@10.0.0.1:26656", "d1@10.0.0.1:26656", "d2@10.0.0.1:26656"},
+			wantSeeds:      []string{"s1@10.0.0.1:26656", "s3@10.0.0.1:26656"},
+		},
+		{
+			name:     "seeds beyond the limit are dropped, not made persistent",
+			maxPeers: 10, maxSeeds: 1,
+			wantPersistent: []string{"p1@10.0.0.1:26656", "d1@10.0.0.1:26656", "d2@10.0.0.1:26656"},
+			wantSeeds:      []string{"s1@10.0.0.1:26656"},
+		},
+		{
+			name:     "best persistent peers first",
+			maxPeers: 2, maxSeeds: 0,
+			wantPersistent: []string{"p1@10.0.0.1:26656", "d1@10.0.0.1:26656"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			persistent, seeds := selectRefreshPeers(tested, configured, tt.maxPeers, tt.maxSeeds)
+			if !slices.Equal(persistent, tt.wantPersistent) {
+				t.Errorf("persistent = %v, want %v", persistent, tt.wantPersistent)
+			}
+			if !slices.Equal(seeds, tt.wantSeeds) {
+				t.Errorf("seeds = %v, want %v", seeds, tt.wantSeeds)
+			}
+		})
+	}
+
+	if persistent, seeds := selectRefreshPeers([]PeerInfo{peer("d1", false)}, nil, 10, 3); persistent != nil || seeds != nil {
+		t.Errorf("unreachable peers selected: %v %v", persistent, seeds)
+	}
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/joho/godotenv"
+)
+
+// portService is one port a node listens on. Ports of a block are laid out
+// as base + Offset; only Public services are published on all interfaces by
+// default.
+type portService struct {
+	Name    string
+	Key     string
+	BindKey string
+	Default string
+	Offset  int
+	Public  bool
+}
+
+var portServices = []portService{
+	{Name: "p2p", Key: "P2P_PORT", BindKey: "P2P_BIND", Default: "26666", Offset: 0, Public: true},
+	{Name: "rpc", Key: "RPC_PORT", BindKey: "RPC_BIND", Default: "26667", Offset: 1},
+	{Name: "grpc", Key: "GRPC_PORT", BindKey: "GRPC_BIND", Default: "9092", Offset: 2},
+	{Name: "grpc-web", Key: "GRPC_WEB_PORT", BindKey: "GRPC_WEB_BIND", Default: "9093", Offset: 3},
+	{Name: "json-rpc", Key: "JSON_RPC_PORT", BindKey: "JSON_RPC_BIND", Default: "8547", Offset: 4},
+}
+
+// nodePortKeys are the .env keys of the ports a node listens on.
+var nodePortKeys = func() []string {
+	keys := make([]string, len(portServices))
+	for i, s := range portServices {
+		keys[i] = s.Key
+	}
+	return keys
+}()
+
+const (
+	// autoPortBase is where --auto starts looking for a free block.
+	autoPortBase = 26666
+	// autoPortStep keeps the blocks of sibling nodes apart.
+	autoPortStep = 10
+	bindPublic   = "0.0.0.0"
+	bindLocal    = "127.0.0.1"
+)
+
+// portOptions select how port-set picks ports and where they are published.
+type portOptions struct {
+	Auto     bool
+	BasePort int
+	Bind     map[string]string
+}
+
+// parsePort validates a TCP port number.
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+	if port < 1 || port > 65535 {
+		return 0, fmt.Errorf("%d is outside 1-65535", port)
+	}
+	return port, nil
+}
+
+// bindAddresses returns the publish address of every service: the --bind
+// override, else 0.0.0.0 for public services and 127.0.0.1 for the rest.
+func bindAddresses(overrides map[string]string) (map[string]string, error) {
+	binds := map[string]string{}
+	for _, s := range portServices {
+		binds[s.Name] = bindLocal
+		if s.Public {
+			binds[s.Name] = bindPublic
+		}
+	}
+	for name, addr := range overrides {
+		if _, ok := binds[name]; !ok {
+			return nil, fmt.Errorf("unknown service %q in --bind, expected one of p2p, rpc, grpc, grpc-web, json-rpc", name)
+		}
+		if net.ParseIP(addr) == nil {
+			return nil, fmt.Errorf("invalid bind address %q for %s", addr, name)
+		}
+		binds[name] = addr
+	}
+	return binds, nil
+}
+
+// reservedPorts collects the ports other nodes already claim: the .env files
+// of sibling node directories, the registry and running Docker containers.
+// The value names the owner. own holds the ports published by the node's own
+// container, which stay usable for it although they are bound.
+func reservedPorts(mynode string) (reserved map[int]string, own map[int]bool) {
+	reserved = map[int]string{}
+	own = map[int]bool{}
+	self, _ := filepath.Abs(mynode)
+
+	claim := func(owner string, env map[string]string) {
+		for _, key := range nodePortKeys {
+			if port, err := parsePort(env[key]); err == nil {
+				if _, taken := reserved[port]; !taken {
+					reserved[port] = owner
+				}
+			}
+		}
+	}
+
+	siblings, _ := filepath.Glob(filepath.Join("*", ".env"))
+	for _, envPath := range siblings {
+		dir := filepath.Dir(envPath)
+		if abs, _ := filepath.Abs(dir); abs == self {
+			continue
+		}
+		if env, err := godotenv.Read(envPath); err == nil {
+			claim("node "+dir, env)
+		}
+	}
+
+	if registry, err := loadNodeRegistry(); err == nil {
+		for _, n := range registry.Nodes {
+			if n.Remote == nil && n.Home != self {
+				claim("node "+n.Name, n.Ports)
+			}
+		}
+	}
+
+	var output syncBuffer
+	if err := transport.Run(nil, &output, &output, "docker", "ps", "--format", "{{.Names}}\t{{.Ports}}"); err == nil {
+		for _, line := range strings.Split(output.String(), "\n") {
+			name, ports, ok := strings.Cut(line, "\t")
+			if !ok {
+				continue
+			}
+			for _, port := range dockerHostPorts(ports) {
+				if name == nodeContainer(filepath.Base(mynode)) {
+					own[port] = true
+				} else if _, taken := reserved[port]; !taken {
+					reserved[port] = "container " + name
+				}
+			}
+		}
+	}
+	return reserved, own
+}
+
+// dockerHostPorts parses the host side of docker ps port bindings such as
+// "0.0.0.0:26656->26656/tcp, 127.0.0.1:9090-9091->9090-9091/tcp".
+func dockerHostPorts(bindings string) []int {
+	var ports []int
+	for _, binding := range strings.Split(bindings, ",") {
+		host, _, ok := strings.Cut(strings.TrimSpace(binding), "->")
+		if !ok {
+			continue
+		}
+		host = host[strings.LastIndex(host, ":")+1:]
+		first, last, isRange := strings.Cut(host, "-")
+		from, err := strconv.Atoi(first)
+		if err != nil {
+			continue
+		}
+		to := from
+		if isRange {
+			if to, err = strconv.Atoi(last); err != nil {
+				continue
+			}
+		}
+		for p := from; p <= to; p++ {
+			ports = append(ports, p)
+		}
+	}
+	return ports
+}
+
+// portProblem explains why port cannot be used, or returns "". Ports in own
+// are held by the node itself and are not probed.
+func portProblem(port int, reserved map[int]string, own, picked map[int]bool) string {
+	if picked[port] {
+		return fmt.Sprintf("port %d is already used by another service of this node", port)
+	}
+	if owner, ok := reserved[port]; ok {
+		return fmt.Sprintf("port %d is used by %s", port, owner)
+	}
+	if own[port] {
+		return ""
+	}
+	if err := checkPort(strconv.Itoa(port)); err != nil {
+		return fmt.Sprintf("port %d is not available: %v", port, err)
+	}
+	return ""
+}
+
+// portBlock returns the ports of the block starting at base, or why the
+// block cannot be used.
+func portBlock(base int, reserved map[int]string, own map[int]bool) (map[string]int, string) {
+	ports := map[string]int{}
+	picked := map[int]bool{}
+	for _, s := range portServices {
+		port := base + s.Offset
+		if port > 65535 {
+			return nil, fmt.Sprintf("port %d is outside 1-65535", port)
+		}
+		if problem := portProblem(port, reserved, own, picked); problem != "" {
+			return nil, problem
+		}
+		ports[s.Key] = port
+		picked[port] = true
+	}
+	return ports, ""
+}
+
+// autoPortBlock finds the first free block at or above base.
+func autoPortBlock(base int, reserved map[int]string, own map[int]bool) (map[string]int, error) {
+	for b := base; b+len(portServices)-1 <= 65535; b += autoPortStep {
+		ports, problem := portBlock(b, reserved, own)
+		if problem == "" {
+			return ports, nil
+		}
+		log.Debugf("Skipping port block %d: %s", b, problem)
+	}
+	return nil, fmt.Errorf("no free block of %d ports at or above %d", len(portServices), base)
+}
+
+// choosePorts picks the node ports according to opts, asking for each port
+// when neither --auto nor --base-port is given.
+func choosePorts(mynode string, opts portOptions) (map[string]int, error) {
+	reserved, own := reservedPorts(mynode)
+	if len(reserved) > 0 {
+		owners := map[string]bool{}
+		for _, owner := range reserved {
+			owners[owner] = true
+		}
+		names := make([]string, 0, len(owners))
+		for owner := range owners {
+			names = append(names, owner)
+		}
+		sort.Strings(names)
+		log.Infof("🔎 Avoiding ports of %s", strings.Join(names, ", "))
+	}
+
+	switch {
+	case opts.Auto:
+		base := opts.BasePort
+		if base == 0 {
+			base = autoPortBase
+		}
+		ports, err := autoPortBlock(base, reserved, own)
+		if err != nil {
+			return nil, &ConfigError{Msg: "could not find free ports", Err: err, Hint: "pass another --base-port"}
+		}
+		return ports, nil
+	case opts.BasePort != 0:
+		ports, problem := portBlock(opts.BasePort, reserved, own)
+		if problem != "" {
+			return nil, &ConfigError{Msg: "cannot use the ports at --base-port " + strconv.Itoa(opts.BasePort), Err: fmt.Errorf("%s", problem), Hint: "add --auto to search upwards for a free block"}
+		}
+		return ports, nil
+	}
+
+	fmt.Fprint(os.Stderr, "\n Please enter port - \n")
+	ports := map[string]int{}
+	picked := map[int]bool{}
+	for _, s := range portServices {
+		port, err := getPortInputAndCheck(s.Key, s.Default, picked, reserved, own)
+		if err != nil {
+			return nil, err
+		}
+		ports[s.Key] = port
+		picked[port] = true
+	}
+	return ports, nil
+}
+
+// publishArg is the docker -p value of a service: bind:port:port.
+func publishArg(sp ServicePort) string {
+	return fmt.Sprintf("%s:%d:%d", sp.Bind, sp.Port, sp.Port)
+}
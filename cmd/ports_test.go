@@ -0,0 +1,70 @@
+package main
+
+import (
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestPortProblemOwnPorts(t *testing.T) {
+	// A bound port stands in for one published by a running node container.
+	ln, err := net.Listen("tcp", ":0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer ln.Close()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	tests := []struct {
+		name     string
+		reserved map[int]string
+		own      map[int]bool
+		picked   map[int]bool
+		want     string
+	}{
+		{name: "bound by someone else", want: "is not available"},
+		{name: "held by the node itself", own: map[int]bool{port: true}, want: ""},
+		{name: "claimed by a sibling", reserved: map[int]string{port: "node other"}, own: map[int]bool{port: true}, want: "is used by node other"},
+		{name: "picked twice", own: map[int]bool{port: true}, picked: map[int]bool{port: true}, want: "another service of this node"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := portProblem(port, tt.reserved, tt.own, tt.picked)
+			if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+				t.Errorf("portProblem = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDockerHostPorts(t *testing.T) {
+	got := dockerHostPorts("0.0.0.0:26656->26656/tcp, 127.0.0.1:9090-9091->9090-9091/tcp, [::]:8545->8545/tcp, 26657/tcp")
+	want := []int{26656, 9090, 9091, 8545}
+	if len(got) != len(want) {
+		t.Fatalf("dockerHostPorts = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("dockerHostPorts = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestNodePortsService(t *testing.T) {
+	var ports NodePorts
+	seen := map[*ServicePort]string{}
+	for _, s := range portServices {
+		sp, ok := ports.service(s.Name)
+		if !ok {
+			t.Errorf("NodePorts has no field for port service %s", s.Name)
+			continue
+		}
+		if other, dup := seen[sp]; dup {
+			t.Errorf("port services %s and %s share a NodePorts field", other, s.Name)
+		}
+		seen[sp] = s.Name
+	}
+	if _, ok := ports.service("unknown"); ok {
+		t.Errorf("service(unknown) reported a port")
+	}
+}
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+)
+
+// SentryInfo is one sentry of a validator.
+type SentryInfo struct {
+	Name    string `json:"name" yaml:"name"`
+	NodeID  string `json:"node_id" yaml:"node_id"`
+	P2P     string `json:"p2p" yaml:"p2p"`
+	Running bool   `json:"running" yaml:"running"`
+}
+
+// SentryList is the result of sentry list.
+type SentryList struct {
+	Validator string       `json:"validator" yaml:"validator"`
+	Network   string       `json:"docker_network" yaml:"docker_network"`
+	Sentries  []SentryInfo `json:"sentries" yaml:"sentries"`
+}
+
+func (l SentryList) renderTable(w io.Writer) {
+	fmt.Fprintf(w, "Validator\t%s\n", l.Validator)
+	fmt.Fprintf(w, "Docker network\t%s\n\n", l.Network)
+	fmt.Fprintln(w, "SENTRY\tNODE ID\tP2P\tRUNNING")
+	for _, s := range l.Sentries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\n", s.Name, s.NodeID, s.P2P, s.Running)
+	}
+}
+
+// nodeID derives the node ID from config/node_key.json: the hex encoded
+// first 20 bytes of the SHA-256 of the ed25519 public key.
+func nodeID(mynode string) (string, error) {
+	path := filepath.Join(mynode, "config", "node_key.json")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", &ConfigError{Msg: "failed to read " + path, Err: err, Hint: "run 'init-node --mynode " + mynode + "' first"}
+	}
+	var key struct {
+		PrivKey struct {
+			Value string `json:"value"`
+		} `json:"priv_key"`
+	}
+	if err := json.Unmarshal(data, &key); err != nil {
+		return "", &ConfigError{Msg: "failed to parse " + path, Err: err}
+	}
+	raw, err := base64.StdEncoding.DecodeString(key.PrivKey.Value)
+	if err != nil || len(raw) != ed25519.PrivateKeySize {
+		return "", &ConfigError{Msg: path + " does not hold an ed25519 key", Err: err}
+	}
+	sum := sha256.Sum256(ed25519.PrivateKey(raw).Public().(ed25519.PublicKey))
+	return hex.EncodeToString(sum[:20]), nil
+}
+
+// containerPeer addresses a node by its container name on the shared
+// Docker network.
+func containerPeer(mynode string, cfg NodeConfig) (string, error) {
+	id, err := nodeID(mynode)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s@%s:%d", id, mynode, cfg.Ports.P2P.Port), nil
+}
+
+// ensureDockerNetwork creates a user-defined bridge network unless it exists.
+func ensureDockerNetwork(name string) error {
+	if _, err := quietOutput("docker", "network", "inspect", name); err == nil {
+		return nil
+	}
+	if _, err := runCmdCaptureOutput("docker", "network", "create", name); err != nil {
+		return &RuntimeError{Msg: "failed to create the Docker network " + name, Err: err}
+	}
+	return nil
+}
+
+func sentryNetworkName(validator string) string {
+	return validator + "-sentry"
+}
+
+// setP2PValues sets keys of the [p2p] section of the node's config.toml.
+func setP2PValues(mynode string, values map[string]string) error {
+	return setTomlValues(filepath.Join(mynode, "config", "config.toml"), "p2p", values,
+		"persistent_peers", "pex", "addr_book_strict", "private_peer_ids", "unconditional_peer_ids")
+}
+
+// applySentryTopology writes the sentry layout of validator: the validator
+// only talks to its sentries, with peer exchange off and its P2P port no
+// longer published publicly; every sentry keeps the validator as a private,
+// unconditional peer and carries the public peers the validator used to
+// connect to. With no sentries left the validator is made public again.
+func applySentryTopology(validator string, vcfg NodeConfig, sentries []string) error {
+	validatorPeer, err := containerPeer(validator, vcfg)
+	if err != nil {
+		return err
+	}
+	validatorID, _, _ := strings.Cut(validatorPeer, "@")
+
+	configs := map[string]NodeConfig{}
+	var sentryPeers, sentryIDs []string
+	for _, name := range sentries {
+		cfg, err := loadNodeConfig(name)
+		if err != nil {
+			return err
+		}
+		peer, err := containerPeer(name, cfg)
+		if err != nil {
+			return err
+		}
+		configs[name] = cfg
+		sentryPeers = append(sentryPeers, peer)
+		id, _, _ := strings.Cut(peer, "@")
+		sentryIDs = append(sentryIDs, id)
+	}
+
+	// The public peers are whatever the validator and the sentries are
+	// connected to besides each other.
+	var public []string
+	seen := map[string]bool{validatorID: true}
+	for _, id := range sentryIDs {
+		seen[id] = true
+	}
+	addPublic := func(peers []string) {
+		for _, peer := range peers {
+			if id, _, _ := strings.Cut(peer, "@"); !seen[id] {
+				seen[id] = true
+				public = append(public, peer)
+			}
+		}
+	}
+	if vcfg.Sentry == nil || len(vcfg.Sentry.Nodes) == 0 {
+		addPublic(vcfg.Network.PersistentPeers)
+	}
+	for _, name := range sentries {
+		addPublic(configs[name].Network.PersistentPeers)
+	}
+
+	network := sentryNetworkName(validator)
+	for _, name := range sentries {
+		cfg := configs[name]
+		cfg.DockerNetwork = network
+		cfg.Sentry = &NodeSentry{Validator: validator}
+		cfg.Network.PersistentPeers = append([]string{validatorPeer}, public...)
+		if err := saveNodeConfig(name, cfg); err != nil {
+			return err
+		}
+		if err := setP2PValues(name, map[string]string{
+			"persistent_peers":       tomlString(strings.Join(cfg.Network.PersistentPeers, ",")),
+			"pex":                    tomlBool(true),
+			"addr_book_strict":       tomlBool(false),
+			"private_peer_ids":       tomlString(validatorID),
+			"unconditional_peer_ids": tomlString(validatorID),
+		}); err != nil {
+			return err
+		}
+		log.Infof("✅ Sentry %s guards %s", name, validator)
+	}
+
+	if len(sentries) == 0 {
+		vcfg.DockerNetwork = ""
+		vcfg.Sentry = nil
+		vcfg.Network.PersistentPeers = public
+		vcfg.Ports.P2P.Bind = bindPublic
+		if err := saveNodeConfig(validator, vcfg); err != nil {
+			return err
+		}
+		log.Infof("✅ %s has no sentries left and peers publicly again", validator)
+		return setP2PValues(validator, map[string]string{
+			"persistent_peers":       tomlString(strings.Join(public, ",")),
+			"pex":                    tomlBool(true),
+			"addr_book_strict":       tomlBool(true),
+			"private_peer_ids":       tomlString(""),
+			"unconditional_peer_ids": tomlString(""),
+		})
+	}
+
+	vcfg.DockerNetwork = network
+	vcfg.Sentry = &NodeSentry{Nodes: sentries}
+	vcfg.Network.PersistentPeers = sentryPeers
+	vcfg.Network.Seeds = nil
+	// Sentries reach the validator over the Docker network; nothing outside
+	// this host needs its P2P port.
+	vcfg.Ports.P2P.Bind = bindLocal
+	if err := saveNodeConfig(validator, vcfg); err != nil {
+		return err
+	}
+	if err := setP2PValues(validator, map[string]string{
+		"persistent_peers":       tomlString(strings.Join(sentryPeers, ",")),
+		"seeds":                  tomlString(""),
+		"pex":                    tomlBool(false),
+		"addr_book_strict":       tomlBool(false),
+		"private_peer_ids":       tomlString(strings.Join(sentryIDs, ",")),
+		"unconditional_peer_ids": tomlString(strings.Join(sentryIDs, ",")),
+	}); err != nil {
+		return err
+	}
+	log.Infof("🛡️ %s now only peers with %s", validator, strings.Join(sentries, ", "))
+	return nil
+}
+
+func sentryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sentry",
+		Short: "Protect a validator behind sentry nodes",
+		Long: `Sentry nodes are full nodes that face the public network on behalf of a
+validator. The validator only connects to its sentries over a private Docker
+network: peer exchange is turned off, the sentries become its persistent,
+private and unconditional peers, and its P2P port is published on localhost
+only. Each sentry keeps the validator as a private peer so its address is
+never gossiped.`,
+	}
+	cmd.AddCommand(sentryAddCmd(), sentryListCmd(), sentryRemoveCmd())
+	return cmd
+}
+
+// sentryAddOptions configure sentry add.
+type sentryAddOptions struct {
+	Name             string
+	BasePort         int
+	NoStart          bool
+	RestartValidator bool
+}
+
+func sentryAddCmd() *cobra.Command {
+	var mynode string
+	var opts sentryAddOptions
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "Provision a sentry node for the validator and start it",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sentryAddLogic(mynode, opts)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Name of the validator node")
+	cmd.Flags().StringVar(&opts.Name, "name", "", "Name of the sentry node (default <validator>-sentry<N>)")
+	cmd.Flags().IntVar(&opts.BasePort, "base-port", 0, "First port of the sentry's port block (default: the first free block)")
+	cmd.Flags().BoolVar(&opts.NoStart, "no-start", false, "Only configure the sentry, do not start its container")
+	cmd.Flags().BoolVar(&opts.RestartValidator, "restart-validator", false, "Recreate the validator container so it joins the sentry network")
+	requireNode(cmd)
+	return cmd
+}
+
+func sentryAddLogic(validator string, opts sentryAddOptions) error {
+	vcfg, err := loadNodeConfig(validator)
+	if err != nil {
+		return err
+	}
+	if vcfg.Sentry != nil && vcfg.Sentry.Validator != "" {
+		return &ConfigError{Msg: validator + " is itself a sentry of " + vcfg.Sentry.Validator, Hint: "pass the validator node with --mynode"}
+	}
+	var sentries []string
+	if vcfg.Sentry != nil {
+		sentries = vcfg.Sentry.Nodes
+	}
+
+	name := opts.Name
+	if name == "" {
+		for i := len(sentries) + 1; ; i++ {
+			name = validator + "-sentry" + strconv.Itoa(i)
+			if !exists(name) {
+				break
+			}
+		}
+	}
+	if name == validator || slices.Contains(sentries, name) {
+		return &usageError{Err: fmt.Errorf("%s is already part of the sentry layout of %s", name, validator)}
+	}
+
+	if exists(filepath.Join(name, "config", "genesis.json")) {
+		log.Infof("♻️ Reusing the existing node %s", name)
+	} else if err := initNodeLogic(name); err != nil {
+		return err
+	}
+	if !exists(filepath.Join(name, nodeConfigFile)) && !exists(filepath.Join(name, ".env")) {
+		if err := portSetLogic(name, portOptions{Auto: true, BasePort: opts.BasePort}); err != nil {
+			return err
+		}
+	}
+
+	sentries = append(append([]string{}, sentries...), name)
+	if err := applySentryTopology(validator, vcfg, sentries); err != nil {
+		return err
+	}
+
+	if !opts.NoStart {
+		if err := recreateNodeContainer(name); err != nil {
+			return err
+		}
+	}
+	if opts.RestartValidator {
+		return recreateNodeContainer(validator)
+	}
+	log.Infof("💡 Pass --restart-validator, or remove the %s container and run 'start-node --mynode %s', so the validator joins the sentry network.", validator, validator)
+	return nil
+}
+
+func sentryListCmd() *cobra.Command {
+	var mynode string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the sentries of the validator",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vcfg, err := loadNodeConfig(mynode)
+			if err != nil {
+				return err
+			}
+			list := SentryList{Validator: mynode, Network: vcfg.DockerNetwork, Sentries: []SentryInfo{}}
+			if vcfg.Sentry == nil {
+				return render(list)
+			}
+			for _, name := range vcfg.Sentry.Nodes {
+				info := SentryInfo{Name: name, Running: nodeContainerRunning(name)}
+				if cfg, err := loadNodeConfig(name); err == nil {
+					info.P2P = fmt.Sprintf("%s:%d", cfg.Ports.P2P.Bind, cfg.Ports.P2P.Port)
+				}
+				info.NodeID, _ = nodeID(name)
+				list.Sentries = append(list.Sentries, info)
+			}
+			return render(list)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Name of the validator node")
+	requireNode(cmd)
+	return cmd
+}
+
+func sentryRemoveCmd() *cobra.Command {
+	var mynode, name string
+	var restartValidator bool
+
+	cmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Stop a sentry and take it out of the validator's peers",
+		Long: `remove deletes the sentry container and reconfigures the validator without
+it. The sentry's node directory is kept. Removing the last sentry makes the
+validator peer publicly again.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sentryRemoveLogic(mynode, name, restartValidator)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Name of the validator node")
+	cmd.Flags().StringVar(&name, "name", "", "Name of the sentry node")
+	cmd.Flags().BoolVar(&restartValidator, "restart-validator", false, "Recreate the validator container with the new peers")
+	requireNode(cmd)
+	cmd.MarkFlagRequired("name")
+	return cmd
+}
+
+func sentryRemoveLogic(validator, name string, restartValidator bool) error {
+	vcfg, err := loadNodeConfig(validator)
+	if err != nil {
+		return err
+	}
+	if vcfg.Sentry == nil || !slices.Contains(vcfg.Sentry.Nodes, name) {
+		return &ConfigError{Msg: name + " is not a sentry of " + validator, Hint: "run 'sentry list --mynode " + validator + "'"}
+	}
+	var sentries []string
+	for _, s := range vcfg.Sentry.Nodes {
+		if s != name {
+			sentries = append(sentries, s)
+		}
+	}
+
+	// Hand the removed sentry's public peers back to the validator when it
+	// loses its last sentry.
+	if len(sentries) == 0 {
+		if cfg, err := loadNodeConfig(name); err == nil {
+			vcfg.Sentry.Nodes = nil
+			vcfg.Network.PersistentPeers = cfg.Network.PersistentPeers
+		}
+	}
+	if err := applySentryTopology(validator, vcfg, sentries); err != nil {
+		return err
+	}
+
+	if err := removeNode(name); err != nil {
+		log.Warnf("⚠️ Could not remove the container %s: %v", nodeContainer(name), err)
+	}
+	if cfg, err := loadNodeConfig(name); err == nil {
+		cfg.Sentry = nil
+		cfg.DockerNetwork = ""
+		if err := saveNodeConfig(name, cfg); err != nil {
+			return err
+		}
+	}
+	log.Infof("🗑️ Removed sentry %s, its files are kept in %s", name, name)
+
+	if restartValidator {
+		return recreateNodeContainer(validator)
+	}
+	log.Infof("💡 Pass --restart-validator, or remove the %s container and run 'start-node --mynode %s', to apply the new peers.", validator, validator)
+	return nil
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// rfc8032NodeKey is a node_key.json holding the key of RFC 8032 test 1; its
+// node id is the first 20 bytes of the SHA-256 of the public key
+// d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a.
+const rfc8032NodeKey = `{"priv_key":{"type":"tendermint/PrivKeyEd25519","value":"nWGxne/9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2DXWpgBgrEKt9VL/tPJZAc6DuFy89qmIyWvAhpo9wdRGg=="}}`
+
+// inTempDir runs the test from an empty directory, where node directories
+// are created as in a workspace.
+func inTempDir(t *testing.T) {
+	t.Helper()
+	saved, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(saved) })
+}
+
+func writeNodeKey(t *testing.T, mynode, nodeKey string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(mynode, "config"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mynode, "config", "node_key.json"), []byte(nodeKey), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNodeID(t *testing.T) {
+	inTempDir(t)
+	writeNodeKey(t, "valid", rfc8032NodeKey)
+	writeNodeKey(t, "short", `{"priv_key":{"value":"AAEC"}}`)
+	writeNodeKey(t, "garbled", `{"priv_key":`)
+
+	tests := []struct {
+		node    string
+		want    string
+		wantErr string
+	}{
+		{node: "valid", want: "21fe31dfa154a261626bf854046fd2271b7bed4b"},
+		{node: "short", wantErr: "does not hold an ed25519 key"},
+		{node: "garbled", wantErr: "failed to parse"},
+		{node: "missing", wantErr: "failed to read"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.node, func(t *testing.T) {
+			got, err := nodeID(tt.node)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("nodeID error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("nodeID = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+// testSentryNode creates a node directory with a node key, a [p2p]
+// section and a node.yaml whose ports start at base.
+func testSentryNode(t *testing.T, name string, seed byte, base int, peers []string) NodeConfig {
+	t.Helper()
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{seed}, ed25519.SeedSize))
+	writeNodeKey(t, name, fmt.Sprintf(`{"priv_key":{"type":"tendermint/PrivKeyEd25519","value":%q}}`, base64.StdEncoding.EncodeToString(key)))
+	if err := os.WriteFile(filepath.Join(name, "config", "config.toml"), []byte("[p2p]\npex = true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := NodeConfig{SchemaVersion: nodeConfigVersion, Image: defaultNodeImage}
+	for i, s := range portServices {
+		sp, _ := cfg.Ports.service(s.Name)
+		*sp = ServicePort{Port: base + i, Bind: bindLocal}
+	}
+	cfg.Ports.P2P.Bind = bindPublic
+	cfg.Network.PersistentPeers = peers
+	if err := saveNodeConfig(name, cfg); err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func p2pValue(t *testing.T, mynode, key string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(mynode, "config", "config.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if k, v, ok := strings.Cut(line, " = "); ok && k == key {
+			return v
+		}
+	}
+	return ""
+}
+
+func TestApplySentryTopology(t *testing.T) {
+	inTempDir(t)
+	pub1 := "1111111111111111111111111111111111111111@203.0.113.1:26656"
+	pub2 := "2222222222222222222222222222222222222222@203.0.113.2:26656"
+	pub3 := "3333333333333333333333333333333333333333@203.0.113.3:26656"
+	vcfg := testSentryNode(t, "val", 1, 26666, []string{pub1, pub2})
+	vcfg.Network.Seeds = []string{pub3}
+	testSentryNode(t, "s1", 2, 26676, []string{pub2, pub3})
+	testSentryNode(t, "s2", 3, 26686, nil)
+
+	valID, _ := nodeID("val")
+	s1ID, _ := nodeID("s1")
+	s2ID, _ := nodeID("s2")
+
+	if err := applySentryTopology("val", vcfg, []string{"s1", "s2"}); err != nil {
+		t.Fatalf("applySentryTopology: %v", err)
+	}
+
+	got, err := readNodeConfig("val")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantPeers := []string{s1ID + "@s1:26676", s2ID + "@s2:26686"}
+	if !slices.Equal(got.Network.PersistentPeers, wantPeers) || len(got.Network.Seeds) != 0 {
+		t.Errorf("validator peers = %v, seeds %v; want %v and no seeds", got.Network.PersistentPeers, got.Network.Seeds, wantPeers)
+	}
+	if got.Ports.P2P.Bind != bindLocal || got.DockerNetwork != "val-sentry" || got.Sentry == nil || !slices.Equal(got.Sentry.Nodes, []string{"s1", "s2"}) {
+		t.Errorf("validator config = %+v", got)
+	}
+	for key, want := range map[string]string{
+		"pex":                    "false",
+		"addr_book_strict":       "false",
+		"persistent_peers":       tomlString(strings.Join(wantPeers, ",")),
+		"private_peer_ids":       tomlString(s1ID + "," + s2ID),
+		"unconditional_peer_ids": tomlString(s1ID + "," + s2ID),
+		"seeds":                  `""`,
+	} {
+		if v := p2pValue(t, "val", key); v != want {
+			t.Errorf("validator %s = %s, want %s", key, v, want)
+		}
+	}
+
+	// Every sentry keeps the validator first, then the public peers of the
+	// validator and of all sentries, without duplicates.
+	validatorPeer := valID + "@val:26666"
+	for _, name := range []string{"s1", "s2"} {
+		got, err := readNodeConfig(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{validatorPeer, pub1, pub2, pub3}
+		if !slices.Equal(got.Network.PersistentPeers, want) {
+			t.Errorf("%s peers = %v, want %v", name, got.Network.PersistentPeers, want)
+		}
+		if got.Sentry == nil || got.Sentry.Validator != "val" || got.DockerNetwork != "val-sentry" {
+			t.Errorf("%s config = %+v", name, got)
+		}
+		if v := p2pValue(t, name, "private_peer_ids"); v != tomlString(valID) {
+			t.Errorf("%s private_peer_ids = %s, want %s", name, v, tomlString(valID))
+		}
+		if v := p2pValue(t, name, "pex"); v != "true" {
+			t.Errorf("%s pex = %s, want true", name, v)
+		}
+	}
+
+	// Removing the last sentry makes the validator public again with the
+	// public peers of that sentry, which sentryRemoveLogic hands back.
+	vcfg, err = readNodeConfig("val")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s1, err := readNodeConfig("s1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vcfg.Sentry.Nodes = nil
+	vcfg.Network.PersistentPeers = s1.Network.PersistentPeers
+	if err := applySentryTopology("val", vcfg, nil); err != nil {
+		t.Fatalf("applySentryTopology without sentries: %v", err)
+	}
+	got, err = readNodeConfig("val")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Sentry != nil || got.DockerNetwork != "" || got.Ports.P2P.Bind != bindPublic {
+		t.Errorf("validator config after removing the sentries = %+v", got)
+	}
+	if want := []string{pub1, pub2, pub3}; !slices.Equal(got.Network.PersistentPeers, want) {
+		t.Errorf("validator peers after removing the sentries = %v, want %v", got.Network.PersistentPeers, want)
+	}
+	if v := p2pValue(t, "val", "private_peer_ids"); v != `""` {
+		t.Errorf("validator private_peer_ids = %s, want empty", v)
+	}
+	if v := p2pValue(t, "val", "pex"); v != "true" {
+		t.Errorf("validator pex = %s, want true", v)
+	}
+}
@@ -0,0 +1,511 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// defaultPrivValidatorState is what a validator that never signed starts
+// from when the node directory had no priv_validator_state.json.
+const defaultPrivValidatorState = `{
+  "height": "0",
+  "round": 0,
+  "step": 0
+}
+`
+
+// SnapshotEntry is one data snapshot of the HTTP index.
+type SnapshotEntry struct {
+	Name      string    `json:"name" yaml:"name"`
+	Height    int64     `json:"height" yaml:"height"`
+	URL       string    `json:"url" yaml:"url"`
+	Size      int64     `json:"size,omitempty" yaml:"size,omitempty"`
+	SHA256    string    `json:"sha256" yaml:"sha256"`
+	CreatedAt time.Time `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+}
+
+// SnapshotIndex is the JSON document served at the snapshot index URL. Entry
+// URLs may be relative to the index.
+type SnapshotIndex struct {
+	Snapshots []SnapshotEntry `json:"snapshots" yaml:"snapshots"`
+}
+
+func (idx SnapshotIndex) renderTable(w io.Writer) {
+	fmt.Fprintln(w, "NAME\tHEIGHT\tSIZE\tCREATED\tURL")
+	for _, s := range idx.Snapshots {
+		created := "-"
+		if !s.CreatedAt.IsZero() {
+			created = s.CreatedAt.Local().Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", s.Name, s.Height, formatBytes(s.Size), created, s.URL)
+	}
+}
+
+// find returns the snapshot called name, or the highest one if name is empty.
+func (idx SnapshotIndex) find(name string) (SnapshotEntry, error) {
+	if len(idx.Snapshots) == 0 {
+		return SnapshotEntry{}, &ChainQueryError{Query: "choose a snapshot", Err: fmt.Errorf("the snapshot index is empty")}
+	}
+	if name == "" {
+		return idx.Snapshots[0], nil
+	}
+	for _, s := range idx.Snapshots {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return SnapshotEntry{}, &ConfigError{Msg: "snapshot " + name + " is not in the index", Hint: "list the available snapshots with 'snapshot list'"}
+}
+
+// snapshotOptions select the snapshot index and entry.
+type snapshotOptions struct {
+	IndexURL string
+	Name     string
+}
+
+func addSnapshotFlags(cmd *cobra.Command, opts *snapshotOptions, withName bool) {
+	cmd.Flags().StringVar(&opts.IndexURL, "index", "", "URL of the snapshot index (default: snapshotIndexUrl of the chain config)")
+	if withName {
+		cmd.Flags().StringVar(&opts.Name, "name", "", "Snapshot to use (default: the highest one)")
+	}
+}
+
+func snapshotCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Download and restore pruned data snapshots",
+		Long: `Snapshots are listed in a JSON index served over HTTP:
+
+  {"snapshots": [{"name": "...", "height": 123, "url": "x.tar.zst", "sha256": "..."}]}
+
+Archives are tar files, optionally compressed with gzip (.tar.gz), zstd
+(.tar.zst) or lz4 (.tar.lz4), holding the node's data directory.`,
+	}
+	cmd.AddCommand(snapshotListCmd(), snapshotDownloadCmd(), snapshotRestoreCmd())
+	return cmd
+}
+
+func snapshotListCmd() *cobra.Command {
+	var opts snapshotOptions
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the snapshots of the index",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			idx, err := loadSnapshotIndex(opts.IndexURL)
+			if err != nil {
+				return err
+			}
+			return render(idx)
+		},
+	}
+	addSnapshotFlags(cmd, &opts, false)
+	return cmd
+}
+
+func snapshotDownloadCmd() *cobra.Command {
+	var mynode string
+	var opts snapshotOptions
+	cmd := &cobra.Command{
+		Use:   "download",
+		Short: "Download a snapshot into <mynode>/snapshots, resuming a partial download",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			_, err := downloadSnapshotLogic(mynode, opts)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	addSnapshotFlags(cmd, &opts, true)
+	return cmd
+}
+
+func snapshotRestoreCmd() *cobra.Command {
+	var mynode string
+	var opts snapshotOptions
+	var file string
+	cmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Replace the node's data directory with a snapshot",
+		Long: `Downloads the snapshot if needed, then replaces <mynode>/data with its contents.
+priv_validator_state.json is backed up first and put back afterwards, so the
+validator never signs below its last signed height. The node must be stopped.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return restoreSnapshotLogic(mynode, opts, file)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	addSnapshotFlags(cmd, &opts, true)
+	cmd.Flags().StringVar(&file, "file", "", "Restore a snapshot archive that is already on disk")
+	return cmd
+}
+
+// loadSnapshotIndex fetches the index and resolves its entry URLs; the
+// snapshots are sorted by height, highest first.
+func loadSnapshotIndex(indexURL string) (SnapshotIndex, error) {
+	var idx SnapshotIndex
+	if indexURL == "" {
+		if err := loadConfigCliParams(); err != nil {
+			return idx, err
+		}
+		indexURL = configCliParams.SnapshotIndexUrl
+	}
+	if indexURL == "" {
+		return idx, &ConfigError{Msg: "no snapshot index configured", Hint: "pass the index URL with --index"}
+	}
+
+	base, err := url.Parse(indexURL)
+	if err != nil {
+		return idx, &ConfigError{Msg: "invalid snapshot index URL", Err: err}
+	}
+	body, err := fetchURL(indexURL)
+	if err != nil {
+		return idx, &ChainQueryError{Query: "fetch the snapshot index", Err: err, Hint: "check the --index URL and the network connection"}
+	}
+	if err := json.Unmarshal(body, &idx); err != nil {
+		return idx, &ChainQueryError{Query: "parse the snapshot index", Err: err}
+	}
+	for i, s := range idx.Snapshots {
+		ref, err := url.Parse(s.URL)
+		if err != nil {
+			return idx, &ChainQueryError{Query: "parse the snapshot index", Err: fmt.Errorf("snapshot %s: %w", s.Name, err)}
+		}
+		idx.Snapshots[i].URL = base.ResolveReference(ref).String()
+		if s.Name == "" {
+			idx.Snapshots[i].Name = path.Base(ref.Path)
+		}
+	}
+	sort.SliceStable(idx.Snapshots, func(i, j int) bool {
+		return idx.Snapshots[i].Height > idx.Snapshots[j].Height
+	})
+	return idx, nil
+}
+
+// downloadSnapshotLogic downloads the selected snapshot and returns the path
+// of the verified archive.
+func downloadSnapshotLogic(mynode string, opts snapshotOptions) (string, error) {
+	idx, err := loadSnapshotIndex(opts.IndexURL)
+	if err != nil {
+		return "", err
+	}
+	entry, err := idx.find(opts.Name)
+	if err != nil {
+		return "", err
+	}
+	if entry.SHA256 == "" {
+		return "", &ConfigError{Msg: "snapshot " + entry.Name + " has no sha256 in the index", Hint: "ask the snapshot provider to publish checksums; unverified snapshots are not restored"}
+	}
+
+	dir := filepath.Join(mynode, "snapshots")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", &RuntimeError{Msg: "failed to create " + dir, Err: err}
+	}
+	u, _ := url.Parse(entry.URL)
+	target := filepath.Join(dir, path.Base(u.Path))
+
+	if exists(target) {
+		if err := verifySHA256(target, entry.SHA256); err == nil {
+			log.Infof("✅ %s is already downloaded and verified.", target)
+			return target, nil
+		}
+		log.Warnf("⚠️ %s does not match its checksum, downloading it again.", target)
+		if err := os.Remove(target); err != nil {
+			return "", &RuntimeError{Msg: "failed to remove " + target, Err: err}
+		}
+	}
+
+	log.Infof("⬇️  Downloading snapshot %s (height %d)", entry.Name, entry.Height)
+	if err := downloadResumable(entry.URL, target+".part"); err != nil {
+		return "", err
+	}
+	if err := verifySHA256(target+".part", entry.SHA256); err != nil {
+		os.Remove(target + ".part")
+		return "", &RuntimeError{Msg: "snapshot checksum verification failed", Err: err, Hint: "run the download again; a corrupted partial file has been removed"}
+	}
+	if err := os.Rename(target+".part", target); err != nil {
+		return "", &RuntimeError{Msg: "failed to move the downloaded snapshot", Err: err}
+	}
+	log.Infof("✅ Snapshot saved to %s (sha256 verified)", target)
+	return target, nil
+}
+
+// downloadResumable downloads rawURL into partPath, continuing from the end
+// of an existing partial file when the server supports range requests.
+func downloadResumable(rawURL, partPath string) error {
+	var offset int64
+	if info, err := os.Stat(partPath); err == nil {
+		offset = info.Size()
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return &ConfigError{Msg: "invalid snapshot URL", Err: err}
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return &RuntimeError{Msg: "snapshot download failed", Err: err, Hint: "run the download again to resume"}
+	}
+	defer resp.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+		log.Infof("↪️  Resuming from %s", formatBytes(offset))
+		flags |= os.O_APPEND
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// The partial file already holds the whole snapshot.
+		return nil
+	case resp.StatusCode == http.StatusOK:
+		if offset > 0 {
+			log.Warnf("⚠️ The server does not support resuming; starting over.")
+		}
+		offset = 0
+		flags |= os.O_TRUNC
+	default:
+		return &RuntimeError{Msg: "snapshot download failed", Err: fmt.Errorf("GET %s: %s", rawURL, resp.Status)}
+	}
+
+	f, err := os.OpenFile(partPath, flags, 0644)
+	if err != nil {
+		return &RuntimeError{Msg: "failed to open " + partPath, Err: err}
+	}
+	total := int64(-1)
+	if resp.ContentLength >= 0 {
+		total = offset + resp.ContentLength
+	}
+	progress := &downloadProgress{done: offset, total: total, interactive: term.IsTerminal(int(os.Stderr.Fd()))}
+	_, err = io.Copy(f, io.TeeReader(resp.Body, progress))
+	progress.finish()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return &RuntimeError{Msg: "snapshot download interrupted", Err: err, Hint: "run the download again to resume"}
+	}
+	return nil
+}
+
+// downloadProgress reports the progress of a download on stderr: a redrawn
+// line on a terminal, a log line every 10% otherwise.
+type downloadProgress struct {
+	done, total  int64
+	interactive  bool
+	lastReported int64
+	lastDraw     time.Time
+}
+
+func (p *downloadProgress) Write(b []byte) (int, error) {
+	p.done += int64(len(b))
+	if p.interactive {
+		if time.Since(p.lastDraw) > 200*time.Millisecond {
+			fmt.Fprintf(os.Stderr, "\r⬇️  %s ", p.describe())
+			p.lastDraw = time.Now()
+		}
+	} else if p.total > 0 && p.done*10/p.total > p.lastReported {
+		p.lastReported = p.done * 10 / p.total
+		log.Infof("⬇️  %s", p.describe())
+	}
+	return len(b), nil
+}
+
+func (p *downloadProgress) describe() string {
+	if p.total <= 0 {
+		return formatBytes(p.done)
+	}
+	return fmt.Sprintf("%5.1f%% (%s of %s)", float64(p.done)*100/float64(p.total), formatBytes(p.done), formatBytes(p.total))
+}
+
+func (p *downloadProgress) finish() {
+	if p.interactive {
+		fmt.Fprintf(os.Stderr, "\r⬇️  %s\n", p.describe())
+	}
+}
+
+func verifySHA256(file, want string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+	got := hex.EncodeToString(h.Sum(nil))
+	if !strings.EqualFold(got, want) {
+		return fmt.Errorf("sha256 of %s is %s, expected %s", file, got, want)
+	}
+	return nil
+}
+
+// isTarArchive reports whether file has an extension decompressSnapshot
+// understands.
+func isTarArchive(file string) bool {
+	name := strings.ToLower(file)
+	for _, ext := range []string{".tar", ".tar.gz", ".tgz", ".tar.zst", ".tzst", ".tar.lz4"} {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// decompressSnapshot returns the tar stream of an archive, picking the
+// decompressor from the file extension.
+func decompressSnapshot(file string, r io.Reader) (io.ReadCloser, error) {
+	name := strings.ToLower(file)
+	switch {
+	case strings.HasSuffix(name, ".tar"):
+		return io.NopCloser(r), nil
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return gzip.NewReader(r)
+	case strings.HasSuffix(name, ".tar.zst"), strings.HasSuffix(name, ".tzst"):
+		dec, err := zstd.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return dec.IOReadCloser(), nil
+	case strings.HasSuffix(name, ".tar.lz4"):
+		return io.NopCloser(lz4.NewReader(r)), nil
+	}
+	return nil, fmt.Errorf("unsupported snapshot format %s: expected .tar, .tar.gz, .tar.zst or .tar.lz4", filepath.Base(file))
+}
+
+// restoreSnapshotLogic replaces the node's data directory with a snapshot,
+// keeping the node's priv_validator_state.json.
+func restoreSnapshotLogic(mynode string, opts snapshotOptions, file string) error {
+	if nodeContainerRunning(mynode) {
+		return &RuntimeError{Msg: "node " + mynode + " is running", Hint: "stop it first with 'stop-node --mynode " + mynode + "'"}
+	}
+
+	if file != "" {
+		file = invocationPath(file)
+	} else {
+		var err error
+		if file, err = downloadSnapshotLogic(mynode, opts); err != nil {
+			return err
+		}
+	}
+
+	dataDir := filepath.Join(mynode, "data")
+	statePath := filepath.Join(dataDir, "priv_validator_state.json")
+	backupPath := filepath.Join(mynode, "priv_validator_state.json.backup")
+
+	// Back up the signing state before anything is touched.
+	state, err := os.ReadFile(statePath)
+	switch {
+	case err == nil:
+		if err := os.WriteFile(backupPath, state, 0600); err != nil {
+			return &RuntimeError{Msg: "failed to back up priv_validator_state.json", Err: err}
+		}
+		log.Infof("🔐 Backed up %s to %s", statePath, backupPath)
+	case os.IsNotExist(err):
+		state = []byte(defaultPrivValidatorState)
+	default:
+		return &RuntimeError{Msg: "failed to read priv_validator_state.json", Err: err}
+	}
+
+	staging := filepath.Join(mynode, "data.restore")
+	if err := os.RemoveAll(staging); err != nil {
+		return &RuntimeError{Msg: "failed to clean " + staging, Err: err}
+	}
+	log.Infof("📦 Extracting %s", file)
+	if err := extractSnapshot(file, staging); err != nil {
+		os.RemoveAll(staging)
+		return &RuntimeError{Msg: "failed to extract the snapshot", Err: err}
+	}
+	// Archives either hold a data/ directory or the contents of one.
+	restored := staging
+	if info, err := os.Stat(filepath.Join(staging, "data")); err == nil && info.IsDir() {
+		restored = filepath.Join(staging, "data")
+	}
+
+	previous := filepath.Join(mynode, "data.pre-restore")
+	if err := os.RemoveAll(previous); err != nil {
+		return &RuntimeError{Msg: "failed to clean " + previous, Err: err}
+	}
+	if exists(dataDir) {
+		if err := os.Rename(dataDir, previous); err != nil {
+			return &RuntimeError{Msg: "failed to move the old data directory aside", Err: err}
+		}
+	}
+	if err := os.Rename(restored, dataDir); err != nil {
+		os.Rename(previous, dataDir)
+		return &RuntimeError{Msg: "failed to move the snapshot into place", Err: err}
+	}
+	if err := os.WriteFile(statePath, state, 0600); err != nil {
+		return &RuntimeError{
+			Msg:  "failed to put priv_validator_state.json back",
+			Err:  err,
+			Hint: "copy " + backupPath + " to " + statePath + " before starting the node",
+		}
+	}
+	os.RemoveAll(staging)
+	if err := os.RemoveAll(previous); err != nil {
+		log.Warnf("⚠️ Could not remove the old data directory %s: %v", previous, err)
+	}
+
+	log.Infof("✅ Snapshot restored into %s; priv_validator_state.json was kept.", dataDir)
+	return nil
+}
+
+// nodeContainerRunning reports whether the node's docker container is up.
+func nodeContainerRunning(mynode string) bool {
+	output, err := runCmdCaptureOutput("docker", "inspect", "-f", "{{.State.Running}}", nodeContainer(mynode))
+	return err == nil && strings.TrimSpace(output) == "true"
+}
+
+func extractSnapshot(file, dir string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	tr, err := decompressSnapshot(file, f)
+	if err != nil {
+		return err
+	}
+	defer tr.Close()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	return extractNodeTar(tr, dir)
+}
+
+// formatBytes formats n bytes with a binary unit.
+func formatBytes(n int64) string {
+	if n <= 0 {
+		return "-"
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// tarGz builds a .tar.gz archive of the given files, keyed by path.
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		hdr := &tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func sha256Hex(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDownloadResumable(t *testing.T) {
+	content := []byte(strings.Repeat("0123456789", 1000))
+
+	tests := []struct {
+		name       string
+		partial    []byte
+		ranges     bool
+		wantStatus int
+	}{
+		{name: "fresh download", ranges: true, wantStatus: http.StatusOK},
+		{name: "resume with 206", partial: content[:4000], ranges: true, wantStatus: http.StatusPartialContent},
+		{name: "already complete with 416", partial: content, ranges: true, wantStatus: http.StatusRequestedRangeNotSatisfiable},
+		{name: "server without ranges starts over", partial: []byte("stale bytes"), ranges: false, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var gotStatus int
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				rec := &statusRecorder{ResponseWriter: w}
+				if tt.ranges {
+					http.ServeContent(rec, r, "snapshot.tar.gz", time.Time{}, bytes.NewReader(content))
+				} else {
+					rec.Write(content)
+				}
+				mu.Lock()
+				gotStatus = rec.status
+				mu.Unlock()
+			}))
+			defer srv.Close()
+
+			part := filepath.Join(t.TempDir(), "snapshot.tar.gz.part")
+			if tt.partial != nil {
+				if err := os.WriteFile(part, tt.partial, 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := downloadResumable(srv.URL+"/snapshot.tar.gz", part); err != nil {
+				t.Fatalf("downloadResumable: %v", err)
+			}
+			// Close waits for the handler to finish.
+			srv.Close()
+			mu.Lock()
+			status := gotStatus
+			mu.Unlock()
+			if status != tt.wantStatus {
+				t.Errorf("server answered %d, want %d", status, tt.wantStatus)
+			}
+			got, err := os.ReadFile(part)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, content) {
+				t.Errorf("downloaded %d bytes, want the %d bytes of the snapshot", len(got), len(content))
+			}
+		})
+	}
+}
+
+func TestDownloadResumableHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	err := downloadResumable(srv.URL+"/missing.tar.gz", filepath.Join(t.TempDir(), "missing.part"))
+	var runtimeErr *RuntimeError
+	if !errors.As(err, &runtimeErr) {
+		t.Fatalf("error = %v, want a RuntimeError", err)
+	}
+}
+
+// statusRecorder remembers the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// snapshotServer serves an index with one snapshot whose published checksum
+// is sum.
+func snapshotServer(t *testing.T, archive []byte, sum string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/index.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(SnapshotIndex{Snapshots: []SnapshotEntry{
+			{Name: "snap-100", Height: 100, URL: "files/snap-100.tar.gz", SHA256: sum},
+		}})
+	})
+	mux.HandleFunc("/files/snap-100.tar.gz", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "snap-100.tar.gz", time.Time{}, bytes.NewReader(archive))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDownloadSnapshotVerifiesChecksum(t *testing.T) {
+	archive := tarGz(t, map[string]string{"data/blockstore.db/000001.log": "blocks"})
+
+	t.Run("match", func(t *testing.T) {
+		srv := snapshotServer(t, archive, sha256Hex(archive))
+		mynode := filepath.Join(t.TempDir(), "node1")
+
+		file, err := downloadSnapshotLogic(mynode, snapshotOptions{IndexURL: srv.URL + "/index.json"})
+		if err != nil {
+			t.Fatalf("downloadSnapshotLogic: %v", err)
+		}
+		if want := filepath.Join(mynode, "snapshots", "snap-100.tar.gz"); file != want {
+			t.Errorf("file = %s, want %s", file, want)
+		}
+		if exists(file + ".part") {
+			t.Errorf("%s.part was left behind", file)
+		}
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		srv := snapshotServer(t, archive, sha256Hex([]byte("something else")))
+		mynode := filepath.Join(t.TempDir(), "node1")
+
+		_, err := downloadSnapshotLogic(mynode, snapshotOptions{IndexURL: srv.URL + "/index.json"})
+		var runtimeErr *RuntimeError
+		if !errors.As(err, &runtimeErr) || !strings.Contains(err.Error(), "checksum") {
+			t.Fatalf("error = %v, want a checksum RuntimeError", err)
+		}
+		target := filepath.Join(mynode, "snapshots", "snap-100.tar.gz")
+		if exists(target) || exists(target+".part") {
+			t.Errorf("an unverified snapshot was kept in %s", filepath.Dir(target))
+		}
+	})
+
+	t.Run("missing checksum", func(t *testing.T) {
+		srv := snapshotServer(t, archive, "")
+		_, err := downloadSnapshotLogic(filepath.Join(t.TempDir(), "node1"), snapshotOptions{IndexURL: srv.URL + "/index.json"})
+		var configErr *ConfigError
+		if !errors.As(err, &configErr) {
+			t.Fatalf("error = %v, want a ConfigError", err)
+		}
+	})
+}
+
+func TestRestoreSnapshotKeepsPrivValidatorState(t *testing.T) {
+	const signingState = `{"height":"12345","round":0,"step":3}`
+
+	// Archives either hold a data/ directory or the contents of one.
+	layouts := []struct {
+		name   string
+		prefix string
+	}{
+		{name: "data directory", prefix: "data/"},
+		{name: "flat", prefix: ""},
+	}
+	for _, layout := range layouts {
+		t.Run(layout.name, func(t *testing.T) {
+			mynode := filepath.Join(t.TempDir(), "node1")
+			dataDir := filepath.Join(mynode, "data")
+			if err := os.MkdirAll(filepath.Join(dataDir, "old.db"), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(filepath.Join(dataDir, "priv_validator_state.json"), []byte(signingState), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			archive := filepath.Join(t.TempDir(), "snap.tar.gz")
+			if err := os.WriteFile(archive, tarGz(t, map[string]string{
+				layout.prefix + "blockstore.db/000001.log":  "blocks",
+				layout.prefix + "priv_validator_state.json": `{"height":"1","round":0,"step":0}`,
+			}), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := restoreSnapshotLogic(mynode, snapshotOptions{}, archive); err != nil {
+				t.Fatalf("restoreSnapshotLogic: %v", err)
+			}
+
+			state, err := os.ReadFile(filepath.Join(dataDir, "priv_validator_state.json"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(state) != signingState {
+				t.Errorf("priv_validator_state.json = %s, want the node's own %s", state, signingState)
+			}
+			backup, err := os.ReadFile(filepath.Join(mynode, "priv_validator_state.json.backup"))
+			if err != nil || string(backup) != signingState {
+				t.Errorf("backup = %q (%v), want %s", backup, err, signingState)
+			}
+			if !exists(filepath.Join(dataDir, "blockstore.db", "000001.log")) {
+				t.Errorf("the snapshot was not restored into %s", dataDir)
+			}
+			if exists(filepath.Join(dataDir, "old.db")) {
+				t.Errorf("the old data directory was not replaced")
+			}
+			for _, leftover := range []string{"data.restore", "data.pre-restore"} {
+				if exists(filepath.Join(mynode, leftover)) {
+					t.Errorf("%s was left behind", leftover)
+				}
+			}
+		})
+	}
+}
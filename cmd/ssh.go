@@ -0,0 +1,427 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// RemoteHost is where a remote node runs. Dir is the workspace on that host:
+// the directory holding the node home, ethermintd and the global .env, laid
+// out like a local workspace.
+type RemoteHost struct {
+	Host       string `json:"host" yaml:"host"`
+	Port       int    `json:"port,omitempty" yaml:"port,omitempty"`
+	User       string `json:"user" yaml:"user"`
+	KeyFile    string `json:"key_file,omitempty" yaml:"key_file,omitempty"`
+	KnownHosts string `json:"known_hosts,omitempty" yaml:"known_hosts,omitempty"`
+	Dir        string `json:"dir" yaml:"dir"`
+}
+
+func (r RemoteHost) address() string {
+	port := r.Port
+	if port == 0 {
+		port = 22
+	}
+	return net.JoinHostPort(r.Host, strconv.Itoa(port))
+}
+
+// sshTransport runs commands in the remote workspace. Commands run without a
+// terminal, so remote steps cannot prompt for input.
+type sshTransport struct {
+	remote RemoteHost
+
+	mu     sync.Mutex
+	client *ssh.Client
+}
+
+func newSSHTransport(remote RemoteHost) *sshTransport {
+	return &sshTransport{remote: remote}
+}
+
+func (t *sshTransport) Run(stdin io.Reader, stdout, stderr io.Writer, name string, args ...string) error {
+	session, err := t.session()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	session.Stdout = stdout
+	session.Stderr = stderr
+	// os.Stdin is never forwarded: the copy would block until the local
+	// terminal is closed.
+	if stdin != nil && stdin != os.Stdin {
+		session.Stdin = stdin
+	}
+	return session.Run(t.remoteCommand(name, args...))
+}
+
+func (t *sshTransport) WorkingDir() (string, error) {
+	return t.remote.Dir, nil
+}
+
+func (t *sshTransport) WriteTemp(pattern string, data []byte) (string, error) {
+	// mktemp only fills in trailing Xs, so the suffix of the pattern is dropped.
+	prefix, _, _ := strings.Cut(pattern, "*")
+	script := `f=$(mktemp "${TMPDIR:-/tmp}"/` + shellQuote(prefix+"XXXXXX") + `) && cat > "$f" && printf %s "$f"`
+	var stdout, stderr syncBuffer
+	if err := t.Run(bytes.NewReader(data), &stdout, &stderr, "sh", "-c", script); err != nil {
+		return "", fmt.Errorf("failed to write a temporary file on %s: %w: %s", t.remote.Host, err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+func (t *sshTransport) ReadFile(path string) ([]byte, error) {
+	var stdout bytes.Buffer
+	var stderr syncBuffer
+	if err := t.Run(nil, &stdout, &stderr, "cat", path); err != nil {
+		return nil, fmt.Errorf("failed to read %s on %s: %w: %s", path, t.remote.Host, err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+func (t *sshTransport) Remove(path string) error {
+	return t.Run(nil, io.Discard, io.Discard, "rm", "-f", path)
+}
+
+func (t *sshTransport) remoteCommand(name string, args ...string) string {
+	quoted := []string{shellQuote(name)}
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	return "cd " + shellQuote(t.remote.Dir) + " && " + strings.Join(quoted, " ")
+}
+
+func (t *sshTransport) session() (*ssh.Session, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.client == nil {
+		client, err := dialRemote(t.remote)
+		if err != nil {
+			return nil, err
+		}
+		t.client = client
+	}
+	session, err := t.client.NewSession()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ssh session to %s: %w", t.remote.Host, err)
+	}
+	return session, nil
+}
+
+func dialRemote(remote RemoteHost) (*ssh.Client, error) {
+	knownHostsPath := remote.KnownHosts
+	if knownHostsPath == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("could not get user home directory: %w", err)
+		}
+		knownHostsPath = filepath.Join(homeDir, ".ssh", "known_hosts")
+	}
+	hostKeyCallback, err := knownhosts.New(knownHostsPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known_hosts %s (add the host with ssh-keyscan first): %w", knownHostsPath, err)
+	}
+
+	auth, err := sshAuthMethods(remote)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &ssh.ClientConfig{
+		User:            remote.User,
+		Auth:            auth,
+		HostKeyCallback: hostKeyCallback,
+	}
+	client, err := ssh.Dial("tcp", remote.address(), config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s@%s: %w", remote.User, remote.address(), err)
+	}
+	return client, nil
+}
+
+// sshAuthMethods uses the configured key file and falls back to ssh-agent,
+// which is also how passphrase protected keys are supported.
+func sshAuthMethods(remote RemoteHost) ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+	if remote.KeyFile != "" {
+		key, err := os.ReadFile(remote.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ssh key %s: %w", remote.KeyFile, err)
+		}
+		signer, err := ssh.ParsePrivateKey(key)
+		var missing *ssh.PassphraseMissingError
+		switch {
+		case errors.As(err, &missing):
+			log.Debugf("ssh key %s is passphrase protected, using ssh-agent", remote.KeyFile)
+		case err != nil:
+			return nil, fmt.Errorf("failed to parse ssh key %s: %w", remote.KeyFile, err)
+		default:
+			methods = append(methods, ssh.PublicKeys(signer))
+		}
+	}
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("no ssh credentials for %s: set --ssh-key or run ssh-agent", remote.Host)
+	}
+	return methods, nil
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:@,+", r))
+	}) == -1 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+func logsCmd() *cobra.Command {
+	var mynode string
+	var follow bool
+	var tail string
+
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Show the logs of the node (local or remote)",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if nodeRuntime(mynode) == runtimeNative {
+				lines := tail
+				if lines == "all" {
+					lines = "+1"
+				}
+				tailArgs := []string{"-n", lines}
+				if follow {
+					tailArgs = append(tailArgs, "-f")
+				}
+				return transport.Run(nil, os.Stdout, os.Stderr, "tail", append(tailArgs, filepath.ToSlash(filepath.Join(mynode, nativeLogFile)))...)
+			}
+			dockerArgs := []string{"logs", "--tail", tail}
+			if follow {
+				dockerArgs = append(dockerArgs, "--follow")
+			}
+			// Logs are the result of this command, so they go to stdout.
+			return transport.Run(nil, os.Stdout, os.Stderr, "docker", append(dockerArgs, nodeContainer(mynode))...)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep streaming new log lines")
+	cmd.Flags().StringVar(&tail, "tail", "100", "Number of lines to show from the end of the logs (or \"all\")")
+	return cmd
+}
+
+func nodesSyncCmd() *cobra.Command {
+	var pull bool
+	var includeData bool
+
+	cmd := &cobra.Command{
+		Use:   "sync [name]",
+		Short: "Copy the node home and .env files to (or --pull from) a remote node",
+		Long: `Pushes the local copy of the node home and the workspace .env to the remote
+workspace, or pulls them from it with --pull. The chain data directory is
+skipped unless --include-data is given.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return nodesSyncCmdLogic(args[0], pull, includeData)
+		},
+	}
+	cmd.Flags().BoolVar(&pull, "pull", false, "Copy from the remote host to this machine")
+	cmd.Flags().BoolVar(&includeData, "include-data", false, "Also copy the chain data directory")
+	return cmd
+}
+
+func nodesSyncCmdLogic(name string, pull, includeData bool) error {
+	registry, err := loadNodeRegistry()
+	if err != nil {
+		return err
+	}
+	entry, ok := registry.Find(name)
+	if !ok {
+		return fmt.Errorf("node %s is not registered", name)
+	}
+	if entry.Remote == nil {
+		return fmt.Errorf("node %s is not a remote node", name)
+	}
+
+	t := newSSHTransport(*entry.Remote)
+	dirName := filepath.Base(entry.Home)
+	if pull {
+		err = pullNodeFiles(t, entry.Workspace(), dirName, includeData)
+	} else {
+		err = pushNodeFiles(t, entry.Workspace(), dirName, includeData)
+	}
+	if err != nil {
+		return err
+	}
+
+	entry.Ports = readNodePorts(entry.Home)
+	registry.Put(entry)
+	if err := saveNodeRegistry(registry); err != nil {
+		return err
+	}
+	log.Infof("✅ Node %s synced with %s:%s", name, entry.Remote.Host, entry.Remote.Dir)
+	return nil
+}
+
+// pushNodeFiles streams a tar of the node home and the workspace .env into
+// tar on the remote host.
+func pushNodeFiles(t *sshTransport, workspace, dirName string, includeData bool) error {
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeNodeTar(pw, workspace, dirName, includeData))
+	}()
+
+	var stderr syncBuffer
+	err := t.Run(pr, io.Discard, &stderr, "sh", "-c", "mkdir -p "+shellQuote(t.remote.Dir)+" && tar -xf - -C "+shellQuote(t.remote.Dir))
+	pr.Close()
+	if err != nil {
+		return fmt.Errorf("failed to push node files: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func writeNodeTar(w io.Writer, workspace, dirName string, includeData bool) error {
+	tw := tar.NewWriter(w)
+	paths := []string{dirName}
+	if exists(filepath.Join(workspace, ".env")) {
+		paths = append(paths, ".env")
+	}
+	for _, root := range paths {
+		err := filepath.WalkDir(filepath.Join(workspace, root), func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(workspace, p)
+			if err != nil {
+				return err
+			}
+			if d.IsDir() && !includeData && rel == filepath.Join(dirName, "data") {
+				return filepath.SkipDir
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() && !info.IsDir() {
+				return nil
+			}
+			hdr, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			hdr.Name = filepath.ToSlash(rel)
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			f, err := os.Open(p)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			_, err = io.Copy(tw, f)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// pullNodeFiles runs tar on the remote host and unpacks it into the local
+// workspace.
+func pullNodeFiles(t *sshTransport, workspace, dirName string, includeData bool) error {
+	script := "tar -cf -"
+	if !includeData {
+		script += " --exclude=" + shellQuote(dirName+"/data")
+	}
+	script += " " + shellQuote(dirName) + ` $([ -f .env ] && echo .env)`
+
+	pr, pw := io.Pipe()
+	errc := make(chan error, 1)
+	go func() {
+		err := extractNodeTar(pr, workspace)
+		// tar pads the archive past its end marker; drain it so the remote
+		// side can finish writing.
+		io.Copy(io.Discard, pr)
+		errc <- err
+	}()
+
+	var stderr syncBuffer
+	err := t.Run(nil, pw, &stderr, "sh", "-c", script)
+	pw.Close()
+	if extractErr := <-errc; extractErr != nil && err == nil {
+		err = extractErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to pull node files: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func extractNodeTar(r io.Reader, workspace string) error {
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return fmt.Errorf("refusing to extract %q outside the workspace", hdr.Name)
+		}
+		target := filepath.Join(workspace, filepath.FromSlash(name))
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode).Perm())
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		}
+	}
+}
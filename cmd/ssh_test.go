@@ -0,0 +1,133 @@
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "ethermintd", want: "ethermintd"},
+		{in: "./node1/config.toml", want: "./node1/config.toml"},
+		{in: "--home=node1", want: "--home=node1"},
+		{in: "user@host:26657", want: "user@host:26657"},
+		{in: "", want: "''"},
+		{in: "two words", want: "'two words'"},
+		{in: "it's", want: `'it'"'"'s'`},
+		{in: "$(rm -rf ~)", want: "'$(rm -rf ~)'"},
+		{in: "a;b", want: "'a;b'"},
+		{in: "*", want: "'*'"},
+		{in: "line\nbreak", want: "'line\nbreak'"},
+	}
+	for _, tt := range tests {
+		if got := shellQuote(tt.in); got != tt.want {
+			t.Errorf("shellQuote(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestShellQuoteRoundTrip(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("no POSIX shell available")
+	}
+	for _, in := range []string{"plain", "", "it's", `"double" and 'single'`, "$HOME `id` \\ !", "tab\tand\nnewline"} {
+		out, err := exec.Command(sh, "-c", "printf %s "+shellQuote(in)).Output()
+		if err != nil {
+			t.Fatalf("sh -c with %q: %v", in, err)
+		}
+		if string(out) != in {
+			t.Errorf("the shell read %q back as %q", in, out)
+		}
+	}
+}
+
+// nodeTar builds an uncompressed tar stream of the given headers; regular
+// files get their name as content.
+func nodeTar(t *testing.T, headers ...tar.Header) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, hdr := range headers {
+		if hdr.Typeflag == tar.TypeReg {
+			hdr.Size = int64(len(hdr.Name))
+		}
+		if hdr.Mode == 0 {
+			hdr.Mode = 0644
+		}
+		if err := tw.WriteHeader(&hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(hdr.Name)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &buf
+}
+
+func TestExtractNodeTar(t *testing.T) {
+	workspace := t.TempDir()
+	err := extractNodeTar(nodeTar(t,
+		tar.Header{Name: "node1/", Typeflag: tar.TypeDir, Mode: 0755},
+		tar.Header{Name: "node1/config/config.toml", Typeflag: tar.TypeReg},
+		tar.Header{Name: "node1/./data/../.env", Typeflag: tar.TypeReg},
+		tar.Header{Name: "node1/link", Typeflag: tar.TypeSymlink, Linkname: "/etc"},
+	), workspace)
+	if err != nil {
+		t.Fatalf("extractNodeTar: %v", err)
+	}
+
+	for _, name := range []string{"node1/config/config.toml", "node1/./data/../.env"} {
+		got, err := os.ReadFile(filepath.Join(workspace, filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("%s was not extracted: %v", name, err)
+		} else if string(got) != name {
+			t.Errorf("%s = %q, want %q", name, got, name)
+		}
+	}
+	if _, err := os.Lstat(filepath.Join(workspace, "node1", "link")); !os.IsNotExist(err) {
+		t.Errorf("a symlink was extracted (err = %v)", err)
+	}
+}
+
+func TestExtractNodeTarRefusesTraversal(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry string
+	}{
+		{name: "parent directory", entry: "../escape"},
+		{name: "nested parent directory", entry: "node1/../../escape"},
+		{name: "bare parent", entry: ".."},
+		{name: "absolute path", entry: "/tmp/escape"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			workspace := filepath.Join(root, "workspace")
+			if err := os.Mkdir(workspace, 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			err := extractNodeTar(nodeTar(t, tar.Header{Name: tt.entry, Typeflag: tar.TypeReg}), workspace)
+			if err == nil || !strings.Contains(err.Error(), "outside the workspace") {
+				t.Fatalf("extractNodeTar(%q) error = %v, want a refusal", tt.entry, err)
+			}
+			if exists(filepath.Join(root, "escape")) {
+				t.Errorf("%q was written outside the workspace", tt.entry)
+			}
+		})
+	}
+}
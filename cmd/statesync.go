@@ -0,0 +1,251 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+// stateSyncOptions configure the [statesync] section of config.toml.
+type stateSyncOptions struct {
+	Enabled     bool
+	RPCServers  []string
+	TrustPeriod time.Duration
+	TrustOffset int64
+}
+
+func addStateSyncFlags(cmd *cobra.Command, opts *stateSyncOptions) {
+	cmd.Flags().BoolVar(&opts.Enabled, "state-sync", false, "Bootstrap the node from a state-sync snapshot instead of replaying the chain from genesis")
+	cmd.Flags().StringSliceVar(&opts.RPCServers, "state-sync-rpc", nil, "RPC servers to take the trusted height and hash from (default: the chain config list, else the boot node and a reachable peer of it)")
+	cmd.Flags().DurationVar(&opts.TrustPeriod, "trust-period", 168*time.Hour, "State-sync trust period; must be shorter than the unbonding period")
+	cmd.Flags().Int64Var(&opts.TrustOffset, "trust-offset", 2000, "How many blocks below the latest height the trusted block is taken")
+}
+
+// stateSyncRPCServers returns the RPC servers to use: the flag, else the
+// list of the chain config, else the boot node and the first of its peers
+// whose RPC server answers.
+func stateSyncRPCServers(mynode string, opts stateSyncOptions) []string {
+	if len(opts.RPCServers) > 0 {
+		return opts.RPCServers
+	}
+	if len(configCliParams.StateSyncRpcServers) > 0 {
+		return configCliParams.StateSyncRpcServers
+	}
+	bootRpc := configCliParams.BootNodeRpc
+	if bootRpc == "" {
+		env, _ := godotenv.Read(filepath.Join(mynode, ".env"))
+		bootRpc = env["BOOT_NODE_RPC"]
+	}
+	if bootRpc == "" {
+		return nil
+	}
+	log.Infof("🔎 Looking for a second RPC server among the peers of %s", bootRpc)
+	return append([]string{bootRpc}, peerRPCServers(bootRpc, 1)...)
+}
+
+// peerRPCServers returns up to max RPC servers of the peers of server that
+// answer. A peer's RPC server is taken to listen at the address the server
+// sees it from, on the port of its advertised rpc_address; peers whose RPC
+// only listens on loopback are skipped.
+func peerRPCServers(server string, max int) []string {
+	var info netInfoResponse
+	if err := rpcGetJSON(server, "/net_info", &info); err != nil {
+		log.Warnf("⚠️ Could not list the peers of %s: %v", server, err)
+		return nil
+	}
+	var servers []string
+	for _, p := range info.Result.Peers {
+		if len(servers) >= max {
+			break
+		}
+		host, port, err := net.SplitHostPort(strings.TrimPrefix(p.NodeInfo.Other.RPCAddress, "tcp://"))
+		if err != nil || p.RemoteIP == "" {
+			continue
+		}
+		if ip := net.ParseIP(host); host == "localhost" || (ip != nil && ip.IsLoopback()) {
+			continue
+		}
+		candidate := "http://" + net.JoinHostPort(p.RemoteIP, port)
+		if _, err := fetchBlockHeight(candidate); err != nil {
+			log.Debugf("Peer RPC server %s does not answer: %v", candidate, err)
+			continue
+		}
+		servers = append(servers, candidate)
+	}
+	return servers
+}
+
+// configureStateSync picks a trusted block below the latest height, checks
+// that the RPC servers agree on its hash and writes the [statesync] section
+// of the node's config.toml.
+func configureStateSync(mynode string, opts stateSyncOptions) error {
+	servers := stateSyncRPCServers(mynode, opts)
+	if len(servers) < 2 {
+		return &ConfigError{
+			Msg:  fmt.Sprintf("state sync needs at least two RPC servers, got %d", len(servers)),
+			Hint: "by default a peer of the boot node that exposes its RPC server is added; otherwise pass the servers with --state-sync-rpc http://rpc1:26657,http://rpc2:26657",
+		}
+	}
+	if opts.TrustOffset < 1 {
+		return &usageError{Err: fmt.Errorf("--trust-offset must be at least 1")}
+	}
+
+	latest, err := fetchBlockHeight(servers[0])
+	if err != nil {
+		return &ChainQueryError{Query: "get the latest height from " + servers[0], Err: err}
+	}
+	trustHeight := latest - opts.TrustOffset
+	if trustHeight < 1 {
+		trustHeight = 1
+	}
+
+	trustHash, agreeing, err := agreedBlockHash(servers, trustHeight)
+	if err != nil {
+		return err
+	}
+	log.Infof("🔒 Trusted block %d (%s), confirmed by %d RPC servers", trustHeight, trustHash, agreeing)
+
+	urls := make([]string, len(servers))
+	for i, server := range servers {
+		urls[i] = rpcHTTPURL(server)
+	}
+	configPath := filepath.Join(mynode, "config", "config.toml")
+	values := map[string]string{
+		"enable":       tomlBool(true),
+		"rpc_servers":  tomlString(strings.Join(urls, ",")),
+		"trust_height": strconv.FormatInt(trustHeight, 10),
+		"trust_hash":   tomlString(trustHash),
+		"trust_period": tomlString(opts.TrustPeriod.String()),
+	}
+	if err := setTomlValues(configPath, "statesync", values, "enable", "rpc_servers", "trust_height", "trust_hash", "trust_period"); err != nil {
+		return err
+	}
+
+	if exists(filepath.Join(mynode, "data", "blockstore.db")) {
+		log.Warnf("⚠️ %s already has blocks; state sync only runs on an empty data directory. Reset the node data before starting it.", mynode)
+	}
+	log.Infof("✅ State sync configured in %s", configPath)
+	return nil
+}
+
+// agreedBlockHash fetches the hash of the block at height from every server
+// and fails unless at least two servers answered and all answers match.
+func agreedBlockHash(servers []string, height int64) (string, int, error) {
+	hashes := map[string][]string{}
+	for _, server := range servers {
+		hash, err := fetchBlockHash(server, height)
+		if err != nil {
+			log.Warnf("⚠️ Could not get block %d from %s: %v", height, server, err)
+			continue
+		}
+		hashes[hash] = append(hashes[hash], server)
+	}
+
+	if len(hashes) > 1 {
+		var answers []string
+		for hash, from := range hashes {
+			answers = append(answers, fmt.Sprintf("%s from %s", hash, strings.Join(from, ", ")))
+		}
+		return "", 0, &ChainQueryError{
+			Query: fmt.Sprintf("verify the hash of block %d", height),
+			Err:   fmt.Errorf("RPC servers disagree: %s", strings.Join(answers, "; ")),
+			Hint:  "one of the RPC servers is on another chain or fork; remove it from --state-sync-rpc",
+		}
+	}
+	for hash, from := range hashes {
+		if len(from) >= 2 {
+			return hash, len(from), nil
+		}
+	}
+	return "", 0, &ChainQueryError{
+		Query: fmt.Sprintf("verify the hash of block %d", height),
+		Err:   fmt.Errorf("fewer than two RPC servers answered"),
+		Hint:  "make sure at least two of the state-sync RPC servers are reachable",
+	}
+}
+
+// rpcBlockResponse is the part of the CometBFT /block response we need.
+type rpcBlockResponse struct {
+	Result struct {
+		BlockID struct {
+			Hash string `json:"hash"`
+		} `json:"block_id"`
+		Block struct {
+			Header struct {
+				Height string `json:"height"`
+			} `json:"header"`
+		} `json:"block"`
+	} `json:"result"`
+	Error *struct {
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	} `json:"error"`
+}
+
+func fetchBlock(server string, height int64) (rpcBlockResponse, error) {
+	var block rpcBlockResponse
+	url := rpcHTTPURL(server) + "/block"
+	if height > 0 {
+		url += "?height=" + strconv.FormatInt(height, 10)
+	}
+	client := &http.Client{Timeout: 15 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return block, err
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(&block); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return block, fmt.Errorf("%s returned %s", url, resp.Status)
+		}
+		return block, fmt.Errorf("invalid response from %s: %w", url, err)
+	}
+	if block.Error != nil {
+		return block, fmt.Errorf("%s: %s", block.Error.Message, block.Error.Data)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return block, fmt.Errorf("%s returned %s", url, resp.Status)
+	}
+	return block, nil
+}
+
+func fetchBlockHeight(server string) (int64, error) {
+	block, err := fetchBlock(server, 0)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(block.Result.Block.Header.Height, 10, 64)
+}
+
+func fetchBlockHash(server string, height int64) (string, error) {
+	block, err := fetchBlock(server, height)
+	if err != nil {
+		return "", err
+	}
+	if block.Result.BlockID.Hash == "" {
+		return "", fmt.Errorf("no block hash in the response")
+	}
+	return block.Result.BlockID.Hash, nil
+}
+
+// rpcHTTPURL turns a node address such as tcp://host:26657 into the http URL
+// of its RPC server.
+func rpcHTTPURL(server string) string {
+	server = strings.TrimRight(server, "/")
+	if rest, ok := strings.CutPrefix(server, "tcp://"); ok {
+		return "http://" + rest
+	}
+	if !strings.Contains(server, "://") {
+		return "http://" + server
+	}
+	return server
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTomlString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "168h0m0s", want: `"168h0m0s"`},
+		{in: `say "hi"`, want: `"say \"hi\""`},
+		{in: `C:\node`, want: `"C:\\node"`},
+		{in: "a\tb\nc\r", want: `"a\tb\nc\r"`},
+		{in: "bell\a vt\v", want: `"bell\u0007 vt\u000B"`},
+		{in: "del\x7f", want: `"del\u007F"`},
+		{in: "naïve ✓", want: `"naïve ✓"`},
+	}
+	for _, tt := range tests {
+		if got := tomlString(tt.in); got != tt.want {
+			t.Errorf("tomlString(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPeerRPCServers(t *testing.T) {
+	// The peer answers /block like a CometBFT RPC server.
+	peer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"result":{"block_id":{"hash":"ABC"},"block":{"header":{"height":"42"}}}}`)
+	}))
+	defer peer.Close()
+	_, peerPort, _ := net.SplitHostPort(peer.Listener.Addr().String())
+
+	// A port nothing listens on.
+	closed := httptest.NewServer(http.NotFoundHandler())
+	_, closedPort, _ := net.SplitHostPort(closed.Listener.Addr().String())
+	closed.Close()
+
+	boot := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/net_info" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"result":{"peers":[
+			{"node_info":{"id":"a","other":{"rpc_address":"tcp://127.0.0.1:%[1]s"}},"remote_ip":"127.0.0.1"},
+			{"node_info":{"id":"b","other":{"rpc_address":"tcp://0.0.0.0:%[2]s"}},"remote_ip":"127.0.0.1"},
+			{"node_info":{"id":"c","other":{"rpc_address":""}},"remote_ip":"127.0.0.1"},
+			{"node_info":{"id":"d","other":{"rpc_address":"tcp://0.0.0.0:%[1]s"}},"remote_ip":"127.0.0.1"}
+		]}}`, peerPort, closedPort)
+	}))
+	defer boot.Close()
+
+	got := peerRPCServers(boot.URL, 1)
+	want := "http://" + net.JoinHostPort("127.0.0.1", peerPort)
+	if len(got) != 1 || got[0] != want {
+		t.Fatalf("peerRPCServers = %v, want [%s]", got, want)
+	}
+	if height, err := fetchBlockHeight(got[0]); err != nil || height != 42 {
+		t.Errorf("fetchBlockHeight(%s) = %d, %v", got[0], height, err)
+	}
+
+	if got := peerRPCServers(closed.URL, 1); len(got) != 0 {
+		t.Errorf("peerRPCServers of an unreachable server = %v, want none", got)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+// Transport runs the ethermintd and docker commands of a node, either on
+// this machine or on the node's remote host.
+type Transport interface {
+	// Run executes name with args in the node workspace.
+	Run(stdin io.Reader, stdout, stderr io.Writer, name string, args ...string) error
+	// WorkingDir is the node workspace as seen by the commands.
+	WorkingDir() (string, error)
+	// WriteTemp writes data to a new temporary file on the machine that runs
+	// the commands and returns its path. pattern is as for os.CreateTemp.
+	WriteTemp(pattern string, data []byte) (string, error)
+	// ReadFile reads a file written by a command.
+	ReadFile(path string) ([]byte, error)
+	// Remove deletes a file, if it exists.
+	Remove(path string) error
+}
+
+// transport is selected by applyNodeFlag from the node registry.
+var transport Transport = localTransport{}
+
+type localTransport struct{}
+
+func (localTransport) Run(stdin io.Reader, stdout, stderr io.Writer, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return cmd.Run()
+}
+
+func (localTransport) WorkingDir() (string, error) {
+	return os.Getwd()
+}
+
+func (localTransport) WriteTemp(pattern string, data []byte) (string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), f.Close()
+}
+
+func (localTransport) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func (localTransport) Remove(path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// nodeCmd is the subset of exec.Cmd used to read a command's output.
+type nodeCmd struct {
+	name string
+	args []string
+}
+
+// nodeCommand is exec.Command for commands that must run where the node
+// lives.
+func nodeCommand(name string, args ...string) *nodeCmd {
+	return &nodeCmd{name: name, args: args}
+}
+
+// Output runs the command and returns its standard output.
+func (c *nodeCmd) Output() ([]byte, error) {
+	var stdout bytes.Buffer
+	err := transport.Run(nil, &stdout, io.Discard, c.name, c.args...)
+	return stdout.Bytes(), err
+}
+
+// syncBuffer is a bytes.Buffer that stdout and stderr can share while they
+// are copied concurrently.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
@@ -0,0 +1,520 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+// The node home holds a cosmovisor-compatible layout:
+//
+//	<mynode>/cosmovisor/genesis/bin/ethermintd
+//	<mynode>/cosmovisor/upgrades/<name>/bin/ethermintd
+//	<mynode>/cosmovisor/current -> upgrades/<name>
+//
+// start-node runs the binary of current once an upgrade has been switched to;
+// until then the binary of the Docker image is used.
+const (
+	cosmovisorDir      = "cosmovisor"
+	upgradePlanFile    = "plan.json"
+	upgradeInfoFile    = "upgrade-info.json"
+	chainBinaryName    = "ethermintd"
+	msgSoftwareUpgrade = "/cosmos.upgrade.v1beta1.MsgSoftwareUpgrade"
+	legacyUpgradeProp  = "/cosmos.upgrade.v1beta1.SoftwareUpgradeProposal"
+)
+
+// UpgradePlan is an x/upgrade plan. Info follows the cosmovisor convention:
+// {"binaries": {"linux/amd64": "https://...?checksum=sha256:<hex>"}}.
+type UpgradePlan struct {
+	Name       string `json:"name" yaml:"name"`
+	Height     string `json:"height" yaml:"height"`
+	Info       string `json:"info,omitempty" yaml:"info,omitempty"`
+	ProposalID string `json:"proposal_id,omitempty" yaml:"proposal_id,omitempty"`
+}
+
+func (p UpgradePlan) height() int64 {
+	h, _ := strconv.ParseInt(p.Height, 10, 64)
+	return h
+}
+
+// UpgradeStatus is the result of upgrade status.
+type UpgradeStatus struct {
+	Node     string            `json:"node" yaml:"node"`
+	Height   int64             `json:"height" yaml:"height"`
+	Current  string            `json:"current" yaml:"current"`
+	Upgrades []UpgradeProgress `json:"upgrades" yaml:"upgrades"`
+}
+
+type UpgradeProgress struct {
+	UpgradePlan `yaml:",inline"`
+	Status      string `json:"status" yaml:"status"`
+	Prepared    bool   `json:"prepared" yaml:"prepared"`
+}
+
+func (s UpgradeStatus) renderTable(w io.Writer) {
+	fmt.Fprintf(w, "Node:\t%s\n", s.Node)
+	fmt.Fprintf(w, "Height:\t%d\n", s.Height)
+	fmt.Fprintf(w, "Binary:\t%s\n", s.Current)
+	fmt.Fprintln(w)
+	if len(s.Upgrades) == 0 {
+		fmt.Fprintln(w, "No passed software-upgrade proposals.")
+		return
+	}
+	fmt.Fprintln(w, "NAME\tHEIGHT\tPROPOSAL\tSTATUS\tPREPARED")
+	for _, u := range s.Upgrades {
+		prepared := "no"
+		if u.Prepared {
+			prepared = "yes"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", u.Name, u.Height, u.ProposalID, u.Status, prepared)
+	}
+}
+
+func upgradeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Prepare and switch to chain upgrades passed by governance",
+	}
+	cmd.AddCommand(upgradeStatusCmd(), upgradePrepareCmd())
+	return cmd
+}
+
+func upgradeStatusCmd() *cobra.Command {
+	var mynode string
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show the passed upgrades and whether their binaries are prepared",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			status, err := upgradeStatusLogic(mynode)
+			if err != nil {
+				return err
+			}
+			return render(status)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	return cmd
+}
+
+// upgradePrepareOptions override what the plan info says.
+type upgradePrepareOptions struct {
+	URL          string
+	SHA256       string
+	Switch       bool
+	PollInterval time.Duration
+}
+
+func upgradePrepareCmd() *cobra.Command {
+	var mynode string
+	var opts upgradePrepareOptions
+	cmd := &cobra.Command{
+		Use:   "prepare <name>",
+		Short: "Download and verify the binary of a passed upgrade",
+		Long: `Downloads the binary of a passed software upgrade into
+<mynode>/cosmovisor/upgrades/<name>/bin and verifies its SHA-256 checksum.
+The download URL and checksum come from the plan info, or from --url and
+--sha256.
+
+With --switch the command keeps running until the node halts at the upgrade
+height, then restarts the node container on the new binary.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.PollInterval <= 0 {
+				return &usageError{Err: fmt.Errorf("--poll-interval must be positive")}
+			}
+			return upgradePrepareLogic(mynode, args[0], opts)
+		},
+	}
+	cmd.Flags().StringVar(&mynode, "mynode", "", "Please enter your node name")
+	requireNode(cmd)
+	cmd.Flags().StringVar(&opts.URL, "url", "", "Download the binary from this URL instead of the plan info")
+	cmd.Flags().StringVar(&opts.SHA256, "sha256", "", "Expected SHA-256 of the download")
+	cmd.Flags().BoolVar(&opts.Switch, "switch", false, "Wait for the upgrade height and restart the node on the new binary")
+	cmd.Flags().DurationVar(&opts.PollInterval, "poll-interval", 10*time.Second, "How often to check for the upgrade halt with --switch")
+	return cmd
+}
+
+func nodeRPC(mynode string) (string, error) {
+	envPath := filepath.Join(mynode, ".env")
+	env, err := godotenv.Read(envPath)
+	if err != nil {
+		return "", envFileError(envPath, err)
+	}
+	if env["RPC_PORT"] == "" {
+		return "", &ConfigError{Msg: "RPC_PORT is not set in " + envPath, Hint: "run 'port-set --mynode " + mynode + "' to regenerate the node .env"}
+	}
+	return "tcp://localhost:" + env["RPC_PORT"], nil
+}
+
+func upgradeStatusLogic(mynode string) (UpgradeStatus, error) {
+	status := UpgradeStatus{Node: mynode, Current: currentUpgrade(mynode)}
+	node, err := nodeRPC(mynode)
+	if err != nil {
+		return status, err
+	}
+	if height, _, err := pollNodeHeight(node); err == nil {
+		status.Height = height
+	} else {
+		log.Warnf("⚠️ Could not get the node height: %v", err)
+	}
+	plans, err := passedUpgradePlans(node)
+	if err != nil {
+		return status, err
+	}
+	for _, plan := range plans {
+		progress := UpgradeProgress{UpgradePlan: plan, Prepared: upgradePrepared(mynode, plan.Name)}
+		switch {
+		case status.Current == plan.Name:
+			progress.Status = "active"
+		case status.Height > 0 && status.Height >= plan.height():
+			progress.Status = "passed height"
+		default:
+			progress.Status = "scheduled"
+		}
+		status.Upgrades = append(status.Upgrades, progress)
+	}
+	return status, nil
+}
+
+// passedUpgradePlans returns the plans of the passed software-upgrade
+// proposals, from gov v1 messages as well as legacy proposal content.
+func passedUpgradePlans(node string) ([]UpgradePlan, error) {
+	type planHolder struct {
+		Type    string          `json:"@type"`
+		Plan    *UpgradePlan    `json:"plan"`
+		Content json.RawMessage `json:"content"`
+	}
+	var plans []UpgradePlan
+	err := queryAllPages("governance proposals", func(data json.RawMessage) (int, error) {
+		var resp struct {
+			Proposals []struct {
+				ID         string       `json:"id"`
+				ProposalID string       `json:"proposal_id"`
+				Status     string       `json:"status"`
+				Messages   []planHolder `json:"messages"`
+				Content    *planHolder  `json:"content"`
+			} `json:"proposals"`
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			return 0, err
+		}
+		for _, p := range resp.Proposals {
+			if p.Status != "PROPOSAL_STATUS_PASSED" {
+				continue
+			}
+			id := p.ID
+			if id == "" {
+				id = p.ProposalID
+			}
+			holders := p.Messages
+			if p.Content != nil {
+				holders = append(holders, *p.Content)
+			}
+			for _, h := range holders {
+				// MsgExecLegacyContent wraps the legacy proposal one level down.
+				if h.Plan == nil && len(h.Content) > 0 {
+					var inner planHolder
+					if json.Unmarshal(h.Content, &inner) == nil {
+						h = inner
+					}
+				}
+				if h.Plan == nil || (h.Type != msgSoftwareUpgrade && h.Type != legacyUpgradeProp) {
+					continue
+				}
+				plan := *h.Plan
+				plan.ProposalID = id
+				plans = append(plans, plan)
+			}
+		}
+		return len(resp.Proposals), nil
+	}, "query", "gov", "proposals", "--status", "passed", "--node", node, "-o", "json")
+	if err != nil {
+		if strings.Contains(err.Error(), "no proposals found") {
+			return plans, nil
+		}
+		return nil, err
+	}
+	return plans, nil
+}
+
+// safePathElement reports whether s can be used as a single directory name:
+// no path separators and not "." or "..".
+func safePathElement(s string) bool {
+	return s != "" && !strings.ContainsAny(s, `/\`) && s != "." && s != ".."
+}
+
+func upgradeDir(mynode, name string) string {
+	return filepath.Join(mynode, cosmovisorDir, "upgrades", name)
+}
+
+func upgradePrepared(mynode, name string) bool {
+	return exists(filepath.Join(upgradeDir(mynode, name), "bin", chainBinaryName))
+}
+
+// currentUpgrade returns the upgrade the node runs, or "genesis".
+func currentUpgrade(mynode string) string {
+	target, err := os.Readlink(filepath.Join(mynode, cosmovisorDir, "current"))
+	if err != nil {
+		return "genesis"
+	}
+	return path.Base(filepath.ToSlash(target))
+}
+
+// nodeBinary is the binary start-node runs inside the container, relative to
+// the container's working directory.
+func nodeBinary(mynode string) string {
+	if currentUpgrade(mynode) == "genesis" || !exists(filepath.Join(mynode, cosmovisorDir, "current", "bin", chainBinaryName)) {
+		return containerMrmintd
+	}
+	return "./" + path.Join(filepath.ToSlash(mynode), cosmovisorDir, "current", "bin", chainBinaryName)
+}
+
+func upgradePrepareLogic(mynode, name string, opts upgradePrepareOptions) error {
+	if !safePathElement(name) {
+		return &usageError{Err: fmt.Errorf("invalid upgrade name %q", name)}
+	}
+	node, err := nodeRPC(mynode)
+	if err != nil {
+		return err
+	}
+	plan := UpgradePlan{Name: name}
+	plans, err := passedUpgradePlans(node)
+	if err != nil {
+		if opts.URL == "" {
+			return err
+		}
+		log.Warnf("⚠️ Could not look up the upgrade plan: %v", err)
+	}
+	found := false
+	for _, p := range plans {
+		if p.Name == name {
+			plan, found = p, true
+		}
+	}
+	if !found && opts.URL == "" {
+		return &ConfigError{Msg: "no passed software-upgrade proposal named " + name, Hint: "check the name with 'upgrade status', or pass the binary with --url and --sha256"}
+	}
+
+	if err := ensureGenesisLayout(mynode); err != nil {
+		return err
+	}
+	if upgradePrepared(mynode, name) {
+		log.Infof("✅ Upgrade %s is already prepared.", name)
+	} else if err := downloadUpgradeBinary(mynode, plan, opts); err != nil {
+		return err
+	}
+
+	planJSON, _ := json.MarshalIndent(plan, "", "  ")
+	if err := os.WriteFile(filepath.Join(upgradeDir(mynode, name), upgradePlanFile), planJSON, 0644); err != nil {
+		return &RuntimeError{Msg: "failed to write the upgrade plan", Err: err}
+	}
+
+	if !opts.Switch {
+		if found {
+			log.Infof("🗓️  Upgrade %s happens at height %s. Run 'upgrade prepare %s --mynode %s --switch' to switch over automatically.", name, plan.Height, name, mynode)
+		}
+		return nil
+	}
+	if err := waitForUpgradeHalt(mynode, node, plan, opts.PollInterval); err != nil {
+		return err
+	}
+	return switchUpgrade(mynode, name)
+}
+
+// ensureGenesisLayout creates cosmovisor/genesis/bin with the installed
+// binary, as cosmovisor expects.
+func ensureGenesisLayout(mynode string) error {
+	binDir := filepath.Join(mynode, cosmovisorDir, "genesis", "bin")
+	if err := os.MkdirAll(binDir, 0755); err != nil {
+		return &RuntimeError{Msg: "failed to create " + binDir, Err: err}
+	}
+	target := filepath.Join(binDir, chainBinaryName)
+	if exists(target) || !exists(Mrmintd) {
+		return nil
+	}
+	data, err := os.ReadFile(Mrmintd)
+	if err != nil {
+		return &RuntimeError{Msg: "failed to copy the genesis binary", Err: err}
+	}
+	if err := os.WriteFile(target, data, 0755); err != nil {
+		return &RuntimeError{Msg: "failed to copy the genesis binary", Err: err}
+	}
+	return nil
+}
+
+// upgradeBinaryURL picks the binary for the container platform from the plan
+// info and splits off a go-getter style ?checksum=sha256:<hex>.
+func upgradeBinaryURL(plan UpgradePlan) (string, string, error) {
+	var info struct {
+		Binaries map[string]string `json:"binaries"`
+	}
+	if err := json.Unmarshal([]byte(plan.Info), &info); err != nil || len(info.Binaries) == 0 {
+		return "", "", fmt.Errorf("the plan info of %s lists no binaries", plan.Name)
+	}
+	// The node runs in a Linux container whatever the host OS.
+	raw, ok := info.Binaries["linux/"+runtime.GOARCH]
+	if !ok {
+		raw, ok = info.Binaries["any"]
+	}
+	if !ok {
+		return "", "", fmt.Errorf("the plan info of %s has no binary for linux/%s", plan.Name, runtime.GOARCH)
+	}
+	return splitChecksum(raw)
+}
+
+func splitChecksum(raw string) (string, string, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", err
+	}
+	q := u.Query()
+	checksum := q.Get("checksum")
+	q.Del("checksum")
+	u.RawQuery = q.Encode()
+	sum, ok := strings.CutPrefix(checksum, "sha256:")
+	if checksum != "" && !ok {
+		return "", "", fmt.Errorf("unsupported checksum %q, expected sha256:<hex>", checksum)
+	}
+	return u.String(), sum, nil
+}
+
+func downloadUpgradeBinary(mynode string, plan UpgradePlan, opts upgradePrepareOptions) error {
+	rawURL, sum := opts.URL, opts.SHA256
+	if rawURL == "" {
+		var err error
+		if rawURL, sum, err = upgradeBinaryURL(plan); err != nil {
+			return &ConfigError{Msg: "cannot find the upgrade binary", Err: err, Hint: "pass the binary with --url and --sha256"}
+		}
+	} else if sum == "" {
+		var err error
+		if rawURL, sum, err = splitChecksum(rawURL); err != nil {
+			return &usageError{Err: err}
+		}
+	}
+	if opts.SHA256 != "" {
+		sum = opts.SHA256
+	}
+	if sum == "" {
+		return &ConfigError{Msg: "no SHA-256 checksum for the " + plan.Name + " binary", Hint: "pass it with --sha256; unverified binaries are not installed"}
+	}
+
+	if !safePathElement(plan.Name) {
+		return &ConfigError{Msg: fmt.Sprintf("invalid upgrade name %q", plan.Name)}
+	}
+	dir := upgradeDir(mynode, plan.Name)
+	if err := os.MkdirAll(filepath.Join(dir, "bin"), 0755); err != nil {
+		return &RuntimeError{Msg: "failed to create " + dir, Err: err}
+	}
+	u, _ := url.Parse(rawURL)
+	name := path.Base(u.Path)
+	if !safePathElement(name) {
+		name = chainBinaryName + ".download"
+	}
+	download := filepath.Join(dir, name)
+
+	log.Infof("⬇️  Downloading the %s binary from %s", plan.Name, rawURL)
+	if err := downloadResumable(rawURL, download+".part"); err != nil {
+		return err
+	}
+	if err := verifySHA256(download+".part", sum); err != nil {
+		os.Remove(download + ".part")
+		return &RuntimeError{Msg: "upgrade binary checksum verification failed", Err: err, Hint: "check the URL and checksum of the upgrade"}
+	}
+
+	binary := filepath.Join(dir, "bin", chainBinaryName)
+	if isTarArchive(download) {
+		// Release archives hold the binary, either at the top or in bin/.
+		if err := os.Rename(download+".part", download); err != nil {
+			return &RuntimeError{Msg: "failed to move the upgrade archive", Err: err}
+		}
+		if err := extractSnapshot(download, dir); err != nil {
+			return &RuntimeError{Msg: "failed to extract the upgrade archive", Err: err}
+		}
+		os.Remove(download)
+		if !exists(binary) && exists(filepath.Join(dir, chainBinaryName)) {
+			if err := os.Rename(filepath.Join(dir, chainBinaryName), binary); err != nil {
+				return &RuntimeError{Msg: "failed to install the upgrade binary", Err: err}
+			}
+		}
+		if !exists(binary) {
+			return &RuntimeError{Msg: "the upgrade archive has no " + chainBinaryName + " binary"}
+		}
+	} else if err := os.Rename(download+".part", binary); err != nil {
+		return &RuntimeError{Msg: "failed to install the upgrade binary", Err: err}
+	}
+	if err := os.Chmod(binary, 0755); err != nil {
+		return &RuntimeError{Msg: "failed to make the upgrade binary executable", Err: err}
+	}
+	log.Infof("✅ Upgrade binary verified and installed at %s", binary)
+	return nil
+}
+
+// waitForUpgradeHalt waits until the node stops at the upgrade height, which
+// x/upgrade marks by writing data/upgrade-info.json before halting.
+func waitForUpgradeHalt(mynode, node string, plan UpgradePlan, interval time.Duration) error {
+	infoPath := filepath.Join(mynode, "data", upgradeInfoFile)
+	for {
+		data, err := os.ReadFile(infoPath)
+		if err == nil {
+			var info struct {
+				Name   string `json:"name"`
+				Height int64  `json:"height"`
+			}
+			if json.Unmarshal(data, &info) == nil && info.Name == plan.Name {
+				log.Infof("🛑 The node halted for upgrade %s at height %d.", info.Name, info.Height)
+				return nil
+			}
+		} else if !errors.Is(err, fs.ErrNotExist) {
+			return &RuntimeError{Msg: "failed to read " + infoPath, Err: err}
+		}
+
+		if height, _, err := pollNodeHeight(node); err == nil {
+			log.Infof("⏳ Height %d, upgrade %s at height %s", height, plan.Name, plan.Height)
+		} else {
+			log.Infof("⏳ Waiting for the node to halt for upgrade %s...", plan.Name)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// switchUpgrade points cosmovisor/current at the upgrade and restarts the
+// node container, which start-node runs on the current binary.
+func switchUpgrade(mynode, name string) error {
+	if !safePathElement(name) {
+		return &ConfigError{Msg: fmt.Sprintf("invalid upgrade name %q", name)}
+	}
+	if !upgradePrepared(mynode, name) {
+		return &RuntimeError{Msg: "upgrade " + name + " is not prepared", Hint: "run 'upgrade prepare " + name + " --mynode " + mynode + "'"}
+	}
+	current := filepath.Join(mynode, cosmovisorDir, "current")
+	tmp := current + ".new"
+	os.Remove(tmp)
+	// A relative link resolves both on the host and in the container.
+	if err := os.Symlink(filepath.Join("upgrades", name), tmp); err != nil {
+		return &RuntimeError{Msg: "failed to switch to upgrade " + name, Err: err}
+	}
+	if err := os.Rename(tmp, current); err != nil {
+		return &RuntimeError{Msg: "failed to switch to upgrade " + name, Err: err}
+	}
+	log.Infof("🔀 Switched %s to the %s binary, restarting the node.", mynode, name)
+
+	if err := recreateNodeContainer(mynode); err != nil {
+		return &RuntimeError{Msg: "failed to restart the node on the upgrade binary", Err: err, Hint: "start it with 'start-node --mynode " + mynode + "'"}
+	}
+	return nil
+}
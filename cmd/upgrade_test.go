@@ -0,0 +1,86 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+// proposalPages serves total passed proposals, every third one a software
+// upgrade, in pages of the requested size.
+func proposalPages(total int) func(args []string) (string, error) {
+	return func(args []string) (string, error) {
+		if flagValue(args, "--status") != "passed" {
+			return "", fmt.Errorf("expected --status passed")
+		}
+		offset, _ := strconv.Atoi(flagValue(args, "--offset"))
+		limit, _ := strconv.Atoi(flagValue(args, "--limit"))
+		if offset >= total && total > 0 {
+			return "Error: no proposals found", fmt.Errorf("exit status 1")
+		}
+		var page struct {
+			Proposals  []json.RawMessage `json:"proposals"`
+			Pagination struct {
+				NextKey *string `json:"next_key"`
+			} `json:"pagination"`
+		}
+		for i := offset; i < total && i < offset+limit; i++ {
+			msgType := "/cosmos.bank.v1beta1.MsgSend"
+			if i%3 == 0 {
+				msgType = msgSoftwareUpgrade
+			}
+			page.Proposals = append(page.Proposals, json.RawMessage(fmt.Sprintf(
+				`{"id":"%d","status":"PROPOSAL_STATUS_PASSED","messages":[{"@type":%q,"plan":{"name":"v%d","height":"%d"}}]}`,
+				i, msgType, i, 1000+i)))
+		}
+		if offset+limit < total {
+			key := "AAAAAAAAAAE="
+			page.Pagination.NextKey = &key
+		}
+		data, err := json.Marshal(page)
+		return string(data), err
+	}
+}
+
+func TestPassedUpgradePlansPaginates(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		wantPlans int
+		wantCalls int
+	}{
+		{name: "one page", total: 10, wantPlans: 4, wantCalls: 1},
+		{name: "several pages", total: 2*queryPageLimit + 5, wantPlans: 69, wantCalls: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeTransport{run: proposalPages(tt.total)}
+			useTransport(t, fake)
+
+			plans, err := passedUpgradePlans("http://node:26657")
+			if err != nil {
+				t.Fatalf("passedUpgradePlans: %v", err)
+			}
+			if len(plans) != tt.wantPlans {
+				t.Errorf("got %d plans, want %d", len(plans), tt.wantPlans)
+			}
+			if len(fake.calls) != tt.wantCalls {
+				t.Errorf("got %d queries, want %d", len(fake.calls), tt.wantCalls)
+			}
+			if len(plans) > 0 && (plans[0].Name != "v0" || plans[0].ProposalID != "0") {
+				t.Errorf("first plan = %+v", plans[0])
+			}
+		})
+	}
+}
+
+func TestPassedUpgradePlansNoProposals(t *testing.T) {
+	useTransport(t, &fakeTransport{run: func(args []string) (string, error) {
+		return "Error: no proposals found", fmt.Errorf("exit status 1")
+	}})
+	plans, err := passedUpgradePlans("http://node:26657")
+	if err != nil || len(plans) != 0 {
+		t.Fatalf("got %v, %v; want no plans and no error", plans, err)
+	}
+}
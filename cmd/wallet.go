@@ -1,30 +1,11 @@
 package main
 
 import (
-	"encoding/hex"
-	"fmt"
-	"strings"
-
-	"github.com/btcsuite/btcutil/bech32"
+	"github.com/yourname/ethermint-validator-cli/address"
 )
 
+// Bech32ToEthAddress converts an ethm1 (or ethmvaloper1) address to its
+// EIP-55 checksummed 0x form.
 func Bech32ToEthAddress(bech32Addr string) (string, error) {
-
-	hrp, data, err := bech32.Decode(bech32Addr)
-	if err != nil {
-		return "", fmt.Errorf("failed to decode bech32 address: %w", err)
-	}
-	fmt.Println("HRP:", hrp)
-
-	// Convert from 5-bit groups back to 8-bit bytes
-	decoded, err := bech32.ConvertBits(data, 5, 8, false)
-	if err != nil {
-		return "", fmt.Errorf("failed to convert bits: %w", err)
-	}
-	if len(decoded) != 20 {
-		return "", fmt.Errorf("expected 20 bytes, got %d", len(decoded))
-	}
-
-	ethAddress := "0x" + strings.ToLower(hex.EncodeToString(decoded))
-	return ethAddress, nil
+	return address.BechToHex(bech32Addr)
 }
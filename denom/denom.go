@@ -0,0 +1,226 @@
+// Package denom parses and formats coin amounts exactly, using big.Int
+// arithmetic and the chain's denomination metadata.
+//
+// Amounts are kept in base units (the on-chain denom). Human input such as
+// "12.5MNT" or "12500000000000000000mnt" is converted using the exponent of
+// the unit it names.
+package denom
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"strings"
+)
+
+// Unit is one denomination of a coin, e.g. "mnt" with exponent 18 means
+// 1 mnt = 10^18 base units.
+type Unit struct {
+	Denom    string   `json:"denom"`
+	Exponent int      `json:"exponent"`
+	Aliases  []string `json:"aliases"`
+}
+
+// Metadata describes a coin: its on-chain base denom, the unit used for
+// display and every unit accepted as input.
+type Metadata struct {
+	Base    string `json:"base"`
+	Display string `json:"display"`
+	Symbol  string `json:"symbol"`
+	Units   []Unit `json:"denom_units"`
+}
+
+// Coin is an exact amount in base units.
+type Coin struct {
+	Amount *big.Int
+	Denom  string
+}
+
+// Default is the MNT metadata used when the chain does not publish any.
+// The on-chain denom "mnt" is the base unit, as in the gas prices and the
+// amounts ethermintd prints; the display unit "MNT" has 18 decimals.
+var Default = Metadata{
+	Base:    "mnt",
+	Display: "MNT",
+	Symbol:  "MNT",
+	Units: []Unit{
+		{Denom: "mnt", Exponent: 0, Aliases: []string{"amnt"}},
+		{Denom: "MNT", Exponent: 18},
+	},
+}
+
+var amountRe = regexp.MustCompile(`^([0-9]+(?:\.[0-9]*)?|\.[0-9]+)\s*([a-zA-Z][a-zA-Z0-9/:._-]*)$`)
+
+// String returns the coin in the form expected by ethermintd, e.g. "1000mnt".
+func (c Coin) String() string {
+	return c.Amount.String() + c.Denom
+}
+
+// Parse converts human input into a Coin in base units. The denom is
+// matched against the metadata units and their aliases, ignoring case unless
+// that makes it ambiguous.
+func Parse(input string, md Metadata) (Coin, error) {
+	input = strings.TrimSpace(input)
+	m := amountRe.FindStringSubmatch(input)
+	if m == nil {
+		return Coin{}, fmt.Errorf("invalid amount %q: expected a number followed by a denom, e.g. 12.5%s", input, md.displayName())
+	}
+
+	unit, err := md.Unit(m[2])
+	if err != nil {
+		return Coin{}, err
+	}
+
+	amount, err := scale(m[1], unit.Exponent)
+	if err != nil {
+		return Coin{}, fmt.Errorf("invalid amount %q: %w", input, err)
+	}
+	return Coin{Amount: amount, Denom: md.Base}, nil
+}
+
+// Unit returns the unit named by denom, or an error listing the valid
+// denoms. An exact match wins; otherwise case is ignored, unless units that
+// only differ in case (such as mnt and MNT) would make the input ambiguous.
+func (md Metadata) Unit(denom string) (Unit, error) {
+	var matches []Unit
+	for _, u := range md.Units {
+		for _, name := range append([]string{u.Denom}, u.Aliases...) {
+			if name == denom {
+				return u, nil
+			}
+			if strings.EqualFold(name, denom) && (len(matches) == 0 || matches[0].Exponent != u.Exponent) {
+				matches = append(matches, u)
+			}
+		}
+	}
+	switch {
+	case len(matches) == 1:
+		return matches[0], nil
+	case len(matches) > 1:
+		return Unit{}, fmt.Errorf("ambiguous denom %q: denoms are case-sensitive, use one of %s", denom, strings.Join(md.denoms(), ", "))
+	case strings.EqualFold(md.Base, denom):
+		return Unit{Denom: md.Base, Exponent: 0}, nil
+	}
+	return Unit{}, fmt.Errorf("unknown denom %q: must be one of %s", denom, strings.Join(md.denoms(), ", "))
+}
+
+// ValidateDenom reports whether denom is known by the metadata.
+func (md Metadata) ValidateDenom(denom string) error {
+	_, err := md.Unit(denom)
+	return err
+}
+
+// DisplayExponent returns the exponent of the display unit.
+func (md Metadata) DisplayExponent() int {
+	if u, err := md.Unit(md.Display); err == nil {
+		return u.Exponent
+	}
+	return 0
+}
+
+// FromDisplay converts a whole number of display units to base units.
+func (md Metadata) FromDisplay(whole int64) *big.Int {
+	exp := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(md.DisplayExponent())), nil)
+	return exp.Mul(exp, big.NewInt(whole))
+}
+
+// Format renders a base unit amount in display units with exact decimals,
+// e.g. "12.5 MNT".
+func (md Metadata) Format(amount *big.Int) string {
+	return Format(amount, md.DisplayExponent()) + " " + md.displayName()
+}
+
+// FormatString is Format for an amount given as a decimal string of base
+// units; invalid input is returned unchanged with the base denom.
+func (md Metadata) FormatString(amount string) string {
+	value, ok := new(big.Int).SetString(strings.TrimSpace(amount), 10)
+	if !ok {
+		return amount + md.Base
+	}
+	return md.Format(value)
+}
+
+// Format renders amount divided by 10^exponent without losing precision,
+// trimming trailing zeros from the fraction.
+func Format(amount *big.Int, exponent int) string {
+	if amount == nil {
+		return "0"
+	}
+	sign := ""
+	abs := new(big.Int).Set(amount)
+	if abs.Sign() < 0 {
+		sign = "-"
+		abs.Neg(abs)
+	}
+	if exponent <= 0 {
+		return sign + abs.String()
+	}
+
+	digits := abs.String()
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+	whole := digits[:len(digits)-exponent]
+	frac := strings.TrimRight(digits[len(digits)-exponent:], "0")
+	if frac == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + frac
+}
+
+// ParseMetadata picks the metadata for base out of the JSON printed by
+// "ethermintd query bank denom-metadata --output json". It returns false
+// when the chain does not publish metadata for that denom.
+func ParseMetadata(data []byte, base string) (Metadata, bool, error) {
+	var resp struct {
+		Metadatas []Metadata `json:"metadatas"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return Metadata{}, false, fmt.Errorf("failed to parse denom metadata: %w", err)
+	}
+	for _, md := range resp.Metadatas {
+		if md.Base == base || md.Display == base {
+			return md, true, nil
+		}
+		for _, u := range md.Units {
+			if u.Denom == base {
+				return md, true, nil
+			}
+		}
+	}
+	return Metadata{}, false, nil
+}
+
+func scale(value string, exponent int) (*big.Int, error) {
+	whole, frac, _ := strings.Cut(value, ".")
+	if len(frac) > exponent {
+		if strings.TrimRight(frac[exponent:], "0") != "" {
+			return nil, fmt.Errorf("more than %d decimal places", exponent)
+		}
+		frac = frac[:exponent]
+	}
+	digits := whole + frac + strings.Repeat("0", exponent-len(frac))
+	amount, ok := new(big.Int).SetString(strings.TrimLeft(digits, "0")+"0", 10)
+	if !ok {
+		return nil, fmt.Errorf("not a number")
+	}
+	// The appended "0" keeps SetString happy for all-zero input; undo it.
+	return amount.Div(amount, big.NewInt(10)), nil
+}
+
+func (md Metadata) displayName() string {
+	if md.Symbol != "" {
+		return md.Symbol
+	}
+	return md.Display
+}
+
+func (md Metadata) denoms() []string {
+	var denoms []string
+	for _, u := range md.Units {
+		denoms = append(denoms, u.Denom)
+		denoms = append(denoms, u.Aliases...)
+	}
+	return denoms
+}
@@ -0,0 +1,194 @@
+package denom
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func bigInt(t *testing.T, s string) *big.Int {
+	t.Helper()
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		t.Fatalf("bad test number %q", s)
+	}
+	return v
+}
+
+// stake is a chain with a micro base unit and an alias on the display unit.
+var stake = Metadata{
+	Base:    "ustake",
+	Display: "stake",
+	Units: []Unit{
+		{Denom: "ustake", Exponent: 0},
+		{Denom: "stake", Exponent: 6, Aliases: []string{"STK"}},
+	},
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		md    Metadata
+		want  string
+	}{
+		{name: "whole display units", input: "1MNT", md: Default, want: "1000000000000000000"},
+		{name: "decimals", input: "12.5MNT", md: Default, want: "12500000000000000000"},
+		{name: "space", input: " 12.5 MNT ", md: Default, want: "12500000000000000000"},
+		{name: "base unit", input: "12500000000000000000mnt", md: Default, want: "12500000000000000000"},
+		{name: "bare mnt stays in base units", input: "1mnt", md: Default, want: "1"},
+		{name: "base unit alias", input: "12500000000000000000amnt", md: Default, want: "12500000000000000000"},
+		{name: "leading dot", input: ".5MNT", md: Default, want: "500000000000000000"},
+		{name: "trailing dot", input: "1.MNT", md: Default, want: "1000000000000000000"},
+		{name: "smallest fraction", input: "0.000000000000000001MNT", md: Default, want: "1"},
+		{name: "extra zero decimals", input: "1.000000000000000000000MNT", md: Default, want: "1000000000000000000"},
+		{name: "zero", input: "0MNT", md: Default, want: "0"},
+		{name: "leading zeros", input: "007MNT", md: Default, want: "7000000000000000000"},
+		{name: "beyond int64", input: "123456789012345678901234567890MNT", md: Default, want: "123456789012345678901234567890000000000000000000"},
+		{name: "case-insensitive alias", input: "5AMNT", md: Default, want: "5"},
+		{name: "six decimals", input: "2.5stake", md: stake, want: "2500000"},
+		{name: "alias", input: "2.5stk", md: stake, want: "2500000"},
+		{name: "micro unit", input: "3ustake", md: stake, want: "3"},
+		{name: "base without a unit entry", input: "5abc", md: Metadata{Base: "abc", Display: "abc"}, want: "5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coin, err := Parse(tt.input, tt.md)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+			if coin.Amount.Cmp(bigInt(t, tt.want)) != 0 {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, coin.Amount, tt.want)
+			}
+			if coin.Denom != tt.md.Base {
+				t.Errorf("Parse(%q) denom = %s, want the base denom %s", tt.input, coin.Denom, tt.md.Base)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "empty", input: "", wantErr: "expected a number followed by a denom"},
+		{name: "no amount", input: "MNT", wantErr: "expected a number followed by a denom"},
+		{name: "no denom", input: "12", wantErr: "expected a number followed by a denom"},
+		{name: "negative", input: "-1MNT", wantErr: "expected a number followed by a denom"},
+		{name: "exponent notation", input: "1e18amnt", wantErr: "unknown denom"},
+		{name: "thousands separator", input: "1,000MNT", wantErr: "expected a number followed by a denom"},
+		{name: "two dots", input: "1.2.3MNT", wantErr: "expected a number followed by a denom"},
+		{name: "unknown denom", input: "5btc", wantErr: `unknown denom "btc": must be one of mnt, amnt, MNT`},
+		{name: "too many decimals", input: "0.0000000000000000001MNT", wantErr: "more than 18 decimal places"},
+		{name: "fraction of the base unit", input: "1.5mnt", wantErr: "more than 0 decimal places"},
+		{name: "mixed case", input: "1Mnt", wantErr: `ambiguous denom "Mnt"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			coin, err := Parse(tt.input, Default)
+			if err == nil {
+				t.Fatalf("Parse(%q) = %s, want an error", tt.input, coin)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		amount   string
+		exponent int
+		want     string
+	}{
+		{amount: "0", exponent: 18, want: "0"},
+		{amount: "1", exponent: 18, want: "0.000000000000000001"},
+		{amount: "1000000000000000000", exponent: 18, want: "1"},
+		{amount: "12500000000000000000", exponent: 18, want: "12.5"},
+		{amount: "-1500000000000000000", exponent: 18, want: "-1.5"},
+		{amount: "2500000", exponent: 6, want: "2.5"},
+		{amount: "123", exponent: 0, want: "123"},
+		{amount: "123456789012345678901234567890", exponent: 18, want: "123456789012.34567890123456789"},
+	}
+	for _, tt := range tests {
+		if got := Format(bigInt(t, tt.amount), tt.exponent); got != tt.want {
+			t.Errorf("Format(%s, %d) = %s, want %s", tt.amount, tt.exponent, got, tt.want)
+		}
+	}
+	if got := Format(nil, 18); got != "0" {
+		t.Errorf("Format(nil) = %s, want 0", got)
+	}
+}
+
+func TestParseFormatRoundTrip(t *testing.T) {
+	for _, input := range []string{"0.000000000000000001", "1", "12.5", "99999999.123456789012345678"} {
+		coin, err := Parse(input+"MNT", Default)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", input, err)
+		}
+		if got := Default.Format(coin.Amount); got != input+" MNT" {
+			t.Errorf("Format(Parse(%q)) = %s", input, got)
+		}
+	}
+}
+
+func TestMetadataHelpers(t *testing.T) {
+	if got := Default.DisplayExponent(); got != 18 {
+		t.Errorf("DisplayExponent() = %d, want 18", got)
+	}
+	if got := Default.FromDisplay(3); got.Cmp(bigInt(t, "3000000000000000000")) != 0 {
+		t.Errorf("FromDisplay(3) = %s", got)
+	}
+	if got := stake.FormatString("2500000"); got != "2.5 stake" {
+		t.Errorf("FormatString = %s, want 2.5 stake", got)
+	}
+	if got := Default.FormatString("not a number"); got != "not a numbermnt" {
+		t.Errorf("FormatString of invalid input = %s", got)
+	}
+	if err := stake.ValidateDenom("STK"); err != nil {
+		t.Errorf("ValidateDenom(STK) = %v", err)
+	}
+	if err := stake.ValidateDenom("atom"); err == nil {
+		t.Errorf("ValidateDenom(atom) succeeded")
+	}
+}
+
+func TestParseMetadata(t *testing.T) {
+	data := []byte(`{"metadatas":[
+		{"base":"uatom","display":"atom","denom_units":[{"denom":"uatom","exponent":0},{"denom":"matom","exponent":3},{"denom":"atom","exponent":6}]},
+		{"base":"ustake","display":"stake","symbol":"STK","denom_units":[{"denom":"ustake","exponent":0},{"denom":"stake","exponent":6,"aliases":["STK"]}]}
+	]}`)
+
+	tests := []struct {
+		name   string
+		base   string
+		found  bool
+		symbol string
+	}{
+		{name: "by base", base: "ustake", found: true, symbol: "STK"},
+		{name: "by display", base: "stake", found: true, symbol: "STK"},
+		{name: "by unit", base: "matom", found: true},
+		{name: "missing", base: "mnt", found: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md, found, err := ParseMetadata(data, tt.base)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found != tt.found {
+				t.Fatalf("found = %v, want %v", found, tt.found)
+			}
+			if md.Symbol != tt.symbol {
+				t.Errorf("symbol = %q, want %q", md.Symbol, tt.symbol)
+			}
+		})
+	}
+
+	if _, _, err := ParseMetadata([]byte("not json"), "mnt"); err == nil {
+		t.Errorf("ParseMetadata accepted invalid JSON")
+	}
+}